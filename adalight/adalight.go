@@ -0,0 +1,44 @@
+// Package adalight implements the Adalight serial protocol, which is used by
+// many ambilight receivers (for example Hyperion and Prismatik setups).
+package adalight
+
+import (
+	"io"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// AppendFrame appends an Adalight frame to buf and returns the resulting
+// slice. A frame consists of the "Ada" magic word, the number of LEDs minus one
+// (big endian), a checksum of that number, and the pixel data in the given
+// channel order (normally RGB). The frame must contain at least one LED.
+func AppendFrame(buf []byte, frame ledsgo.Strip, order ledsgo.ColorOrder) []byte {
+	n := len(frame) - 1
+	hi, lo := byte(n>>8), byte(n)
+	buf = append(buf, 'A', 'd', 'a', hi, lo, hi^lo^0x55)
+	return order.AppendStrip(buf, frame)
+}
+
+// Displayer is a ledsgo.Displayer that sends frames in the Adalight protocol,
+// usually over a serial port.
+type Displayer struct {
+	W     io.Writer
+	Order ledsgo.ColorOrder
+	buf   []byte
+}
+
+// NewDisplayer returns a new Displayer that writes RGB frames to w.
+func NewDisplayer(w io.Writer) *Displayer {
+	return &Displayer{W: w, Order: ledsgo.OrderRGB}
+}
+
+// Display writes a single frame. Empty frames are not sent, as they can't be
+// encoded in the protocol.
+func (d *Displayer) Display(frame ledsgo.Strip) error {
+	if len(frame) == 0 {
+		return nil
+	}
+	d.buf = AppendFrame(d.buf[:0], frame, d.Order)
+	_, err := d.W.Write(d.buf)
+	return err
+}
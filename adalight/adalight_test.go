@@ -0,0 +1,23 @@
+package adalight
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+)
+
+func TestAppendFrame(t *testing.T) {
+	frame := make(ledsgo.Strip, 300)
+	frame[0] = color.RGBA{R: 1, G: 2, B: 3}
+	buf := AppendFrame(nil, frame, ledsgo.OrderRGB)
+	// 299 = 0x012b, checksum = 0x01 ^ 0x2b ^ 0x55 = 0x7f
+	expected := []byte{'A', 'd', 'a', 0x01, 0x2b, 0x7f, 1, 2, 3}
+	if !bytes.Equal(buf[:len(expected)], expected) {
+		t.Errorf("unexpected frame start: %v", buf[:len(expected)])
+	}
+	if len(buf) != 6+300*3 {
+		t.Errorf("unexpected frame length: %d", len(buf))
+	}
+}
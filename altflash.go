@@ -0,0 +1,109 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+func init() {
+	RegisterEffect(EffectInfo{
+		Name:        "Police",
+		Description: "Alternating red and blue flashes",
+		Category:    "flash",
+		New:         func() Effect { return NewPoliceFlash() },
+	})
+}
+
+// AlternatingFlash is an Effect that flashes two zones of the strip in turn,
+// like the lights of emergency vehicles. Every period, the first zone flashes
+// a burst of flashes during the first half and the second zone during the
+// second half.
+//
+// Like a Strobe, the flashes are limited to a rate that is safe for people
+// with photosensitive epilepsy (see StrobeMinInterval) by lengthening the
+// period. Real police patterns flash much faster, which needs AllowUnsafeRate.
+type AlternatingFlash struct {
+	// Zones are the LEDs of the two zones. If both are empty, the first and
+	// second half of the frame are used. LEDs outside of the zones are not
+	// changed.
+	Zones [2]Segment
+
+	// Colors are the colors of the flashes of both zones.
+	Colors [2]color.RGBA
+
+	// Period is the time in which both zones flash once.
+	Period time.Duration
+
+	// Flashes is the number of flashes in every burst. If it is zero, a zone
+	// flashes once.
+	Flashes int
+
+	// Duty is the part of the time of every flash that the zone is lit, where
+	// 0 means never, 128 means half the time and 255 means the zone is lit
+	// during the whole burst.
+	Duty uint8
+
+	// AllowUnsafeRate removes the limit on the rate of the flashes, see
+	// Strobe.AllowUnsafeRate.
+	AllowUnsafeRate bool
+}
+
+// NewPoliceFlash returns an alternating red and blue pattern of the two
+// halves of the frame, with bursts of three flashes. At the safe rate, every
+// burst takes a second.
+func NewPoliceFlash() *AlternatingFlash {
+	return &AlternatingFlash{
+		Colors:  [2]color.RGBA{{255, 0, 0, 0}, {0, 0, 255, 0}},
+		Period:  time.Second,
+		Flashes: 3,
+		Duty:    160,
+	}
+}
+
+// Params implements ParamEffect.
+func (f *AlternatingFlash) Params() []Param {
+	return []Param{
+		{Name: "Flashes", Min: 1, Max: 8, Default: 3, Value: &f.Flashes},
+		{Name: "Duty", Min: 0, Max: 255, Default: 160, Value: &f.Duty},
+	}
+}
+
+// period returns the period, lengthened so that the flashes are at least
+// StrobeMinInterval apart unless that is overridden.
+func (f *AlternatingFlash) period(flashes int) time.Duration {
+	if f.AllowUnsafeRate {
+		return max(f.Period, time.Millisecond)
+	}
+	return max(f.Period, StrobeMinInterval*time.Duration(2*flashes))
+}
+
+// Render implements Effect.
+func (f *AlternatingFlash) Render(frame Strip, t time.Duration) {
+	zones := f.Zones
+	if zones == [2]Segment{} {
+		zones = [2]Segment{{0, len(frame) / 2}, {len(frame) / 2, len(frame) - len(frame)/2}}
+	}
+	flashes := max(f.Flashes, 1)
+	period := f.period(flashes)
+
+	// Find the zone that is flashing and the position within the current
+	// flash, as a .8 fixed-point fraction.
+	phase := t % period
+	if phase < 0 {
+		phase += period
+	}
+	active := int(phase * 2 / period)
+	slot := uint64(phase*2%period) * uint64(flashes) // position in the burst, in units of period
+	pos := slot % uint64(period) << 8 / uint64(period)
+	lit := pos*255 < uint64(f.Duty)<<8
+
+	for i, zone := range zones {
+		c := color.RGBA{}
+		if i == active && lit {
+			c = f.Colors[i]
+		}
+		start := min(max(zone.Start, 0), len(frame))
+		end := min(max(zone.Start+zone.Count, start), len(frame))
+		frame[start:end].FillSolid(c)
+	}
+}
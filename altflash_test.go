@@ -0,0 +1,97 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestAlternatingFlash(t *testing.T) {
+	red, blue := color.RGBA{R: 255}, color.RGBA{B: 255}
+	f := NewPoliceFlash()
+	f.AllowUnsafeRate = true
+	frame := make(Strip, 6)
+	zoneColors := func() (a, b color.RGBA) {
+		for _, c := range frame[1:3] {
+			if c != frame[0] {
+				t.Fatalf("expected the first zone to have one color, got %v", frame)
+			}
+		}
+		for _, c := range frame[4:] {
+			if c != frame[3] {
+				t.Fatalf("expected the second zone to have one color, got %v", frame)
+			}
+		}
+		return frame[0], frame[3]
+	}
+
+	// Three flashes of the first zone, then three of the second zone.
+	var flashes [2]int
+	var was [2]bool
+	for ts := time.Duration(0); ts < time.Second; ts += 10 * time.Millisecond {
+		f.Render(frame, ts)
+		a, b := zoneColors()
+		if a != (color.RGBA{}) && b != (color.RGBA{}) {
+			t.Fatalf("%v: expected only one zone to be lit, got %v", ts, frame)
+		}
+		if (a != (color.RGBA{}) && a != red) || (b != (color.RGBA{}) && b != blue) {
+			t.Fatalf("%v: unexpected colors %v", ts, frame)
+		}
+		if a == red && ts >= 500*time.Millisecond || b == blue && ts < 500*time.Millisecond {
+			t.Fatalf("%v: the wrong zone is lit: %v", ts, frame)
+		}
+		for i, lit := range [2]bool{a != (color.RGBA{}), b != (color.RGBA{})} {
+			if lit && !was[i] {
+				flashes[i]++
+			}
+			was[i] = lit
+		}
+	}
+	if flashes != [2]int{3, 3} {
+		t.Errorf("expected three flashes in each zone, got %v", flashes)
+	}
+
+	// By default, the period is lengthened to keep the flashes at a safe
+	// rate.
+	f.AllowUnsafeRate = false
+	f.Render(frame, 700*time.Millisecond)
+	if a, b := zoneColors(); a != red || b != (color.RGBA{}) {
+		t.Errorf("safe rate: expected the first zone to be lit, got %v", frame)
+	}
+	var last time.Duration
+	was[0] = false
+	for ts := time.Duration(0); ts < 4*time.Second; ts += 5 * time.Millisecond {
+		f.Render(frame, ts)
+		a, b := zoneColors()
+		lit := a != (color.RGBA{}) || b != (color.RGBA{})
+		if lit && !was[0] {
+			if ts != 0 && ts-last < StrobeMinInterval-5*time.Millisecond {
+				t.Fatalf("safe rate: flashes at %v and %v are too close", last, ts)
+			}
+			last = ts
+		}
+		was[0] = lit
+	}
+
+	// Zones can be anywhere, and other LEDs are left alone.
+	f = &AlternatingFlash{
+		Zones:  [2]Segment{{4, 2}, {0, 1}},
+		Colors: [2]color.RGBA{red, blue},
+		Period: time.Second,
+		Duty:   255,
+	}
+	frame.FillSolid(color.RGBA{G: 1})
+	f.Render(frame, 2*time.Second+499*time.Millisecond)
+	if frame[4] != red || frame[5] != red || frame[0] != (color.RGBA{}) || frame[1] != (color.RGBA{G: 1}) {
+		t.Errorf("custom zones: unexpected frame %v", frame)
+	}
+	f.Render(frame, 2*time.Second+500*time.Millisecond)
+	if frame[4] != (color.RGBA{}) || frame[0] != blue {
+		t.Errorf("custom zones: unexpected frame %v", frame)
+	}
+	f.Duty = 0
+	f.Render(frame, 0)
+	if frame[0] != (color.RGBA{}) || frame[4] != (color.RGBA{}) {
+		t.Errorf("duty 0: expected no flash, got %v", frame)
+	}
+}
@@ -0,0 +1,111 @@
+// Package apa102 encodes frames for APA102 and SK9822 LEDs, which have a
+// separate clock line and a 5-bit global brightness per LED in addition to the
+// 8-bit color channels.
+package apa102
+
+import (
+	"image/color"
+	"io"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// MaxBrightness is the maximum value of the 5-bit global brightness.
+const MaxBrightness = 31
+
+// appendStart appends the start frame.
+func appendStart(buf []byte) []byte {
+	return append(buf, 0, 0, 0, 0)
+}
+
+// appendEnd appends the end frame for a strip of n LEDs. APA102 LEDs need an
+// extra clock pulse for every two LEDs to shift the data all the way through
+// the strip. SK9822 LEDs additionally need a 32-bit reset frame. Zero bytes are
+// used instead of the 0xff bytes from the datasheet, so that any LEDs beyond
+// the end of the frame are turned off instead of on.
+func appendEnd(buf []byte, n int) []byte {
+	buf = append(buf, 0, 0, 0, 0)
+	for i := 0; i < (n+15)/16; i++ {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+// AppendFrame appends a complete frame (start frame, LED data and end frame)
+// to buf and returns the resulting slice. All LEDs use the same global
+// brightness, which must be in the range 0..31. APA102 LEDs normally use the
+// BGR channel order.
+func AppendFrame(buf []byte, frame ledsgo.Strip, brightness uint8, order ledsgo.ColorOrder) []byte {
+	buf = appendStart(buf)
+	header := 0xe0 | brightness&MaxBrightness
+	for _, c := range frame {
+		buf = order.Append(append(buf, header), c)
+	}
+	return appendEnd(buf, len(frame))
+}
+
+// AppendFrame48 appends a complete frame of 16-bit colors to buf and returns
+// the resulting slice. For every LED, the lowest global brightness that can
+// still show the brightest channel is chosen, which gives a lot more
+// resolution for dark colors than 8-bit colors at full brightness.
+func AppendFrame48(buf []byte, frame []ledsgo.Color48, order ledsgo.ColorOrder) []byte {
+	buf = appendStart(buf)
+	for _, c := range frame {
+		brightness, c8 := split48(c)
+		buf = order.Append(append(buf, 0xe0|brightness), c8)
+	}
+	return appendEnd(buf, len(frame))
+}
+
+// split48 splits a 16-bit color into a 5-bit global brightness and a 8-bit
+// color.
+func split48(c ledsgo.Color48) (uint8, color.RGBA) {
+	peak := uint32(max(c.R, c.G, c.B))
+	if peak == 0 {
+		return 0, color.RGBA{}
+	}
+	brightness := (peak*MaxBrightness + 0xfffe) / 0xffff // rounded up
+	div := brightness * 0x101
+	scale := func(v uint16) uint8 {
+		return uint8(min((uint32(v)*MaxBrightness+div/2)/div, 0xff))
+	}
+	return uint8(brightness), color.RGBA{scale(c.R), scale(c.G), scale(c.B), 0}
+}
+
+// Displayer is a ledsgo.Displayer that sends frames to APA102 LEDs over a SPI
+// bus (or any other io.Writer).
+type Displayer struct {
+	W     io.Writer
+	Order ledsgo.ColorOrder
+
+	// Brightness is the global brightness for all LEDs (0..31).
+	Brightness uint8
+
+	buf []byte
+}
+
+// NewDisplayer returns a new Displayer using the BGR channel order at full
+// global brightness.
+func NewDisplayer(w io.Writer) *Displayer {
+	return &Displayer{W: w, Order: ledsgo.OrderBGR, Brightness: MaxBrightness}
+}
+
+// Display encodes and writes the frame.
+func (d *Displayer) Display(frame ledsgo.Strip) error {
+	d.buf = AppendFrame(d.buf[:0], frame, d.Brightness, d.Order)
+	_, err := d.W.Write(d.buf)
+	return err
+}
+
+// Display48 encodes and writes a frame of 16-bit colors. See AppendFrame48.
+func (d *Displayer) Display48(frame []ledsgo.Color48) error {
+	d.buf = AppendFrame48(d.buf[:0], frame, d.Order)
+	_, err := d.W.Write(d.buf)
+	return err
+}
+
+// DisplayFrame is the same as Display48. It implements
+// ledsgo.FrameDisplayer[ledsgo.Color48].
+func (d *Displayer) DisplayFrame(frame []ledsgo.Color48) error {
+	return d.Display48(frame)
+}
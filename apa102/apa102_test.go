@@ -0,0 +1,37 @@
+package apa102
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+)
+
+var _ ledsgo.FrameDisplayer[ledsgo.Color48] = (*Displayer)(nil)
+
+func TestAppendFrame(t *testing.T) {
+	frame := ledsgo.Strip{{R: 1, G: 2, B: 3}, {R: 4, G: 5, B: 6}}
+	buf := AppendFrame(nil, frame, 10, ledsgo.OrderBGR)
+	expected := []byte{0, 0, 0, 0, 0xea, 3, 2, 1, 0xea, 6, 5, 4, 0, 0, 0, 0, 0}
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("unexpected frame:\nexpected %v\ngot      %v", expected, buf)
+	}
+}
+
+func TestSplit48(t *testing.T) {
+	for _, tc := range []struct {
+		c          ledsgo.Color48
+		brightness uint8
+		c8         color.RGBA
+	}{
+		{ledsgo.Color48{}, 0, color.RGBA{}},
+		{ledsgo.Color48{R: 0xffff, G: 0x8000}, 31, color.RGBA{255, 128, 0, 0}},
+		{ledsgo.Color48{R: 0x0100, G: 0x0080, B: 0x0001}, 1, color.RGBA{31, 15, 0, 0}},
+	} {
+		brightness, c8 := split48(tc.c)
+		if brightness != tc.brightness || c8 != tc.c8 {
+			t.Errorf("split48(%v): expected %d, %v, got %d, %v", tc.c, tc.brightness, tc.c8, brightness, c8)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package artnet implements the Art-Net protocol, used by many commercial DMX
+// and pixel controllers. Only the parts needed to send and receive pixel data
+// (ArtDMX and ArtSync) are implemented.
+package artnet
+
+import (
+	"errors"
+)
+
+// Port is the UDP port used by Art-Net.
+const Port = 6454
+
+// Opcodes of the packets implemented in this package.
+const (
+	OpDMX  = 0x5000
+	OpSync = 0x5200
+)
+
+// Protocol version sent in every packet.
+const protocolVersion = 14
+
+// Size of the header of an ArtDMX packet, before the channel data.
+const dmxHeaderSize = 18
+
+// MaxChannels is the maximum number of DMX channels in a single universe.
+const MaxChannels = 512
+
+var id = [8]byte{'A', 'r', 't', '-', 'N', 'e', 't', 0}
+
+var (
+	errInvalidPacket = errors.New("artnet: invalid packet")
+	errUnknownOpcode = errors.New("artnet: unknown opcode")
+)
+
+// AppendDMX appends an ArtDMX packet to buf and returns the resulting slice.
+// The universe is the 15-bit port address (Net, Sub-Net and Universe). The data
+// is the DMX channel data, which is padded to an even length as required by
+// the protocol. It must not be longer than 512 bytes.
+func AppendDMX(buf []byte, sequence uint8, universe uint16, data []byte) []byte {
+	length := len(data) + len(data)%2
+	buf = append(buf, id[:]...)
+	buf = append(buf,
+		byte(OpDMX&0xff), byte(OpDMX>>8), // opcode, little endian
+		0, protocolVersion, // protocol version, big endian
+		sequence,
+		0, // physical port
+		byte(universe), byte(universe>>8&0x7f),
+		byte(length>>8), byte(length), // length, big endian
+	)
+	buf = append(buf, data...)
+	if len(data)%2 != 0 {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+// AppendSync appends an ArtSync packet to buf and returns the resulting slice.
+func AppendSync(buf []byte) []byte {
+	buf = append(buf, id[:]...)
+	return append(buf,
+		byte(OpSync&0xff), byte(OpSync>>8), // opcode, little endian
+		0, protocolVersion, // protocol version, big endian
+		0, 0, // aux
+	)
+}
+
+// Packet is a decoded Art-Net packet.
+type Packet struct {
+	Opcode   uint16
+	Sequence uint8  // only for ArtDMX
+	Universe uint16 // only for ArtDMX
+	Data     []byte // only for ArtDMX, refers to the original buffer
+}
+
+// Parse decodes an ArtDMX or ArtSync packet. Other (valid) Art-Net packets
+// return an error.
+func Parse(buf []byte) (Packet, error) {
+	if len(buf) < 12 || [8]byte(buf[:8]) != id {
+		return Packet{}, errInvalidPacket
+	}
+	p := Packet{
+		Opcode: uint16(buf[8]) | uint16(buf[9])<<8,
+	}
+	switch p.Opcode {
+	case OpDMX:
+		if len(buf) < dmxHeaderSize {
+			return Packet{}, errInvalidPacket
+		}
+		length := int(buf[16])<<8 | int(buf[17])
+		if length > MaxChannels || len(buf) < dmxHeaderSize+length {
+			return Packet{}, errInvalidPacket
+		}
+		p.Sequence = buf[12]
+		p.Universe = uint16(buf[14]) | uint16(buf[15]&0x7f)<<8
+		p.Data = buf[dmxHeaderSize : dmxHeaderSize+length]
+	case OpSync:
+	default:
+		return Packet{}, errUnknownOpcode
+	}
+	return p, nil
+}
@@ -0,0 +1,89 @@
+package artnet
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+	"github.com/aykevl/ledsgo/internal/ledstest"
+)
+
+// packetWriter records every write as a separate packet.
+type packetWriter [][]byte
+
+func (w *packetWriter) Write(buf []byte) (int, error) {
+	*w = append(*w, append([]byte(nil), buf...))
+	return len(buf), nil
+}
+
+func TestSender(t *testing.T) {
+	var packets packetWriter
+	s := &Sender{Conn: &packets, Universe: 0x1234, Sync: true}
+	frame := make(ledsgo.Strip, 171)
+	frame[0] = color.RGBA{R: 1, G: 2, B: 3}
+	frame[170] = color.RGBA{R: 4, G: 5, B: 6}
+	if err := s.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	if len(packets) != 3 {
+		t.Fatalf("expected 3 packets, got %d", len(packets))
+	}
+
+	p, err := Parse(packets[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Opcode != OpDMX || p.Sequence != 1 || p.Universe != 0x1234 || len(p.Data) != 510 {
+		t.Errorf("unexpected first packet: %#v", p)
+	}
+	if !bytes.Equal(p.Data[:3], []byte{1, 2, 3}) {
+		t.Errorf("unexpected first pixel: %v", p.Data[:3])
+	}
+
+	p, err = Parse(packets[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Universe != 0x1235 || !bytes.Equal(p.Data, []byte{4, 5, 6, 0}) {
+		t.Errorf("unexpected second packet: %#v", p)
+	}
+
+	p, err = Parse(packets[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Opcode != OpSync {
+		t.Errorf("expected ArtSync, got opcode %#04x", p.Opcode)
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	var packets packetWriter
+	s := &Sender{Conn: &packets, Universe: 3}
+	frame := make(ledsgo.Strip, 200)
+	for i := range frame {
+		frame[i] = color.RGBA{R: uint8(i), G: 1, B: 2}
+	}
+	s.Display(frame)
+
+	var frames ledstest.FrameRecorder
+	r := NewReceiver(nil, 3, len(frame), &frames)
+	if err := r.Handle(packets[0]); err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 0 {
+		t.Fatalf("frame displayed before all universes were received")
+	}
+	if err := r.Handle(packets[1]); err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame, got %d", len(frames))
+	}
+	for i, c := range frames[0] {
+		if c != frame[i] {
+			t.Errorf("LED %d: expected %v, got %v", i, frame[i], c)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package artnet
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Time after the last ArtSync packet after which a receiver goes back to
+// displaying frames as soon as they are complete, as required by the spec.
+const syncTimeout = 4 * time.Second
+
+// Receiver acts as an Art-Net node: it receives ArtDMX packets for a range of
+// universes and shows the resulting frames on a Displayer. This way, a board
+// running this package can be used as a pixel node for software like xLights
+// or Resolume.
+//
+// A frame is displayed when all universes of the frame have been received.
+// When the sender uses ArtSync packets, frames are displayed on every ArtSync
+// instead.
+type Receiver struct {
+	// Conn is the connection on which packets are received.
+	Conn net.PacketConn
+
+	// Universe is the port address of the first universe.
+	Universe uint16
+
+	// Order is the channel order used by the sender.
+	Order ledsgo.ColorOrder
+
+	// Displayer is where the received frames are sent.
+	Displayer ledsgo.Displayer
+
+	frame    ledsgo.Strip
+	received []bool // universes received since the last displayed frame
+	lastSync time.Time
+	buf      []byte
+}
+
+// NewReceiver returns a new receiver for a frame of the given number of LEDs.
+func NewReceiver(conn net.PacketConn, universe uint16, numLEDs int, displayer ledsgo.Displayer) *Receiver {
+	r := &Receiver{
+		Conn:      conn,
+		Universe:  universe,
+		Displayer: displayer,
+		frame:     make(ledsgo.Strip, numLEDs),
+	}
+	return r
+}
+
+// Listen starts listening for Art-Net packets on the default port and returns
+// a new receiver.
+func Listen(universe uint16, numLEDs int, displayer ledsgo.Displayer) (*Receiver, error) {
+	conn, err := net.ListenPacket("udp", ":"+strconv.Itoa(Port))
+	if err != nil {
+		return nil, err
+	}
+	return NewReceiver(conn, universe, numLEDs, displayer), nil
+}
+
+// Serve receives and handles packets until reading from the connection fails.
+// Invalid packets are ignored.
+func (r *Receiver) Serve() error {
+	if r.buf == nil {
+		r.buf = make([]byte, 1500)
+	}
+	for {
+		n, _, err := r.Conn.ReadFrom(r.buf)
+		if err != nil {
+			return err
+		}
+		r.Handle(r.buf[:n])
+	}
+}
+
+// Handle processes a single packet. It returns an error if the packet is
+// invalid or if the frame could not be displayed.
+func (r *Receiver) Handle(packet []byte) error {
+	p, err := Parse(packet)
+	if err != nil {
+		return err
+	}
+	perUniverse := MaxChannels / r.Order.Channels()
+	numUniverses := (len(r.frame) + perUniverse - 1) / perUniverse
+	if len(r.received) != numUniverses {
+		r.received = make([]bool, numUniverses)
+	}
+
+	if p.Opcode == OpSync {
+		r.lastSync = time.Now()
+		return r.display()
+	}
+
+	index := int(p.Universe) - int(r.Universe)
+	if index < 0 || index >= numUniverses {
+		return nil // not for us
+	}
+	r.Order.DecodeStrip(r.frame[index*perUniverse:], p.Data)
+	r.received[index] = true
+
+	if !r.lastSync.IsZero() && time.Since(r.lastSync) < syncTimeout {
+		return nil // wait for the next ArtSync
+	}
+	for _, received := range r.received {
+		if !received {
+			return nil // wait for the rest of the frame
+		}
+	}
+	return r.display()
+}
+
+// display sends the current frame to the displayer.
+func (r *Receiver) display() error {
+	for i := range r.received {
+		r.received[i] = false
+	}
+	return r.Displayer.Display(r.frame)
+}
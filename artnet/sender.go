@@ -0,0 +1,81 @@
+package artnet
+
+import (
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Sender is a ledsgo.Displayer that sends frames as ArtDMX packets. Frames
+// that don't fit in a single universe are split across consecutive universes,
+// without splitting a single LED across two universes.
+type Sender struct {
+	// Conn is where the packets are written to, usually a UDP connection to
+	// an Art-Net node.
+	Conn io.Writer
+
+	// Universe is the port address of the first universe.
+	Universe uint16
+
+	// Order is the channel order expected by the node.
+	Order ledsgo.ColorOrder
+
+	// Sync sends an ArtSync packet after every frame, so that nodes update
+	// all universes at the same time.
+	Sync bool
+
+	sequence uint8
+	data     []byte
+	packet   []byte
+}
+
+// Dial returns a Sender that sends to the Art-Net node at the given host,
+// starting at the given universe. The host may include a port number, by
+// default the Art-Net port is used.
+func Dial(host string, universe uint16) (*Sender, error) {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, strconv.Itoa(Port))
+	}
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{Conn: conn, Universe: universe}, nil
+}
+
+// PixelsPerUniverse returns the number of LEDs that fit in a single universe
+// with the current channel order: 170 for RGB and 128 for RGBW.
+func (s *Sender) PixelsPerUniverse() int {
+	return MaxChannels / s.Order.Channels()
+}
+
+// Display sends the frame to the node.
+func (s *Sender) Display(frame ledsgo.Strip) error {
+	// Sequence numbers start at 1, 0 means sequencing is disabled.
+	s.sequence++
+	if s.sequence == 0 {
+		s.sequence = 1
+	}
+
+	perUniverse := s.PixelsPerUniverse()
+	universe := s.Universe
+	for len(frame) != 0 {
+		n := min(len(frame), perUniverse)
+		s.data = s.Order.AppendStrip(s.data[:0], frame[:n])
+		s.packet = AppendDMX(s.packet[:0], s.sequence, universe, s.data)
+		if _, err := s.Conn.Write(s.packet); err != nil {
+			return err
+		}
+		frame = frame[n:]
+		universe++
+	}
+	if s.Sync {
+		s.packet = AppendSync(s.packet[:0])
+		if _, err := s.Conn.Write(s.packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package ledsgo
+
+import (
+	"sync"
+)
+
+// AudioSource provides audio input for sound-reactive effects. Effects should
+// only depend on this interface, so that they work the same regardless of the
+// microphone or ADC that provides the audio.
+type AudioSource interface {
+	// Level returns the current loudness, from 0 (silence) to 0xffff (full
+	// scale).
+	Level() uint16
+
+	// Bands fills the slice with the magnitude of each frequency band, from
+	// the lowest to the highest frequencies. The bands are roughly
+	// logarithmically spaced. Each value ranges from 0 to 0xffff.
+	Bands(bands []uint16)
+}
+
+// SampleSource is an AudioSource that computes the level and spectrum from raw
+// 16-bit audio samples, using a fixed-point FFT. Samples can be written to it
+// directly using Write, or can be read from a channel using Stream. It is safe
+// to use from multiple goroutines.
+type SampleSource struct {
+	lock     sync.Mutex
+	ring     [audioFFTSize]int16 // last samples
+	pos      int                 // next position in ring
+	pending  int                 // number of samples since the last FFT
+	level    uint16
+	spectrum [audioFFTSize / 2]uint16
+	re, im   [audioFFTSize]int32 // FFT work buffers
+}
+
+// NewSampleSource returns a new SampleSource that starts in silence.
+func NewSampleSource() *SampleSource {
+	return &SampleSource{}
+}
+
+// Write adds new audio samples. The level and spectrum are recalculated every
+// half FFT size, which is smaller with the ledsgo_small build tag, so Write can
+// be called with any number of samples at a time.
+func (s *SampleSource) Write(samples []int16) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, sample := range samples {
+		s.ring[s.pos] = sample
+		s.pos = (s.pos + 1) % audioFFTSize
+		s.pending++
+		if s.pending == audioFFTSize/2 {
+			s.pending = 0
+			s.update()
+		}
+	}
+}
+
+// Stream reads sample buffers from the channel until it is closed. It is
+// normally run in a separate goroutine.
+func (s *SampleSource) Stream(samples <-chan []int16) {
+	for buf := range samples {
+		s.Write(buf)
+	}
+}
+
+// Level implements AudioSource. It returns the RMS level of the samples of the
+// last FFT, scaled so that a full-scale sine wave returns 0xffff.
+func (s *SampleSource) Level() uint16 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.level
+}
+
+// Bands implements AudioSource. The FFT bins are divided over the bands using
+// a quadratic curve, which is a cheap approximation of logarithmic spacing.
+// The magnitude of a band is the magnitude of the loudest bin in it.
+func (s *SampleSource) Bands(bands []uint16) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	n := len(bands)
+	numBins := len(s.spectrum) - 1 // skip the DC bin
+	start := 1
+	for b := range bands {
+		end := 1 + numBins*(b+1)*(b+1)/(n*n)
+		if end <= start {
+			end = start + 1 // at least one bin per band
+		}
+		if end > len(s.spectrum) {
+			end = len(s.spectrum)
+		}
+		var max uint16
+		for _, m := range s.spectrum[start:end] {
+			if m > max {
+				max = m
+			}
+		}
+		bands[b] = max
+		start = end
+	}
+}
+
+// update recalculates the level and spectrum from the sample ring buffer. It
+// must be called with the lock held.
+func (s *SampleSource) update() {
+	var sum uint64
+	for i := range s.ring {
+		sample := int32(s.ring[(s.pos+i)%audioFFTSize])
+		sum += uint64(sample * sample)
+		// Apply a Hann window, to reduce spectral leakage between bins.
+		window := 0x8000 - int32(Cos16(uint16(i*0x10000/audioFFTSize))) // .16
+		s.re[i] = sample * window >> 16
+		s.im[i] = 0
+	}
+	// The RMS of a full-scale sine wave is 0x7fff/sqrt(2), the RMS of a
+	// full-scale square wave is 0x7fff.
+	rms := uint64(Sqrt32(uint32(sum / audioFFTSize)))
+	rms = rms * 0x2d414 >> 16 // multiply by 2*sqrt(2)
+	if rms > 0xffff {
+		rms = 0xffff
+	}
+	s.level = uint16(rms)
+
+	fft(s.re[:], s.im[:])
+	for i := range s.spectrum {
+		re := s.re[i]
+		im := s.im[i]
+		mag := uint32(Sqrt32(uint32(re*re + im*im)))
+		// A full-scale sine wave results in a magnitude of about 0x7fff/4 in
+		// the bin of its frequency, after windowing and scaling.
+		mag <<= 3
+		if mag > 0xffff {
+			mag = 0xffff
+		}
+		s.spectrum[i] = uint16(mag)
+	}
+}
+
+// fft calculates the in-place radix-2 FFT of the given real and imaginary
+// parts, which must have a length that is a power of two. Every stage scales
+// the values down by two to avoid overflow, so the result is divided by the
+// FFT size. Inputs must fit in an int16.
+func fft(re, im []int32) {
+	n := len(re)
+
+	// Reorder the input in bit-reversed order.
+	j := 0
+	for i := 1; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+		if i < j {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+
+	// Butterfly stages.
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		step := 0x10000 / size
+		for k := 0; k < half; k++ {
+			theta := uint16(-k * step)
+			wr := int32(Cos16(theta)) // .15
+			wi := int32(Sin16(theta)) // .15
+			for i := k; i < n; i += size {
+				j := i + half
+				tr := (re[j]*wr - im[j]*wi) >> 15
+				ti := (re[j]*wi + im[j]*wr) >> 15
+				re[j] = (re[i] - tr) >> 1
+				im[j] = (im[i] - ti) >> 1
+				re[i] = (re[i] + tr) >> 1
+				im[i] = (im[i] + ti) >> 1
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package ledsgo
+
+import (
+	"testing"
+)
+
+func sineSamples(n, period int, amplitude int32) []int16 {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16(int32(Sin16(uint16(i*0x10000/period))) * amplitude >> 15)
+	}
+	return samples
+}
+
+func TestSampleSourceLevel(t *testing.T) {
+	s := NewSampleSource()
+	if level := s.Level(); level != 0 {
+		t.Errorf("initial level: expected 0, got %d", level)
+	}
+	s.Write(make([]int16, audioFFTSize))
+	if level := s.Level(); level != 0 {
+		t.Errorf("silence: expected 0, got %d", level)
+	}
+	s.Write(sineSamples(audioFFTSize, 32, 0x7fff))
+	if level := s.Level(); level < 0xf000 {
+		t.Errorf("full-scale sine: expected about 0xffff, got %#x", level)
+	}
+	s.Write(sineSamples(audioFFTSize, 32, 0x2000))
+	if level := s.Level(); level < 0x3800 || level > 0x4800 {
+		t.Errorf("quarter-scale sine: expected about 0x4000, got %#x", level)
+	}
+}
+
+func TestSampleSourceBands(t *testing.T) {
+	for _, period := range []int{audioFFTSize / 2, audioFFTSize / 8, 4} {
+		s := NewSampleSource()
+		s.Write(sineSamples(audioFFTSize, period, 0x4000))
+		var bands [8]uint16
+		s.Bands(bands[:])
+		bin := audioFFTSize / period
+		loudest := 0
+		for i, m := range bands {
+			if m > bands[loudest] {
+				loudest = i
+			}
+		}
+		if bands[loudest] < 0x6000 {
+			t.Errorf("period %d: expected a loud band, got %v", period, bands)
+		}
+		// Check that the band with the expected bin is the loudest.
+		bins := audioFFTSize/2 - 1
+		start, end := 1, 1
+		for b := 0; b <= loudest; b++ {
+			start = end
+			end = max(1+bins*(b+1)*(b+1)/(len(bands)*len(bands)), start+1)
+		}
+		if bin < start || bin >= end {
+			t.Errorf("period %d: bin %d not in loudest band %d (bins %d..%d): %v", period, bin, loudest, start, end, bands)
+		}
+	}
+}
+
+func TestFFT(t *testing.T) {
+	// A DC input results in a single DC bin. The twiddle factors are slightly
+	// below 1, so allow for some rounding error.
+	var re, im [16]int32
+	for i := range re {
+		re[i] = 1000
+	}
+	fft(re[:], im[:])
+	if re[0] < 990 || re[0] > 1000 || im[0] != 0 {
+		t.Errorf("DC bin: expected 1000, got %d%+di", re[0], im[0])
+	}
+	for i := 1; i < len(re); i++ {
+		if abs(int(re[i])) > 2 || abs(int(im[i])) > 2 {
+			t.Errorf("bin %d: expected 0, got %d%+di", i, re[i], im[i])
+		}
+	}
+}
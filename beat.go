@@ -0,0 +1,63 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// This file implements BPM-locked oscillators with the same semantics as the
+// beat functions in FastLED. All of them take the current time t from a
+// monotonic time source, for example the time since the program started. To
+// start a beat at a specific moment, subtract that moment from t.
+//
+// BPM values below 256 are whole beats per minute. Values of 256 and up are
+// interpreted as 8.8 fixed-point values (accum88 in FastLED), which allows for
+// fractional beats per minute: 120.5 BPM is 0x7880.
+
+// Beat88 returns a sawtooth wave that rises from 0 to 65535 once every beat.
+// The BPM is a 8.8 fixed-point value.
+func Beat88(bpm88 uint16, t time.Duration) uint16 {
+	// 65536 * 256 / 60000 ≈ 280, like in FastLED. Calculated in 64 bits to
+	// avoid jumps when the multiplication overflows after a few hours.
+	ms := uint64(t / time.Millisecond)
+	return uint16((ms * uint64(bpm88) * 280) >> 16)
+}
+
+// Beat16 returns a sawtooth wave that rises from 0 to 65535 once every beat.
+func Beat16(bpm uint16, t time.Duration) uint16 {
+	if bpm < 256 {
+		bpm <<= 8
+	}
+	return Beat88(bpm, t)
+}
+
+// Beat8 returns a sawtooth wave that rises from 0 to 255 once every beat.
+func Beat8(bpm uint16, t time.Duration) uint8 {
+	return uint8(Beat16(bpm, t) >> 8)
+}
+
+// BeatSin88 returns a sine wave that oscillates between low and high (both
+// inclusive) once every beat. The BPM is a 8.8 fixed-point value. The phase
+// shifts the wave, where 0x10000 would be a full beat.
+func BeatSin88(bpm88 uint16, t time.Duration, phase, low, high uint16) uint16 {
+	beat := Beat88(bpm88, t)
+	sin := uint16(int32(Sin16(beat+phase)) + 32768)
+	return low + uint16(uint32(sin)*(uint32(high-low)+1)>>16)
+}
+
+// BeatSin16 returns a sine wave that oscillates between low and high (both
+// inclusive) once every beat. See BeatSin88 for details.
+func BeatSin16(bpm uint16, t time.Duration, phase, low, high uint16) uint16 {
+	if bpm < 256 {
+		bpm <<= 8
+	}
+	return BeatSin88(bpm, t, phase, low, high)
+}
+
+// BeatSin8 returns a sine wave that oscillates between low and high (both
+// inclusive) once every beat. The phase shifts the wave, where 256 would be a
+// full beat.
+func BeatSin8(bpm uint16, t time.Duration, phase, low, high uint8) uint8 {
+	beat := Beat16(bpm, t)
+	sin := uint8((int32(Sin16(beat+uint16(phase)<<8)) + 32768) >> 8)
+	return low + uint8(uint16(sin)*(uint16(high-low)+1)>>8)
+}
@@ -0,0 +1,111 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBeat8(t *testing.T) {
+	// At 60 BPM, a beat is about a second long.
+	for _, tc := range []struct {
+		t      time.Duration
+		result uint8
+	}{
+		{0, 0},
+		{250 * time.Millisecond, 64},
+		{500 * time.Millisecond, 128},
+		{750 * time.Millisecond, 192},
+		{990 * time.Millisecond, 253},
+		{time.Second, 0},
+	} {
+		if result := Beat8(60, tc.t); abs(int(result)-int(tc.result)) > 1 {
+			t.Errorf("Beat8(60, %v): expected %d, got %d", tc.t, tc.result, result)
+		}
+	}
+
+	// Whole and 8.8 fixed-point BPM values give the same beat.
+	for ms := time.Duration(0); ms < 10*time.Second; ms += 37 * time.Millisecond {
+		if Beat8(120, ms) != Beat8(120<<8, ms) {
+			t.Fatalf("Beat8 at %v: 120 and 120<<8 BPM differ", ms)
+		}
+	}
+
+	// The wave only rises during a beat.
+	prev := Beat8(100, 0)
+	for ms := time.Duration(1); ms < 600*time.Millisecond; ms += time.Millisecond {
+		result := Beat8(100, ms)
+		if result < prev {
+			t.Fatalf("Beat8(100, %v): fell from %d to %d within a beat", ms, prev, result)
+		}
+		prev = result
+	}
+}
+
+func TestBeatSin(t *testing.T) {
+	// The 8-bit and 16-bit sine waves stay within their range, and reach both
+	// ends during a beat.
+	for _, tc := range []struct {
+		low, high uint8
+	}{
+		{0, 255},
+		{50, 200},
+		{100, 100},
+	} {
+		minimum, maximum := uint8(255), uint8(0)
+		for ms := time.Duration(0); ms < time.Second; ms += time.Millisecond {
+			result := BeatSin8(60, ms, 0, tc.low, tc.high)
+			if result < tc.low || result > tc.high {
+				t.Fatalf("BeatSin8(60, %v, 0, %d, %d): %d is out of range", ms, tc.low, tc.high, result)
+			}
+			minimum, maximum = min(minimum, result), max(maximum, result)
+		}
+		if minimum != tc.low || maximum != tc.high {
+			t.Errorf("BeatSin8(60, ..., %d, %d): expected range %d..%d, got %d..%d", tc.low, tc.high, tc.low, tc.high, minimum, maximum)
+		}
+	}
+	for _, tc := range []struct {
+		low, high uint16
+	}{
+		{0, 0xffff},
+		{1000, 3000},
+	} {
+		minimum, maximum := uint16(0xffff), uint16(0)
+		for ms := time.Duration(0); ms < time.Second; ms += time.Millisecond {
+			result := BeatSin16(60, ms, 0, tc.low, tc.high)
+			if result < tc.low || result > tc.high {
+				t.Fatalf("BeatSin16(60, %v, 0, %d, %d): %d is out of range", ms, tc.low, tc.high, result)
+			}
+			minimum, maximum = min(minimum, result), max(maximum, result)
+		}
+		if int(minimum)-int(tc.low) > 2 || int(tc.high)-int(maximum) > 2 {
+			t.Errorf("BeatSin16(60, ..., %d, %d): expected range %d..%d, got %d..%d", tc.low, tc.high, tc.low, tc.high, minimum, maximum)
+		}
+	}
+
+	// The wave starts in the middle of the range, rising, like a sine.
+	if result := BeatSin8(60, 0, 0, 0, 200); abs(int(result)-100) > 1 {
+		t.Errorf("BeatSin8 at the start of a beat: expected 100, got %d", result)
+	}
+	if result := BeatSin8(60, 250*time.Millisecond, 0, 0, 200); result < 199 {
+		t.Errorf("BeatSin8 after a quarter beat: expected 200, got %d", result)
+	}
+	if result := BeatSin8(60, 750*time.Millisecond, 0, 0, 200); result > 1 {
+		t.Errorf("BeatSin8 after three quarters of a beat: expected 0, got %d", result)
+	}
+
+	// A phase of a quarter beat is the same as starting a quarter beat later.
+	// Beats are slightly shorter than the BPM says (see Beat88), so the waves
+	// don't match exactly.
+	for ms := time.Duration(0); ms < 2*time.Second; ms += 10 * time.Millisecond {
+		shifted := BeatSin88(60<<8, ms, 0x4000, 0, 0xffff)
+		later := BeatSin88(60<<8, ms+250*time.Millisecond, 0, 0, 0xffff)
+		if abs(int(shifted)-int(later)) > 128 {
+			t.Errorf("BeatSin88 at %v: phase 0x4000 gives %d, a quarter beat later gives %d", ms, shifted, later)
+		}
+		shifted8 := BeatSin8(60, ms, 64, 0, 255)
+		later8 := BeatSin8(60, ms+250*time.Millisecond, 0, 0, 255)
+		if abs(int(shifted8)-int(later8)) > 1 {
+			t.Errorf("BeatSin8 at %v: phase 64 gives %d, a quarter beat later gives %d", ms, shifted8, later8)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// Number of beat intervals over which the BPM is estimated.
+const beatIntervals = 8
+
+// BeatDetector detects beats in music by looking for sudden increases in the
+// bass energy of an AudioSource, compared to the recent average. It also
+// estimates the tempo, so that the beat functions such as BeatSin88 can be
+// synchronized to the music.
+//
+// Call Update once every frame.
+type BeatDetector struct {
+	Source AudioSource
+
+	// Threshold is how much louder than the average the bass must be to count
+	// as a beat, as a 8.8 fixed-point factor. The default (set by
+	// NewBeatDetector) is 1.5.
+	Threshold uint16
+
+	// MinLevel is the minimum bass level for a beat, to avoid detecting beats
+	// in background noise.
+	MinLevel uint16
+
+	// MinInterval is the minimum time between two beats. The default is 300ms,
+	// which corresponds to 200 BPM.
+	MinInterval time.Duration
+
+	average   uint32 // moving average of the bass energy, 16.8 fixed-point
+	above     bool   // bass energy was above the threshold in the last update
+	lastBeat  time.Duration
+	beats     int // number of beats so far
+	intervals [beatIntervals]time.Duration
+}
+
+// NewBeatDetector returns a new beat detector with default settings for the
+// given audio source.
+func NewBeatDetector(source AudioSource) *BeatDetector {
+	return &BeatDetector{
+		Source:      source,
+		Threshold:   0x0180, // 1.5
+		MinLevel:    0x0800,
+		MinInterval: 300 * time.Millisecond,
+	}
+}
+
+// Update reads the current bass energy from the audio source and returns true
+// if a beat starts at time t. A beat is only reported once, when the energy
+// first rises above the threshold.
+func (d *BeatDetector) Update(t time.Duration) bool {
+	var bands [4]uint16
+	d.Source.Bands(bands[:])
+	energy := uint32(bands[0])
+
+	// Compare against the average before updating it, so that the beat itself
+	// doesn't raise the threshold.
+	threshold := uint32(uint64(d.average) * uint64(d.Threshold) >> 16) // .8 * .8 >> 16 = .0
+	beat := false
+	if energy > threshold && energy >= uint32(d.MinLevel) {
+		if !d.above && (d.beats == 0 || t-d.lastBeat >= d.MinInterval) {
+			beat = true
+			if d.beats != 0 {
+				interval := t - d.lastBeat
+				if interval > 2*time.Second {
+					// Too long since the last beat (for example, a break in
+					// the music): start estimating the tempo again.
+					d.beats = 0
+				} else {
+					d.intervals[(d.beats-1)%beatIntervals] = interval
+				}
+			}
+			d.beats++
+			d.lastBeat = t
+		}
+		d.above = true
+	} else {
+		d.above = false
+	}
+
+	// Exponential moving average over roughly the last 32 updates.
+	d.average = d.average - d.average>>5 + energy<<3 // .8
+	return beat
+}
+
+// LastBeat returns the time of the last detected beat. Subtracting it from the
+// current time aligns beat functions such as BeatSin88 with the music.
+func (d *BeatDetector) LastBeat() time.Duration {
+	return d.lastBeat
+}
+
+// BPM88 returns the estimated tempo in beats per minute as a 8.8 fixed-point
+// value, based on the median of the last beat intervals. It returns 0 if there
+// weren't enough beats yet to estimate the tempo, and 0xffff for tempos that
+// don't fit in 8.8 (256 BPM and above). The result can be passed directly to
+// Beat88 and BeatSin88.
+func (d *BeatDetector) BPM88() uint16 {
+	n := d.beats - 1
+	if n > beatIntervals {
+		n = beatIntervals
+	}
+	if n < 2 {
+		return 0
+	}
+
+	// Sort the intervals (insertion sort, to avoid allocating).
+	var sorted [beatIntervals]time.Duration
+	copy(sorted[:], d.intervals[:n])
+	for i := 1; i < n; i++ {
+		for j := i; j > 0 && sorted[j] < sorted[j-1]; j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+	median := sorted[n/2]
+	if median <= 0 {
+		return 0xffff
+	}
+	// Tempos above 256 BPM don't fit, so they are clamped.
+	return uint16(min((time.Minute<<8+median/2)/median, 0xffff))
+}
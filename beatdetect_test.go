@@ -0,0 +1,78 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+// testAudio is an AudioSource with a fixed level in all bands.
+type testAudio uint16
+
+func (a testAudio) Level() uint16 {
+	return uint16(a)
+}
+
+func (a testAudio) Bands(bands []uint16) {
+	for i := range bands {
+		bands[i] = uint16(a)
+	}
+}
+
+func TestBeatDetector(t *testing.T) {
+	audio := testAudio(0)
+	d := NewBeatDetector(&audio)
+	const frame = time.Second / 60
+	beats := 0
+	var lastBeat time.Duration
+	for now := time.Duration(0); now < 10*time.Second; now += frame {
+		// 120 BPM: a loud kick of 100ms every 500ms over quieter music.
+		audio = 0x2000
+		if now%(500*time.Millisecond) < 100*time.Millisecond {
+			audio = 0xc000
+		}
+		if d.Update(now) {
+			if beats > 1 {
+				if interval := now - lastBeat; interval < 480*time.Millisecond || interval > 520*time.Millisecond {
+					t.Errorf("beat at %v: unexpected interval %v", now, interval)
+				}
+			}
+			beats++
+			lastBeat = now
+		}
+	}
+	if beats < 18 || beats > 20 {
+		t.Errorf("expected about 20 beats, got %d", beats)
+	}
+	if bpm88 := d.BPM88(); bpm88 < 118<<8 || bpm88 > 122<<8 {
+		t.Errorf("expected about 120 BPM, got %#x", bpm88)
+	}
+	if d.LastBeat() != lastBeat {
+		t.Errorf("LastBeat: expected %v, got %v", lastBeat, d.LastBeat())
+	}
+}
+
+func TestBeatDetectorSilence(t *testing.T) {
+	d := NewBeatDetector(testAudio(0x100))
+	for now := time.Duration(0); now < 5*time.Second; now += time.Second / 60 {
+		if d.Update(now) {
+			t.Fatalf("unexpected beat at %v", now)
+		}
+	}
+	if bpm88 := d.BPM88(); bpm88 != 0 {
+		t.Errorf("expected unknown BPM, got %#x", bpm88)
+	}
+}
+
+func TestBeatDetectorFastTempo(t *testing.T) {
+	// Tempos that don't fit in 8.8 are clamped instead of wrapping around.
+	d := NewBeatDetector(testAudio(0x100))
+	d.beats = 4
+	for _, interval := range []time.Duration{200 * time.Millisecond, 0} {
+		for i := range d.intervals {
+			d.intervals[i] = interval
+		}
+		if bpm88 := d.BPM88(); bpm88 != 0xffff {
+			t.Errorf("interval %v: expected the maximum BPM, got %#x", interval, bpm88)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ledsgo
+
+import (
+	"sync"
+)
+
+// Blackout is a Displayer that sits between the effect engine and the real
+// output and can force all LEDs to black at any time, for example from an
+// emergency stop button or a photosensitivity safety control. Once engaged,
+// the blackout stays in effect (latches) until it is released, regardless of
+// which effects or transitions are running.
+//
+// All methods are safe for concurrent use.
+type Blackout struct {
+	displayer Displayer
+	lock      sync.Mutex
+	engaged   bool
+	black     Strip // black frame, with the length of the last frame
+}
+
+// NewBlackout returns a new Blackout that sends frames to the given displayer.
+// It starts released.
+func NewBlackout(displayer Displayer) *Blackout {
+	return &Blackout{displayer: displayer}
+}
+
+// Engage forces all LEDs to black. A black frame is sent immediately, without
+// waiting for the next frame, and all following frames are replaced by black
+// frames until Release is called.
+func (b *Blackout) Engage() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.engaged = true
+	if len(b.black) == 0 {
+		return nil // nothing has been displayed yet
+	}
+	return b.displayer.Display(b.black)
+}
+
+// Release ends the blackout. The next frame will be displayed as usual.
+func (b *Blackout) Release() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.engaged = false
+}
+
+// Engaged returns whether the blackout is currently in effect.
+func (b *Blackout) Engaged() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.engaged
+}
+
+// Display sends the frame to the underlying displayer, or a black frame of the
+// same length while the blackout is engaged.
+func (b *Blackout) Display(frame Strip) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if len(b.black) != len(frame) {
+		b.black = make(Strip, len(frame))
+	}
+	if b.engaged {
+		return b.displayer.Display(b.black)
+	}
+	return b.displayer.Display(frame)
+}
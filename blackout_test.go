@@ -0,0 +1,66 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+// frameRecorder is a Displayer that stores copies of all frames. It is like
+// ledstest.FrameRecorder, which can't be used here because it imports this
+// package.
+type frameRecorder struct {
+	frames []Strip
+}
+
+func (r *frameRecorder) Display(frame Strip) error {
+	r.frames = append(r.frames, append(Strip(nil), frame...))
+	return nil
+}
+
+func isBlack(frame Strip) bool {
+	for _, c := range frame {
+		if c != (color.RGBA{}) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBlackout(t *testing.T) {
+	rec := &frameRecorder{}
+	b := NewBlackout(rec)
+	frame := Strip{{R: 255}, {G: 255}, {B: 255}}
+
+	// Engaging before anything was displayed doesn't send anything.
+	if err := b.Engage(); err != nil {
+		t.Fatal(err)
+	}
+	b.Release()
+	if len(rec.frames) != 0 {
+		t.Fatalf("expected no frames, got %d", len(rec.frames))
+	}
+
+	b.Display(frame)
+	if err := b.Engage(); err != nil {
+		t.Fatal(err)
+	}
+	if !b.Engaged() {
+		t.Error("expected blackout to be engaged")
+	}
+	b.Display(frame)
+	b.Display(frame)
+	b.Release()
+	b.Display(frame)
+
+	if len(rec.frames) != 5 {
+		t.Fatalf("expected 5 frames, got %d", len(rec.frames))
+	}
+	for i, expectBlack := range []bool{false, true, true, true, false} {
+		if len(rec.frames[i]) != len(frame) {
+			t.Errorf("frame %d: expected %d LEDs, got %d", i, len(frame), len(rec.frames[i]))
+		}
+		if isBlack(rec.frames[i]) != expectBlack {
+			t.Errorf("frame %d: expected black=%v, got %v", i, expectBlack, rec.frames[i])
+		}
+	}
+}
@@ -0,0 +1,75 @@
+//go:build js && wasm
+
+package browser
+
+import (
+	"strconv"
+	"syscall/js"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Displayer is a ledsgo.Displayer that draws frames onto an HTML canvas
+// element, using one canvas pixel per LED. The canvas is scaled up using CSS
+// with pixelated rendering, so LEDs show up as sharp squares.
+type Displayer struct {
+	ctx    js.Value
+	width  int
+	height int
+	pix    []byte
+	array  js.Value // Uint8ClampedArray
+	image  js.Value // ImageData
+}
+
+// NewDisplayer returns a displayer that draws on the canvas element with the
+// given ID. The canvas is created and appended to the document body if it
+// doesn't exist yet. The LEDs are laid out row by row in rows of width LEDs,
+// like a Canvas. Use a width of 0 to show a single strip. Every LED takes up
+// scale by scale CSS pixels.
+func NewDisplayer(id string, numLEDs, width, scale int) *Displayer {
+	if width <= 0 {
+		width = max(numLEDs, 1) // at least one column, also without LEDs
+	}
+	if scale <= 0 {
+		scale = 1
+	}
+	height := (numLEDs + width - 1) / width
+	document := js.Global().Get("document")
+	canvas := document.Call("getElementById", id)
+	if canvas.IsNull() {
+		canvas = document.Call("createElement", "canvas")
+		canvas.Set("id", id)
+		document.Get("body").Call("appendChild", canvas)
+	}
+	canvas.Set("width", width)
+	canvas.Set("height", height)
+	style := canvas.Get("style")
+	style.Set("width", strconv.Itoa(width*scale)+"px")
+	style.Set("height", strconv.Itoa(height*scale)+"px")
+	style.Set("imageRendering", "pixelated")
+	array := js.Global().Get("Uint8ClampedArray").New(width * height * 4)
+	return &Displayer{
+		ctx:    canvas.Call("getContext", "2d"),
+		width:  width,
+		height: height,
+		pix:    make([]byte, width*height*4),
+		array:  array,
+		image:  js.Global().Get("ImageData").New(array, width, height),
+	}
+}
+
+// Display draws the frame onto the canvas.
+func (d *Displayer) Display(frame ledsgo.Strip) error {
+	for i, c := range frame {
+		if i*4 >= len(d.pix) {
+			break
+		}
+		d.pix[i*4+0] = c.R
+		d.pix[i*4+1] = c.G
+		d.pix[i*4+2] = c.B
+		d.pix[i*4+3] = 0xff
+	}
+	js.CopyBytesToJS(d.array, d.pix)
+	d.ctx.Call("putImageData", d.image, 0, 0)
+	return nil
+}
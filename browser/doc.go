@@ -0,0 +1,8 @@
+// Package browser draws LED frames onto an HTML canvas when compiled to
+// WebAssembly, so effects can be previewed and shared in a web browser:
+//
+//	GOOS=js GOARCH=wasm go build -o effect.wasm ./cmd/myeffect
+//
+// The resulting file can be loaded using the wasm_exec.js support file that
+// comes with Go.
+package browser
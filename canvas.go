@@ -0,0 +1,152 @@
+package ledsgo
+
+import (
+	"image"
+	"image/color"
+)
+
+// Canvas is a 2D grid of pixels, such as the pixels of a LED matrix. Pixels are
+// stored row by row, starting at the top left.
+type Canvas struct {
+	Width  int
+	Height int
+	Pix    []color.RGBA
+
+	ditherErr []int32 // error buffer for DrawDithered, reused between frames
+}
+
+// NewCanvas allocates a new canvas of the given size with all pixels black.
+func NewCanvas(width, height int) *Canvas {
+	return &Canvas{
+		Width:  width,
+		Height: height,
+		Pix:    make([]color.RGBA, width*height),
+	}
+}
+
+// NewSupersampledCanvas allocates a canvas that is factor times as wide and as
+// high as the given (physical) size. Effects can draw on this canvas at the
+// higher resolution, after which Downscale produces the physical image. This
+// greatly improves the look of moving shapes and text on low-resolution
+// matrices. A factor of 2 to 4 is usually enough.
+func NewSupersampledCanvas(width, height, factor int) *Canvas {
+	return NewCanvas(width*factor, height*factor)
+}
+
+// frameCanvas returns a canvas of the given size over the LEDs of a frame,
+// for effects that draw on a matrix. If the frame is too small, the canvas has
+// fewer rows.
+func frameCanvas(frame Strip, width, height int) Canvas {
+	height = min(height, len(frame)/max(width, 1))
+	return Canvas{Width: width, Height: height, Pix: frame[:width*height]}
+}
+
+// RGBAAt returns the color of the pixel at the given coordinates. Coordinates
+// outside of the canvas are black.
+func (c *Canvas) RGBAAt(x, y int) color.RGBA {
+	if x < 0 || y < 0 || x >= c.Width || y >= c.Height {
+		return color.RGBA{}
+	}
+	return c.Pix[y*c.Width+x]
+}
+
+// SetRGBA sets the color of the pixel at the given coordinates. Coordinates
+// outside of the canvas are ignored.
+func (c *Canvas) SetRGBA(x, y int, col color.RGBA) {
+	if x < 0 || y < 0 || x >= c.Width || y >= c.Height {
+		return
+	}
+	c.Pix[y*c.Width+x] = col
+}
+
+// ColorModel implements the image.Image interface.
+func (c *Canvas) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+// Bounds implements the image.Image interface.
+func (c *Canvas) Bounds() image.Rectangle {
+	return image.Rect(0, 0, c.Width, c.Height)
+}
+
+// At implements the image.Image interface. LEDs have no notion of
+// transparency, so the returned colors are always fully opaque.
+func (c *Canvas) At(x, y int) color.Color {
+	col := c.RGBAAt(x, y)
+	col.A = 0xff
+	return col
+}
+
+// Set implements the draw.Image interface, so that the standard image/draw
+// package (and libraries built on it, such as font renderers) can draw
+// directly on the canvas. Semi-transparent colors are stored as if they were
+// drawn over black.
+func (c *Canvas) Set(x, y int, col color.Color) {
+	c.SetRGBA(x, y, color.RGBAModel.Convert(col).(color.RGBA))
+}
+
+// Fill sets all pixels to the given color.
+func (c *Canvas) Fill(col color.RGBA) {
+	Strip(c.Pix).FillSolid(col)
+}
+
+// Downscale box-filters this canvas down to the destination canvas: every
+// pixel in dst becomes the average of the block of pixels it covers in c. The
+// size of c must be an exact multiple of the size of dst, with the same factor
+// horizontally and vertically.
+func (c *Canvas) Downscale(dst *Canvas) {
+	if dst.Width == 0 || dst.Height == 0 {
+		return
+	}
+	factor := c.Width / dst.Width
+	if factor == 0 || c.Width != dst.Width*factor || c.Height != dst.Height*factor {
+		panic("ledsgo: canvas size is not a multiple of the destination size")
+	}
+	n := uint32(factor * factor)
+	for y := 0; y < dst.Height; y++ {
+		for x := 0; x < dst.Width; x++ {
+			var r, g, b, a uint32
+			for sy := 0; sy < factor; sy++ {
+				row := c.Pix[(y*factor+sy)*c.Width+x*factor:][:factor]
+				for _, p := range row {
+					r += uint32(p.R)
+					g += uint32(p.G)
+					b += uint32(p.B)
+					a += uint32(p.A)
+				}
+			}
+			// Round to the nearest value instead of rounding down, to avoid
+			// darkening the image.
+			dst.Pix[y*dst.Width+x] = color.RGBA{
+				R: uint8((r + n/2) / n),
+				G: uint8((g + n/2) / n),
+				B: uint8((b + n/2) / n),
+				A: uint8((a + n/2) / n),
+			}
+		}
+	}
+}
+
+// DrawDot draws a single dot at a fractional position, spreading the color
+// over the four nearest pixels. The x and y coordinates are 24.8 fixed-point
+// values and the color is added to the existing colors. See Strip.DrawDot.
+func (c *Canvas) DrawDot(x, y int32, col color.RGBA) {
+	px := int(x >> 8)
+	py := int(y >> 8)
+	fx := uint32(x & 0xff)
+	fy := uint32(y & 0xff)
+	c.addWeighted(px, py, col, (256-fx)*(256-fy)>>8)
+	c.addWeighted(px+1, py, col, fx*(256-fy)>>8)
+	c.addWeighted(px, py+1, col, (256-fx)*fy>>8)
+	c.addWeighted(px+1, py+1, col, fx*fy>>8)
+}
+
+// addWeighted adds the color, scaled by weight/256, to the pixel at the given
+// coordinates. Coordinates outside of the canvas are ignored.
+func (c *Canvas) addWeighted(x, y int, col color.RGBA, weight uint32) {
+	if weight == 0 || x < 0 || y < 0 || x >= c.Width || y >= c.Height {
+		return
+	}
+	i := y*c.Width + x
+	c.Pix[i] = addWeighted(c.Pix[i], col, uint16(weight))
+}
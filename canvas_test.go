@@ -0,0 +1,146 @@
+package ledsgo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// Make sure Canvas can be used with the standard image packages.
+var _ draw.Image = (*Canvas)(nil)
+
+func TestCanvasDraw(t *testing.T) {
+	c := NewCanvas(4, 3)
+	draw.Draw(c, image.Rect(1, 1, 3, 2), image.NewUniform(color.RGBA{10, 20, 30, 255}), image.Point{}, draw.Src)
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			expected := color.RGBA{}
+			if y == 1 && (x == 1 || x == 2) {
+				expected = color.RGBA{10, 20, 30, 255}
+			}
+			if col := c.RGBAAt(x, y); col != expected {
+				t.Errorf("pixel (%d, %d): expected %v, got %v", x, y, expected, col)
+			}
+		}
+	}
+}
+
+func TestCanvasDownscale(t *testing.T) {
+	src := NewSupersampledCanvas(2, 1, 2)
+	src.SetRGBA(0, 0, color.RGBA{R: 255})
+	src.SetRGBA(1, 1, color.RGBA{R: 255})
+	src.SetRGBA(2, 0, color.RGBA{G: 100})
+	dst := NewCanvas(2, 1)
+	src.Downscale(dst)
+	if dst.Pix[0] != (color.RGBA{R: 128}) || dst.Pix[1] != (color.RGBA{G: 25}) {
+		t.Errorf("unexpected downscaled pixels: %v", dst.Pix)
+	}
+}
+
+func TestCanvasDownscaleSizes(t *testing.T) {
+	// Every block of 3x3 pixels is averaged, with rounding to the nearest
+	// value.
+	src := NewCanvas(6, 3)
+	for i := range src.Pix {
+		src.Pix[i] = color.RGBA{R: uint8(i), A: 255}
+	}
+	dst := NewCanvas(2, 1)
+	src.Downscale(dst)
+	if dst.Pix[0] != (color.RGBA{R: 7, A: 255}) || dst.Pix[1] != (color.RGBA{R: 10, A: 255}) {
+		t.Errorf("unexpected downscaled pixels: %v", dst.Pix)
+	}
+
+	// A factor of 1 copies the canvas.
+	same := NewCanvas(6, 3)
+	src.Downscale(same)
+	for i, c := range same.Pix {
+		if c != src.Pix[i] {
+			t.Fatalf("factor 1: pixel %d: expected %v, got %v", i, src.Pix[i], c)
+		}
+	}
+
+	// An empty destination is left alone, and sizes that aren't a multiple
+	// of the destination size panic.
+	src.Downscale(NewCanvas(0, 0))
+	for _, size := range [][2]int{{4, 1}, {3, 2}, {12, 6}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%dx%d: expected a panic", size[0], size[1])
+				}
+			}()
+			src.Downscale(NewCanvas(size[0], size[1]))
+		}()
+	}
+}
+
+func TestCanvasDrawDithered(t *testing.T) {
+	// An exact 8-bit color isn't changed by dithering.
+	c := NewCanvas(8, 8)
+	src := image.NewRGBA64(image.Rect(2, 3, 12, 10))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.RGBA{10, 20, 30, 255}), image.Point{}, draw.Src)
+	c.DrawDithered(src)
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			expected := color.RGBA{10, 20, 30, 255}
+			if y == 7 {
+				expected = color.RGBA{} // outside of src
+			}
+			if col := c.RGBAAt(x, y); col != expected {
+				t.Errorf("pixel (%d, %d): expected %v, got %v", x, y, expected, col)
+			}
+		}
+	}
+
+	// A 16-bit color between two 8-bit values is spread over both, with the
+	// same average.
+	c = NewCanvas(16, 16)
+	frame := make(Frame[Color48], 16*16)
+	frame.Fill(Color48{R: 100*257 + 64, G: 200*257 + 128, B: 3 * 257})
+	c.DrawDithered48(frame)
+	var r, g, b int
+	for _, p := range c.Pix {
+		if p.R < 100 || p.R > 101 || p.G < 200 || p.G > 201 || p.B != 3 {
+			t.Fatalf("unexpected dithered color: %v", p)
+		}
+		r += int(p.R)
+		g += int(p.G)
+		b += int(p.B)
+	}
+	// Some of the error is lost at the edges, so allow a small difference.
+	if abs(r*257-256*(100*257+64)) > 8*257 || abs(g*257-256*(200*257+128)) > 8*257 || b != 256*3 {
+		t.Errorf("expected the same average color, got sums %d, %d, %d", r, g, b)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		c.DrawDithered48(frame)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestCanvasDrawDot(t *testing.T) {
+	col := color.RGBA{R: 200}
+	for _, tc := range []struct {
+		x, y     int32
+		expected []uint8 // red channel, row by row
+	}{
+		{1 << 8, 1 << 8, []uint8{0, 0, 0, 0, 200, 0, 0, 0, 0}},
+		{1<<8 + 128, 1<<8 + 64, []uint8{0, 0, 0, 0, 75, 75, 0, 25, 25}},
+		{2<<8 + 128, 0, []uint8{0, 0, 100, 0, 0, 0, 0, 0, 0}},  // half off the right edge
+		{-128, -128, []uint8{50, 0, 0, 0, 0, 0, 0, 0, 0}},      // off the top left corner
+		{-1 << 8, 1 << 8, []uint8{0, 0, 0, 0, 0, 0, 0, 0, 0}},  // left of the canvas
+		{1 << 8, 3 << 8, []uint8{0, 0, 0, 0, 0, 0, 0, 0, 0}},   // below the canvas
+		{-5 << 8, -5 << 8, []uint8{0, 0, 0, 0, 0, 0, 0, 0, 0}}, // far outside
+	} {
+		c := NewCanvas(3, 3)
+		c.DrawDot(tc.x, tc.y, col)
+		for i, r := range tc.expected {
+			if c.Pix[i] != (color.RGBA{R: r}) {
+				t.Errorf("DrawDot(%#x, %#x): pixel (%d, %d): expected red %d, got %v", tc.x, tc.y, i%3, i/3, r, c.Pix[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// Clock is an animation clock. Effects that take their time from a Clock
+// instead of directly from the system time can be paused, slowed down, sped up
+// or moved to a specific point in time all at once.
+//
+// A Clock is not safe for concurrent use.
+type Clock struct {
+	source   func() time.Duration
+	speed    uint16        // 8.8 fixed-point multiplier
+	paused   bool          // whether the clock is paused
+	anchor   time.Duration // animation time at the source time below
+	sourceAt time.Duration // source time when the anchor was last updated
+	lastTick time.Duration // animation time of the last call to Tick
+}
+
+// NewClock returns a new running clock at time zero and normal speed. The
+// source returns the current (real) time and must be monotonic. If it is nil,
+// the time since the creation of the clock is used.
+func NewClock(source func() time.Duration) *Clock {
+	if source == nil {
+		start := time.Now()
+		source = func() time.Duration {
+			return time.Since(start)
+		}
+	}
+	return &Clock{
+		source:   source,
+		speed:    0x100,
+		sourceAt: source(),
+	}
+}
+
+// Now returns the current animation time.
+func (c *Clock) Now() time.Duration {
+	if c.paused {
+		return c.anchor
+	}
+	return c.anchor + scaleDuration(c.source()-c.sourceAt, c.speed)
+}
+
+// Tick returns the current animation time and the animation time that has
+// passed since the previous call to Tick. It is meant to be called once per
+// frame, for effects that need a delta time (for example for physics).
+func (c *Clock) Tick() (now, delta time.Duration) {
+	now = c.Now()
+	delta = now - c.lastTick
+	c.lastTick = now
+	return now, delta
+}
+
+// Set moves the clock to the given animation time, for example to scrub
+// through a show.
+func (c *Clock) Set(t time.Duration) {
+	c.anchor = t
+	c.sourceAt = c.source()
+	c.lastTick = t
+}
+
+// Pause freezes the clock at the current animation time.
+func (c *Clock) Pause() {
+	if !c.paused {
+		c.anchor = c.Now()
+		c.paused = true
+	}
+}
+
+// Resume continues a paused clock from the time at which it was paused.
+func (c *Clock) Resume() {
+	if c.paused {
+		c.sourceAt = c.source()
+		c.paused = false
+	}
+}
+
+// Paused returns whether the clock is currently paused.
+func (c *Clock) Paused() bool {
+	return c.paused
+}
+
+// Speed returns the current speed as a 8.8 fixed-point multiplier.
+func (c *Clock) Speed() uint16 {
+	return c.speed
+}
+
+// SetSpeed changes the speed at which the clock runs, as a 8.8 fixed-point
+// multiplier: 0x100 is the normal speed, 0x80 is half speed and 0x200 is
+// double speed.
+func (c *Clock) SetSpeed(speed uint16) {
+	c.anchor = c.Now()
+	c.sourceAt = c.source()
+	c.speed = speed
+}
+
+// scaleDuration multiplies d by the 8.8 fixed-point value scale, without
+// overflowing for long durations.
+func scaleDuration(d time.Duration, scale uint16) time.Duration {
+	return (d>>8)*time.Duration(scale) + (d&0xff)*time.Duration(scale)>>8
+}
@@ -0,0 +1,48 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	var now time.Duration
+	clock := NewClock(func() time.Duration {
+		return now
+	})
+	check := func(expected time.Duration) {
+		t.Helper()
+		if actual := clock.Now(); actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+
+	now += time.Second
+	check(time.Second)
+
+	clock.Pause()
+	now += time.Second
+	check(time.Second)
+	clock.Resume()
+	now += time.Second
+	check(2 * time.Second)
+
+	clock.SetSpeed(0x80) // half speed
+	now += time.Second
+	check(2500 * time.Millisecond)
+
+	clock.SetSpeed(0x200) // double speed
+	now += time.Second
+	check(4500 * time.Millisecond)
+
+	clock.Set(time.Minute)
+	_, delta := clock.Tick()
+	if delta != 0 {
+		t.Errorf("expected no delta after Set, got %s", delta)
+	}
+	now += time.Second
+	_, delta = clock.Tick()
+	if delta != 2*time.Second {
+		t.Errorf("expected a delta of 2s, got %s", delta)
+	}
+}
@@ -0,0 +1,161 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+// RingClock is an Effect that shows an analog clock on a LED ring: the hour,
+// minute and second hands are dots that move smoothly around the ring. The
+// first LED of the ring (at angle Start) is twelve o'clock, and the angle
+// increases clockwise.
+type RingClock struct {
+	Ring Ring
+
+	// Now returns the time to show. If it is nil, time.Now is used. Setting
+	// it allows for example a time from a RTC or a simulated time.
+	Now func() time.Time
+
+	// Colors of the hour, minute and second hands. A black hand is not shown.
+	Hour, Minute, Second color.RGBA
+
+	// Marks is the color of the LEDs at every hour, drawn below the hands.
+	// If it is black, there are no marks.
+	Marks color.RGBA
+}
+
+// Render implements Effect. Only the LEDs of the ring are changed.
+func (c *RingClock) Render(frame Strip, t time.Duration) {
+	now := clockNow(c.Now)
+	for i := max(c.Ring.Offset, 0); i < c.Ring.Offset+c.Ring.Count && i < len(frame); i++ {
+		frame[i] = color.RGBA{}
+	}
+	if c.Marks != (color.RGBA{}) {
+		for hour := uint32(0); hour < 12; hour++ {
+			if i := c.Ring.Index(c.Ring.Start + uint16(hour*0x10000/12)); i >= 0 && i < len(frame) {
+				frame[i] = c.Marks
+			}
+		}
+	}
+
+	// Angles of the hands, where the hours include the minutes and so on so
+	// that all hands move smoothly.
+	hour, minute, sec := now.Clock()
+	ms := uint64(sec)*1000 + uint64(now.Nanosecond())/1e6              // milliseconds in this minute
+	ms += uint64(minute) * 60e3                                        // milliseconds in this hour
+	hourAngle := uint16((uint64(hour%12)*3600e3 + ms) << 16 / 43200e3) // 12 hours
+	minuteAngle := uint16(ms << 16 / 3600e3)
+	secondAngle := uint16(ms % 60e3 << 16 / 60e3)
+	for _, hand := range [3]struct {
+		angle uint16
+		c     color.RGBA
+	}{{hourAngle, c.Hour}, {minuteAngle, c.Minute}, {secondAngle, c.Second}} {
+		if hand.c != (color.RGBA{}) {
+			c.Ring.Set(frame, c.Ring.Start+hand.angle, hand.c)
+		}
+	}
+}
+
+// DigitalClock is an Effect that shows the time as digits on a LED matrix of
+// at least 25 by 7 pixels, such as the common 32 by 8 matrices. The frame is
+// treated as a Canvas of the given size: the LEDs are row by row, starting at
+// the top left. The time is centered on the matrix.
+type DigitalClock struct {
+	Width, Height int
+
+	// Now returns the time to show. If it is nil, time.Now is used.
+	Now func() time.Time
+
+	// Color is the color of the digits.
+	Color color.RGBA
+
+	// Hour12 shows the time with a 12-hour clock instead of a 24-hour clock.
+	Hour12 bool
+
+	// Blink makes the colon blink every second.
+	Blink bool
+}
+
+// Params implements ParamEffect.
+func (c *DigitalClock) Params() []Param {
+	return []Param{
+		{Name: "12-hour", Min: 0, Max: 1, Default: 0, Value: &c.Hour12},
+		{Name: "Blink", Min: 0, Max: 1, Default: 0, Value: &c.Blink},
+	}
+}
+
+// Render implements Effect.
+func (c *DigitalClock) Render(frame Strip, t time.Duration) {
+	canvas := frameCanvas(frame, c.Width, c.Height)
+	canvas.Fill(color.RGBA{})
+
+	now := clockNow(c.Now)
+	hour, minute, _ := now.Clock()
+	if c.Hour12 {
+		hour = (hour+11)%12 + 1
+	}
+	digits := [4]int{hour / 10, hour % 10, minute / 10, minute % 10}
+
+	// Four digits with a space between them, and a colon in the middle.
+	const width = 4*(clockDigitWidth+1) + 2 - 1
+	x := (c.Width - width) / 2
+	y := (c.Height - clockDigitHeight) / 2
+	for i, digit := range digits {
+		if i == 0 && digit == 0 && c.Hour12 {
+			x += clockDigitWidth + 1 // no leading zero, like most 12-hour clocks
+			continue
+		}
+		drawClockGlyph(&canvas, x, y, &clockDigits[digit], clockDigitWidth, c.Color)
+		x += clockDigitWidth + 1
+		if i == 1 {
+			if !c.Blink || now.Nanosecond() < 5e8 {
+				drawClockGlyph(&canvas, x, y, &clockColon, 1, c.Color)
+			}
+			x += 2
+		}
+	}
+}
+
+// clockNow returns the current time from the given time provider.
+func clockNow(now func() time.Time) time.Time {
+	if now == nil {
+		return time.Now()
+	}
+	return now()
+}
+
+// drawClockGlyph draws a glyph of the clock font with its top left corner at
+// (x, y). Every row of the glyph has one bit per pixel, where the lowest bit
+// is the rightmost pixel.
+func drawClockGlyph(c *Canvas, x, y int, glyph *[clockDigitHeight]uint8, width int, col color.RGBA) {
+	for row, bits := range glyph {
+		for i := 0; i < width; i++ {
+			if bits>>(width-1-i)&1 != 0 {
+				c.SetRGBA(x+i, y+row, col)
+			}
+		}
+	}
+}
+
+// Size of the digits of the clock font.
+const (
+	clockDigitWidth  = 5
+	clockDigitHeight = 7
+)
+
+// clockDigits is a 5 by 7 pixel font with the digits 0 to 9.
+var clockDigits = [10][clockDigitHeight]uint8{
+	{0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	{0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	{0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	{0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	{0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	{0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	{0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	{0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	{0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	{0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+}
+
+// clockColon is the colon between the hours and minutes, one pixel wide.
+var clockColon = [clockDigitHeight]uint8{0, 0, 1, 0, 1, 0, 0}
@@ -0,0 +1,121 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestRingClock(t *testing.T) {
+	now := time.Date(2024, 5, 1, 15, 30, 15, 0, time.UTC)
+	red, green, blue, gray := color.RGBA{R: 255}, color.RGBA{G: 255}, color.RGBA{B: 255}, color.RGBA{10, 10, 10, 0}
+	c := &RingClock{
+		Ring:   Ring{Offset: 2, Count: 60},
+		Now:    func() time.Time { return now },
+		Hour:   red,
+		Minute: green,
+		Second: blue,
+		Marks:  gray,
+	}
+	frame := make(Strip, 64)
+	frame[0] = color.RGBA{R: 1}
+	c.Render(frame, 0)
+
+	// 15:30 is halfway between 3 and 4 on the clock, which is LED 17.5 of
+	// the ring. The minute hand is just past LED 30 and the second hand at LED 15.
+	if frame[0] != (color.RGBA{R: 1}) || frame[62] != (color.RGBA{}) {
+		t.Error("LEDs outside of the ring were changed")
+	}
+	if frame[2+17].R < 120 || frame[2+18].R < 120 {
+		t.Errorf("hour hand: expected LEDs 17 and 18 to be red, got %v and %v", frame[2+17], frame[2+18])
+	}
+	if frame[2+30].G < 180 || frame[2+31].G == 0 {
+		t.Errorf("minute hand: expected LED 30 to be green, got %v", frame[2+30])
+	}
+	if frame[2+15].B < 250 || frame[2+15].R != 10 {
+		t.Errorf("second hand: expected LED 15 to be blue on a mark, got %v", frame[2+15])
+	}
+	if frame[2+5] != gray || frame[2+6] != (color.RGBA{}) {
+		t.Errorf("marks: unexpected colors %v and %v", frame[2+5], frame[2+6])
+	}
+}
+
+func TestRingClockShortFrame(t *testing.T) {
+	// A ring that doesn't fit in the frame only draws the LEDs in the frame.
+	// At 15:30:15, the hour hand is at LED 7 of a 24 LED ring, the minute hand
+	// at LED 12 and the second hand at LED 6.
+	now := time.Date(2024, 5, 1, 15, 30, 15, 0, time.UTC)
+	c := &RingClock{
+		Ring:   Ring{Count: 24},
+		Now:    func() time.Time { return now },
+		Hour:   color.RGBA{R: 255},
+		Minute: color.RGBA{G: 255},
+		Second: color.RGBA{B: 255},
+		Marks:  color.RGBA{10, 10, 10, 0},
+	}
+	frame := make(Strip, 12)
+	c.Render(frame, 0)
+	if frame[7].R == 0 || frame[6].B == 0 {
+		t.Errorf("expected the hour and second hands in the frame, got %v", frame)
+	}
+	for i, col := range frame {
+		if col.G > 10 {
+			t.Errorf("LED %d: the minute hand is outside of the frame, got %v", i, col)
+		}
+	}
+
+	// The same with a ring that starts before the frame.
+	c.Ring.Offset = -12
+	frame.FillSolid(color.RGBA{1, 1, 1, 0})
+	c.Render(frame, 0)
+	if frame[0].G < 200 {
+		t.Errorf("expected the minute hand at LED 0, got %v", frame[0])
+	}
+	for i, col := range frame {
+		if col == (color.RGBA{1, 1, 1, 0}) {
+			t.Errorf("LED %d was not cleared", i)
+		}
+	}
+}
+
+func TestDigitalClock(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC)
+	white := color.RGBA{255, 255, 255, 0}
+	c := &DigitalClock{Width: 32, Height: 8, Now: func() time.Time { return now }, Color: white}
+	frame := make(Strip, 32*8)
+	c.Render(frame, 0)
+	canvas := &Canvas{Width: 32, Height: 8, Pix: frame}
+	text := func(y int) string {
+		s := ""
+		for x := 0; x < 32; x++ {
+			if canvas.RGBAAt(x, y) == white {
+				s += "#"
+			} else {
+				s += "."
+			}
+		}
+		return s
+	}
+	// The first row of "12:34", centered with 3 columns on the left.
+	if row := text(0); row != ".....#....###....#####....#....." {
+		t.Errorf("row 0: got %s", row)
+	}
+	if canvas.RGBAAt(15, 2) != white || canvas.RGBAAt(15, 4) != white || canvas.RGBAAt(15, 3) != (color.RGBA{}) {
+		t.Error("expected the colon at x=15")
+	}
+	if row := text(7); row != "................................" {
+		t.Errorf("row 7: expected nothing, got %s", row)
+	}
+
+	// A 12-hour clock has no leading zero, and a blinking colon is off in the
+	// second half of every second.
+	now = time.Date(2024, 5, 1, 21, 5, 0, 6e8, time.UTC)
+	c.Hour12, c.Blink = true, true
+	c.Render(frame, 0)
+	if row := text(0); row != "..........###.....###..#####...." {
+		t.Errorf("12-hour row 0: got %s", row)
+	}
+	if canvas.RGBAAt(15, 2) != (color.RGBA{}) {
+		t.Error("expected the colon to be off")
+	}
+}
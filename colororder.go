@@ -0,0 +1,125 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// ColorOrder is the order in which color channels are sent to the LEDs. Most
+// LED chips don't use the RGB order, and chips of different brands often use
+// different orders.
+type ColorOrder uint8
+
+// Color orders used by common LED chips. The RGBW variants have an extra white
+// channel, which is derived from the RGB color (see Append) or set directly
+// (see AppendRGBW).
+const (
+	OrderRGB ColorOrder = iota
+	OrderRBG
+	OrderGRB // WS2812, SK6812
+	OrderGBR
+	OrderBRG
+	OrderBGR // APA102
+	OrderRGBW
+	OrderGRBW // SK6812 RGBW
+	OrderBGRW
+	OrderWRGB
+)
+
+// Channels returns the number of bytes used per LED: 3 for RGB orders and 4 for
+// RGBW orders.
+func (o ColorOrder) Channels() int {
+	if o >= OrderRGBW {
+		return 4
+	}
+	return 3
+}
+
+// Append appends the color in this channel order to buf and returns the
+// resulting slice. For RGBW orders, the white channel is the common part of
+// the red, green and blue channels, which is removed from these channels.
+func (o ColorOrder) Append(buf []byte, c color.RGBA) []byte {
+	switch o {
+	case OrderRGB:
+		return append(buf, c.R, c.G, c.B)
+	case OrderRBG:
+		return append(buf, c.R, c.B, c.G)
+	case OrderGRB:
+		return append(buf, c.G, c.R, c.B)
+	case OrderGBR:
+		return append(buf, c.G, c.B, c.R)
+	case OrderBRG:
+		return append(buf, c.B, c.R, c.G)
+	case OrderBGR:
+		return append(buf, c.B, c.G, c.R)
+	}
+	return o.AppendRGBW(buf, RGBWFromRGBA(c))
+}
+
+// AppendRGBW appends the RGBW color in this channel order to buf and returns
+// the resulting slice. For RGB orders, the white channel is added to the red,
+// green and blue channels.
+func (o ColorOrder) AppendRGBW(buf []byte, c ColorRGBW) []byte {
+	if o.Channels() == 3 {
+		return o.Append(buf, c.RGBA8())
+	}
+	switch o {
+	case OrderGRBW:
+		return append(buf, c.G, c.R, c.B, c.W)
+	case OrderBGRW:
+		return append(buf, c.B, c.G, c.R, c.W)
+	case OrderWRGB:
+		return append(buf, c.W, c.R, c.G, c.B)
+	default: // OrderRGBW
+		return append(buf, c.R, c.G, c.B, c.W)
+	}
+}
+
+// AppendStrip appends all colors of the strip in this channel order to buf and
+// returns the resulting slice.
+func (o ColorOrder) AppendStrip(buf []byte, s Strip) []byte {
+	for _, c := range s {
+		buf = o.Append(buf, c)
+	}
+	return buf
+}
+
+// Decode returns the color stored in buf in this channel order, which is the
+// reverse of Append. The buffer must be at least Channels() bytes long. For
+// RGBW orders, the white channel is added to the red, green and blue channels.
+func (o ColorOrder) Decode(buf []byte) color.RGBA {
+	var r, g, b, w uint8
+	switch o {
+	case OrderRGB:
+		r, g, b = buf[0], buf[1], buf[2]
+	case OrderRBG:
+		r, b, g = buf[0], buf[1], buf[2]
+	case OrderGRB:
+		g, r, b = buf[0], buf[1], buf[2]
+	case OrderGBR:
+		g, b, r = buf[0], buf[1], buf[2]
+	case OrderBRG:
+		b, r, g = buf[0], buf[1], buf[2]
+	case OrderBGR:
+		b, g, r = buf[0], buf[1], buf[2]
+	case OrderGRBW:
+		g, r, b, w = buf[0], buf[1], buf[2], buf[3]
+	case OrderBGRW:
+		b, g, r, w = buf[0], buf[1], buf[2], buf[3]
+	case OrderWRGB:
+		w, r, g, b = buf[0], buf[1], buf[2], buf[3]
+	default: // OrderRGBW
+		r, g, b, w = buf[0], buf[1], buf[2], buf[3]
+	}
+	return color.RGBA{QAdd8(r, w), QAdd8(g, w), QAdd8(b, w), 0}
+}
+
+// DecodeStrip decodes as many colors from buf as fit in the strip (and as are
+// available in buf) and returns the number of decoded colors.
+func (o ColorOrder) DecodeStrip(s Strip, buf []byte) int {
+	channels := o.Channels()
+	n := min(len(s), len(buf)/channels)
+	for i := 0; i < n; i++ {
+		s[i] = o.Decode(buf[i*channels:])
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package ledsgo
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func TestColorOrderRoundTrip(t *testing.T) {
+	c := color.RGBA{R: 10, G: 20, B: 30}
+	tests := []struct {
+		order    ColorOrder
+		expected []byte
+	}{
+		{OrderRGB, []byte{10, 20, 30}},
+		{OrderRBG, []byte{10, 30, 20}},
+		{OrderGRB, []byte{20, 10, 30}},
+		{OrderGBR, []byte{20, 30, 10}},
+		{OrderBRG, []byte{30, 10, 20}},
+		{OrderBGR, []byte{30, 20, 10}},
+		{OrderRGBW, []byte{0, 10, 20, 10}},
+		{OrderGRBW, []byte{10, 0, 20, 10}},
+		{OrderBGRW, []byte{20, 10, 0, 10}},
+		{OrderWRGB, []byte{10, 0, 10, 20}},
+	}
+	for _, tc := range tests {
+		buf := tc.order.Append([]byte{0xff}, c)
+		if !bytes.Equal(buf[1:], tc.expected) || buf[0] != 0xff {
+			t.Errorf("order %d: expected %v, got %v", tc.order, tc.expected, buf[1:])
+		}
+		if len(buf)-1 != tc.order.Channels() {
+			t.Errorf("order %d: expected %d channels, got %d bytes", tc.order, tc.order.Channels(), len(buf)-1)
+		}
+		if decoded := tc.order.Decode(buf[1:]); decoded != c {
+			t.Errorf("order %d: expected %v after decoding, got %v", tc.order, c, decoded)
+		}
+	}
+}
+
+func TestColorOrderRGBW(t *testing.T) {
+	c := ColorRGBW{R: 1, G: 2, B: 3, W: 4}
+	tests := []struct {
+		order    ColorOrder
+		expected []byte
+	}{
+		{OrderRGBW, []byte{1, 2, 3, 4}},
+		{OrderGRBW, []byte{2, 1, 3, 4}},
+		{OrderBGRW, []byte{3, 2, 1, 4}},
+		{OrderWRGB, []byte{4, 1, 2, 3}},
+		{OrderRGB, []byte{5, 6, 7}}, // white is added to the other channels
+		{OrderGRB, []byte{6, 5, 7}},
+	}
+	for _, tc := range tests {
+		buf := tc.order.AppendRGBW(nil, c)
+		if !bytes.Equal(buf, tc.expected) {
+			t.Errorf("order %d: expected %v, got %v", tc.order, tc.expected, buf)
+		}
+		if decoded := tc.order.Decode(buf); decoded != (color.RGBA{5, 6, 7, 0}) {
+			t.Errorf("order %d: expected the white channel to be added when decoding, got %v", tc.order, decoded)
+		}
+	}
+
+	// Decoding saturates instead of overflowing.
+	if c := OrderRGBW.Decode([]byte{200, 100, 0, 100}); c != (color.RGBA{255, 200, 100, 0}) {
+		t.Errorf("expected a saturated color, got %v", c)
+	}
+}
+
+func TestColorOrderDecodeStrip(t *testing.T) {
+	strip := Strip{{R: 1}, {R: 2}, {R: 3}, {R: 4}}
+	buf := OrderGRBW.AppendStrip(nil, Strip{{R: 10}, {G: 20}, {B: 30}, {R: 40}})
+
+	// A short buffer only fills the start of the strip, and an incomplete
+	// color at the end is ignored.
+	if n := OrderGRBW.DecodeStrip(strip, buf[:10]); n != 2 {
+		t.Errorf("short buffer: expected 2 colors, got %d", n)
+	}
+	expected := Strip{{R: 10}, {G: 20}, {R: 3}, {R: 4}}
+	for i, c := range strip {
+		if c != expected[i] {
+			t.Errorf("short buffer: LED %d: expected %v, got %v", i, expected[i], c)
+		}
+	}
+
+	// A long buffer only fills the strip.
+	if n := OrderGRBW.DecodeStrip(strip[:3], buf); n != 3 {
+		t.Errorf("long buffer: expected 3 colors, got %d", n)
+	}
+	if strip[2] != (color.RGBA{B: 30}) || strip[3] != (color.RGBA{R: 4}) {
+		t.Errorf("long buffer: unexpected strip %v", strip)
+	}
+	if n := OrderRGB.DecodeStrip(strip, nil); n != 0 {
+		t.Errorf("empty buffer: expected 0 colors, got %d", n)
+	}
+}
@@ -0,0 +1,88 @@
+package ledsgo
+
+// CubeWiring describes the order in which the LEDs of a cube are wired.
+type CubeWiring uint8
+
+// Common wiring orders of LED cubes. In all cases the X and Y coordinates are
+// horizontal and Z is the layer, counting from the bottom.
+const (
+	// CubeRows wires each layer row by row, every row from left to right.
+	CubeRows CubeWiring = iota
+
+	// CubeSerpentine wires each layer row by row, with every other row going
+	// in the opposite direction (right to left).
+	CubeSerpentine
+
+	// CubeSnake wires the whole cube as one continuous snake: rows alternate
+	// direction in each layer and every other layer is wired back to front,
+	// so that the last LED of a layer is next to the first LED of the next.
+	CubeSnake
+
+	// CubeColumns wires the cube column by column, each column from the
+	// bottom to the top.
+	CubeColumns
+)
+
+// Cube maps 3D coordinates to LED indices for LED cubes, so that volumetric
+// effects (for example using Noise3) can be drawn directly on a strip.
+type Cube struct {
+	SizeX  int
+	SizeY  int
+	SizeZ  int
+	Wiring CubeWiring
+}
+
+// Len returns the number of LEDs in the cube.
+func (c Cube) Len() int {
+	return c.SizeX * c.SizeY * c.SizeZ
+}
+
+// Index returns the strip index of the LED at the given coordinates, or -1 if
+// the coordinates are outside of the cube.
+func (c Cube) Index(x, y, z int) int {
+	if x < 0 || y < 0 || z < 0 || x >= c.SizeX || y >= c.SizeY || z >= c.SizeZ {
+		return -1
+	}
+	switch c.Wiring {
+	case CubeSerpentine:
+		if y%2 == 1 {
+			x = c.SizeX - 1 - x
+		}
+	case CubeSnake:
+		if y%2 == 1 {
+			x = c.SizeX - 1 - x
+		}
+		i := y*c.SizeX + x
+		if z%2 == 1 {
+			// Walk this layer backwards.
+			i = c.SizeX*c.SizeY - 1 - i
+		}
+		return z*c.SizeX*c.SizeY + i
+	case CubeColumns:
+		return (y*c.SizeX+x)*c.SizeZ + z
+	}
+	return (z*c.SizeY+y)*c.SizeX + x
+}
+
+// Coords returns the coordinates of the LED with the given strip index. It is
+// the inverse of Index and is useful to loop over all LEDs of a strip while
+// calculating their position in the cube.
+func (c Cube) Coords(index int) (x, y, z int) {
+	if c.Wiring == CubeColumns {
+		z = index % c.SizeZ
+		index /= c.SizeZ
+		return index % c.SizeX, index / c.SizeX, z
+	}
+	layerSize := c.SizeX * c.SizeY
+	z = index / layerSize
+	index %= layerSize
+	if c.Wiring == CubeSnake && z%2 == 1 {
+		index = layerSize - 1 - index
+	}
+	x = index % c.SizeX
+	y = index / c.SizeX
+	if c.Wiring != CubeRows && y%2 == 1 {
+		x = c.SizeX - 1 - x
+	}
+	return x, y, z
+}
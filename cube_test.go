@@ -0,0 +1,43 @@
+package ledsgo
+
+import (
+	"testing"
+)
+
+func TestCube(t *testing.T) {
+	for _, wiring := range []CubeWiring{CubeRows, CubeSerpentine, CubeSnake, CubeColumns} {
+		cube := Cube{SizeX: 3, SizeY: 4, SizeZ: 5, Wiring: wiring}
+		seen := make([]bool, cube.Len())
+		for z := 0; z < cube.SizeZ; z++ {
+			for y := 0; y < cube.SizeY; y++ {
+				for x := 0; x < cube.SizeX; x++ {
+					index := cube.Index(x, y, z)
+					if index < 0 || index >= cube.Len() || seen[index] {
+						t.Fatalf("wiring %d: invalid or duplicate index %d for (%d, %d, %d)", wiring, index, x, y, z)
+					}
+					seen[index] = true
+					if x2, y2, z2 := cube.Coords(index); x2 != x || y2 != y || z2 != z {
+						t.Errorf("wiring %d: Coords(%d): expected (%d, %d, %d), got (%d, %d, %d)", wiring, index, x, y, z, x2, y2, z2)
+					}
+				}
+			}
+		}
+	}
+
+	// In a snake, consecutive LEDs must be neighbors.
+	cube := Cube{SizeX: 3, SizeY: 4, SizeZ: 5, Wiring: CubeSnake}
+	for i := 1; i < cube.Len(); i++ {
+		x0, y0, z0 := cube.Coords(i - 1)
+		x1, y1, z1 := cube.Coords(i)
+		if abs(x1-x0)+abs(y1-y0)+abs(z1-z0) != 1 {
+			t.Errorf("snake: LED %d at (%d, %d, %d) is not next to LED %d at (%d, %d, %d)", i-1, x0, y0, z0, i, x1, y1, z1)
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+// Package ddp implements the Distributed Display Protocol, a lightweight
+// protocol to stream pixel data over UDP. It is supported by WLED, xLights and
+// many other programs and has a lot less overhead per frame than Art-Net or
+// sACN, especially for long strips.
+package ddp
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Port is the UDP port used by DDP.
+const Port = 4048
+
+// Header flags.
+const (
+	FlagVersion1 = 0x40
+	FlagTimecode = 0x10
+	FlagStorage  = 0x08
+	FlagReply    = 0x04
+	FlagQuery    = 0x02
+	FlagPush     = 0x01 // the frame is complete and should be displayed
+
+	versionMask = 0xc0
+)
+
+// Data types of 8-bit RGB and RGBW pixel data.
+const (
+	DataTypeRGB8  = 0x0b
+	DataTypeRGBW8 = 0x1b
+)
+
+// DestinationDisplay is the default output device of a receiver.
+const DestinationDisplay = 1
+
+// MaxData is the maximum amount of pixel data in a single packet. It is a
+// multiple of both 3 and 4, so that LEDs are never split across packets.
+const MaxData = 1440
+
+// Size of the packet header, without the optional timecode.
+const headerSize = 10
+
+var errInvalidPacket = errors.New("ddp: invalid packet")
+
+// AppendPacket appends a data packet to buf and returns the resulting slice.
+// The offset is the position of the data in the frame, in bytes. The sequence
+// number should be 1-15 (wrapping around), or 0 to disable sequencing. The
+// data type is usually DataTypeRGB8 or DataTypeRGBW8.
+func AppendPacket(buf []byte, flags, sequence, dataType uint8, offset uint32, data []byte) []byte {
+	buf = append(buf,
+		FlagVersion1|flags&^versionMask,
+		sequence&0x0f,
+		dataType,
+		DestinationDisplay,
+	)
+	buf = binary.BigEndian.AppendUint32(buf, offset)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(data)))
+	return append(buf, data...)
+}
+
+// Packet is a decoded DDP data packet.
+type Packet struct {
+	Flags       uint8
+	Sequence    uint8
+	DataType    uint8
+	Destination uint8
+	Offset      uint32
+	Data        []byte // refers to the original buffer
+}
+
+// Parse decodes a DDP packet.
+func Parse(buf []byte) (Packet, error) {
+	if len(buf) < headerSize || buf[0]&versionMask != FlagVersion1 {
+		return Packet{}, errInvalidPacket
+	}
+	p := Packet{
+		Flags:       buf[0],
+		Sequence:    buf[1] & 0x0f,
+		DataType:    buf[2],
+		Destination: buf[3],
+		Offset:      binary.BigEndian.Uint32(buf[4:]),
+	}
+	data := buf[headerSize:]
+	if p.Flags&FlagTimecode != 0 {
+		if len(data) < 4 {
+			return Packet{}, errInvalidPacket
+		}
+		data = data[4:]
+	}
+	length := int(binary.BigEndian.Uint16(buf[8:]))
+	if len(data) < length {
+		return Packet{}, errInvalidPacket
+	}
+	p.Data = data[:length]
+	return p, nil
+}
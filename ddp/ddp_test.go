@@ -0,0 +1,113 @@
+package ddp
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+	"github.com/aykevl/ledsgo/internal/ledstest"
+)
+
+// packetWriter records every write as a separate packet.
+type packetWriter [][]byte
+
+func (w *packetWriter) Write(buf []byte) (int, error) {
+	*w = append(*w, append([]byte(nil), buf...))
+	return len(buf), nil
+}
+
+func TestSendReceive(t *testing.T) {
+	var packets packetWriter
+	s := &Sender{Conn: &packets}
+	frame := make(ledsgo.Strip, 500)
+	for i := range frame {
+		frame[i] = color.RGBA{R: uint8(i), G: uint8(i >> 8), B: 3}
+	}
+	if err := s.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	if len(packets) != 2 {
+		t.Fatalf("expected 2 packets, got %d", len(packets))
+	}
+
+	var frames ledstest.FrameRecorder
+	r := NewReceiver(nil, len(frame), &frames)
+	for i, packet := range packets {
+		p, err := Parse(packet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.DataType != DataTypeRGB8 {
+			t.Errorf("packet %d: expected RGB data, got data type %#x", i, p.DataType)
+		}
+		if push := p.Flags&FlagPush != 0; push != (i == len(packets)-1) {
+			t.Errorf("packet %d: unexpected push flag", i)
+		}
+		if err := r.Handle(packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame, got %d", len(frames))
+	}
+	for i, c := range frames[0] {
+		if c != frame[i] {
+			t.Errorf("LED %d: expected %v, got %v", i, frame[i], c)
+		}
+	}
+}
+
+func TestSendRGBW(t *testing.T) {
+	var packets packetWriter
+	s := &Sender{Conn: &packets, Order: ledsgo.OrderGRBW}
+	frame := ledsgo.Strip{{R: 255}, {G: 10, B: 20}, {R: 30, G: 30, B: 30}}
+	if err := s.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	if len(packets) != 1 {
+		t.Fatalf("expected 1 packet, got %d", len(packets))
+	}
+	p, err := Parse(packets[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.DataType != DataTypeRGBW8 || len(p.Data) != len(frame)*4 {
+		t.Errorf("expected %d bytes of RGBW data, got data type %#x with %d bytes", len(frame)*4, p.DataType, len(p.Data))
+	}
+
+	var frames ledstest.FrameRecorder
+	r := NewReceiver(nil, len(frame), &frames)
+	r.Order = ledsgo.OrderGRBW
+	if err := r.Handle(packets[0]); err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame, got %d", len(frames))
+	}
+	for i, c := range frames[0] {
+		if c != frame[i] {
+			t.Errorf("LED %d: expected %v, got %v", i, frame[i], c)
+		}
+	}
+}
+
+func TestReceiveOutOfRange(t *testing.T) {
+	// Packets past the end of the frame are ignored, even with offsets that
+	// don't fit in an int on 32-bit systems.
+	var frames ledstest.FrameRecorder
+	r := NewReceiver(nil, 10, &frames)
+	for _, offset := range []uint32{30, 0x7fffffff, 0xffffff00} {
+		packet := AppendPacket(nil, FlagPush, 1, DataTypeRGB8, offset, []byte{1, 2, 3})
+		if err := r.Handle(packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	for i, c := range frames[2] {
+		if c != (color.RGBA{}) {
+			t.Errorf("LED %d: expected black, got %v", i, c)
+		}
+	}
+}
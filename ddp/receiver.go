@@ -0,0 +1,81 @@
+package ddp
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Receiver receives DDP packets and shows the resulting frames on a Displayer.
+// A frame is displayed when a packet with the push flag arrives.
+type Receiver struct {
+	// Conn is the connection on which packets are received.
+	Conn net.PacketConn
+
+	// Order is the channel order used by the sender.
+	Order ledsgo.ColorOrder
+
+	// Displayer is where the received frames are sent.
+	Displayer ledsgo.Displayer
+
+	frame ledsgo.Strip
+	buf   []byte
+}
+
+// NewReceiver returns a new receiver for a frame of the given number of LEDs.
+func NewReceiver(conn net.PacketConn, numLEDs int, displayer ledsgo.Displayer) *Receiver {
+	return &Receiver{
+		Conn:      conn,
+		Displayer: displayer,
+		frame:     make(ledsgo.Strip, numLEDs),
+	}
+}
+
+// Listen starts listening for DDP packets on the default port and returns a
+// new receiver.
+func Listen(numLEDs int, displayer ledsgo.Displayer) (*Receiver, error) {
+	conn, err := net.ListenPacket("udp", ":"+strconv.Itoa(Port))
+	if err != nil {
+		return nil, err
+	}
+	return NewReceiver(conn, numLEDs, displayer), nil
+}
+
+// Serve receives and handles packets until reading from the connection fails.
+// Invalid packets are ignored.
+func (r *Receiver) Serve() error {
+	if r.buf == nil {
+		r.buf = make([]byte, 1500)
+	}
+	for {
+		n, _, err := r.Conn.ReadFrom(r.buf)
+		if err != nil {
+			return err
+		}
+		r.Handle(r.buf[:n])
+	}
+}
+
+// Handle processes a single packet. It returns an error if the packet is
+// invalid or if the frame could not be displayed.
+func (r *Receiver) Handle(packet []byte) error {
+	p, err := Parse(packet)
+	if err != nil {
+		return err
+	}
+	if p.Flags&(FlagQuery|FlagReply) != 0 || (p.Destination != DestinationDisplay && p.Destination != 0) {
+		return nil // not pixel data for us
+	}
+	channels := r.Order.Channels()
+	if p.Offset < uint32(len(r.frame)*channels) {
+		// This assumes packets start at a LED boundary, which all common
+		// senders do.
+		start := int(p.Offset) / channels
+		r.Order.DecodeStrip(r.frame[start:], p.Data)
+	}
+	if p.Flags&FlagPush != 0 {
+		return r.Displayer.Display(r.frame)
+	}
+	return nil
+}
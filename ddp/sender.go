@@ -0,0 +1,60 @@
+package ddp
+
+import (
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Sender is a ledsgo.Displayer that sends frames as DDP packets. The last
+// packet of every frame has the push flag set.
+type Sender struct {
+	// Conn is where the packets are written to, usually a UDP connection to
+	// the receiver.
+	Conn io.Writer
+
+	// Order is the channel order expected by the receiver. The data type of
+	// the packets is RGBW for RGBW orders, and RGB otherwise.
+	Order ledsgo.ColorOrder
+
+	sequence uint8
+	data     []byte
+	packet   []byte
+}
+
+// Dial returns a Sender that sends to the given host. The host may include a
+// port number, by default the DDP port is used.
+func Dial(host string) (*Sender, error) {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, strconv.Itoa(Port))
+	}
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{Conn: conn}, nil
+}
+
+// Display sends the frame to the receiver.
+func (s *Sender) Display(frame ledsgo.Strip) error {
+	s.sequence = s.sequence%15 + 1 // 1..15
+	s.data = s.Order.AppendStrip(s.data[:0], frame)
+	dataType := uint8(DataTypeRGB8)
+	if s.Order.Channels() == 4 {
+		dataType = DataTypeRGBW8
+	}
+	for offset := 0; offset < len(s.data) || offset == 0; offset += MaxData {
+		end := min(offset+MaxData, len(s.data))
+		var flags uint8
+		if end == len(s.data) {
+			flags = FlagPush
+		}
+		s.packet = AppendPacket(s.packet[:0], flags, s.sequence, dataType, uint32(offset), s.data[offset:end])
+		if _, err := s.Conn.Write(s.packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
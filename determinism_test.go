@@ -0,0 +1,143 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+// determinismHash is a FNV-1a hash. Values are added byte by byte in a fixed
+// order, so that the hash doesn't depend on the byte order or word size of the
+// platform.
+type determinismHash uint64
+
+func (h *determinismHash) add(v uint32) {
+	for i := 0; i < 4; i++ {
+		*h ^= determinismHash(uint8(v >> (8 * i)))
+		*h *= 1099511628211
+	}
+}
+
+func (h *determinismHash) addRGBA(c color.RGBA) {
+	h.add(uint32(c.R) | uint32(c.G)<<8 | uint32(c.B)<<16 | uint32(c.A)<<24)
+}
+
+// TestDeterminism locks down the exact outputs of the functions that
+// animations are built from. Installations with multiple controllers (for
+// example a microcontroller and a computer sending Art-Net) rely on these
+// being bit-identical on all platforms: 32-bit and 64-bit, big-endian and
+// little-endian, with and without assembly implementations (see the purego
+// build tag), and with TinyGo.
+//
+// If one of these hashes changes, visuals change, which is why the expected
+// values are hardcoded here instead of being recalculated.
+func TestDeterminism(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected determinismHash
+		fn       func(h *determinismHash)
+	}{
+		{"Noise1", 0x7e88c3f798ffbc08, func(h *determinismHash) {
+			for x := int32(-1 << 20); x < 1<<20; x += 0x35 {
+				h.add(uint32(Noise1(x)))
+			}
+			h.add(uint32(Noise1(-1 << 31)))
+			h.add(uint32(Noise1(1<<31 - 1)))
+		}},
+		{"Noise2", 0x79e0768d68c5698d, func(h *determinismHash) {
+			for y := int32(-1 << 18); y < 1<<18; y += 0xa31 {
+				for x := int32(-1 << 18); x < 1<<18; x += 0x1c3 {
+					h.add(uint32(Noise2(x, y)))
+				}
+			}
+		}},
+		{"FillNoise2", 0x79e0768d68c5698d, func(h *determinismHash) {
+			// Same inputs as Noise2, so the hash must be the same.
+			var row [(2<<18 + 0x1c3 - 1) / 0x1c3]int16
+			for y := int32(-1 << 18); y < 1<<18; y += 0xa31 {
+				FillNoise2(row[:], -1<<18, y, 0x1c3, 0)
+				for _, n := range row {
+					h.add(uint32(n))
+				}
+			}
+		}},
+		{"Noise2 (large)", 0x74330b8130ea3d52, func(h *determinismHash) {
+			for i := uint32(0); i < 0x10000; i++ {
+				h.add(uint32(Noise2(int32(i*0x9e3779b9), int32(i*0x7f4a7c15))))
+			}
+		}},
+		{"Noise3", 0x2a4b93a68e469d7a, func(h *determinismHash) {
+			for z := int32(-1 << 16); z < 1<<16; z += 0x1e35 {
+				for y := int32(-1 << 16); y < 1<<16; y += 0xc31 {
+					for x := int32(-1 << 16); x < 1<<16; x += 0x3c3 {
+						h.add(uint32(Noise3(x, y, z)))
+					}
+				}
+			}
+		}},
+		{"Inoise", 0x68be5fcaa857d984, func(h *determinismHash) {
+			for i := uint32(0); i < 0x4000; i++ {
+				h.add(uint32(Inoise16(i * 0x1357)))
+				h.add(uint32(Inoise16XY(i*0x2468, i*0x1111)))
+				h.add(uint32(Inoise16XYZ(i*0x3579, i*0x0f0f, i*0x2222)))
+				h.add(uint32(Inoise8(uint16(i * 97))))
+				h.add(uint32(Inoise8XY(uint16(i*31), uint16(i*53))))
+				h.add(uint32(Inoise8XYZ(uint16(i*131), uint16(i*17), uint16(i*7))))
+			}
+		}},
+		{"Sin16", 0x5a34ea1f9cd75e15, func(h *determinismHash) {
+			for i := 0; i < 0x10000; i++ {
+				h.add(uint32(Sin16(uint16(i))))
+				h.add(uint32(Cos16(uint16(i))))
+			}
+		}},
+		{"Beat", 0x871c3b20c4e6ec01, func(h *determinismHash) {
+			for ms := 0; ms < 100000; ms += 7 {
+				t := time.Duration(ms) * time.Millisecond
+				h.add(uint32(BeatSin88(120<<8, t, 0x1000, 100, 60000)))
+				h.add(uint32(Beat8(93, t)))
+			}
+		}},
+		{"Palette16", 0x315b66d3f6815771, func(h *determinismHash) {
+			for _, p := range []*Palette16{&RainbowColors, &PartyColors, &LavaColors, &OceanColors, &ForestColors, &CloudColors, &HeatColors} {
+				for i := 0; i < 256; i++ {
+					h.addRGBA(p.ColorAt(uint8(i)))
+				}
+			}
+		}},
+		{"Spectrum", 0xbdb55cfe42500e6, func(h *determinismHash) {
+			for hue := 0; hue < 0x10000; hue += 0x13 {
+				for _, sv := range [][2]uint8{{255, 255}, {255, 128}, {128, 255}, {17, 200}, {0, 99}} {
+					h.addRGBA(Color{H: uint16(hue), S: sv[0], V: sv[1]}.Spectrum())
+				}
+			}
+		}},
+		{"scaleStrip", 0xd165152f4c62f6d9, func(h *determinismHash) {
+			src := make(Strip, 256)
+			for i := range src {
+				src[i] = color.RGBA{uint8(i), uint8(i * 7), uint8(255 - i), 0}
+			}
+			dst := make(Strip, len(src))
+			for scale := 0; scale < 256; scale++ {
+				scaleStrip(dst, src, uint8(scale))
+				for _, c := range dst {
+					h.addRGBA(c)
+				}
+			}
+		}},
+		{"Rand", 0xe8dbb5d396efab4e, func(h *determinismHash) {
+			r := NewRand(1234)
+			for i := 0; i < 1000; i++ {
+				h.add(r.Uint32())
+				h.add(uint32(r.Intn(1000)))
+			}
+		}},
+	}
+	for _, test := range tests {
+		h := determinismHash(14695981039346656037)
+		test.fn(&h)
+		if h != test.expected {
+			t.Errorf("%s: expected hash %#x, got %#x", test.name, uint64(test.expected), uint64(h))
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package ledsgo
+
+import (
+	"io"
+)
+
+// Displayer is implemented by LED outputs, such as LED strip drivers or network
+// protocols that send pixel data to a remote controller.
+type Displayer interface {
+	// Display sends the frame to the LEDs. The frame must not be modified or
+	// retained by the Displayer after Display returns.
+	Display(frame Strip) error
+}
+
+// WriterDisplayer is a Displayer that writes raw color data to an io.Writer,
+// such as a SPI bus or a serial port. The channel order is applied when the
+// frame is written, so that one frame can be sent to different kinds of LEDs
+// with the same visual result.
+type WriterDisplayer struct {
+	W     io.Writer
+	Order ColorOrder
+	buf   []byte
+}
+
+// NewWriterDisplayer returns a new WriterDisplayer that writes to w in the
+// given channel order.
+func NewWriterDisplayer(w io.Writer, order ColorOrder) *WriterDisplayer {
+	return &WriterDisplayer{W: w, Order: order}
+}
+
+// Display writes the frame to the underlying writer.
+func (d *WriterDisplayer) Display(frame Strip) error {
+	d.buf = d.Order.AppendStrip(d.buf[:0], frame)
+	_, err := d.W.Write(d.buf)
+	return err
+}
+
+// DisplayFrame writes a frame of RGBW colors to the underlying writer, which
+// sets the white channel directly for RGBW orders. It implements
+// FrameDisplayer[ColorRGBW].
+func (d *WriterDisplayer) DisplayFrame(frame []ColorRGBW) error {
+	d.buf = d.buf[:0]
+	for _, c := range frame {
+		d.buf = d.Order.AppendRGBW(d.buf, c)
+	}
+	_, err := d.W.Write(d.buf)
+	return err
+}
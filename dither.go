@@ -0,0 +1,72 @@
+package ledsgo
+
+import (
+	"image"
+	"image/color"
+)
+
+// DrawDithered draws the image src on the canvas, with the top left pixel of
+// src at (0, 0). Parts of src that fall outside the canvas are ignored. The
+// colors are converted to 8 bits per channel with Floyd–Steinberg error
+// diffusion: the rounding error of every pixel is spread over the pixels to
+// the right and below it. On a LED matrix, this avoids visible bands in photos
+// and smooth gradients, especially at low brightness where every step of the
+// 8-bit values is large. Just like with Set, semi-transparent colors are
+// stored as if they were drawn over black.
+//
+// Dithering only makes a difference when src has more than 8 bits per
+// channel, such as an image.RGBA64 or a scaled image, or for a canvas with
+// 16-bit colors (see DrawDithered48).
+func (c *Canvas) DrawDithered(src image.Image) {
+	b := src.Bounds()
+	c.dither(min(b.Dx(), c.Width), min(b.Dy(), c.Height), func(x, y int) [3]int32 {
+		r, g, bl, _ := src.At(b.Min.X+x, b.Min.Y+y).RGBA()
+		return [3]int32{int32(r), int32(g), int32(bl)}
+	})
+}
+
+// DrawDithered48 is like DrawDithered, for 16-bit colors stored row by row
+// with the same width as the canvas, for example a Frame[Color48] that an
+// effect has rendered in high precision.
+func (c *Canvas) DrawDithered48(src []Color48) {
+	if c.Width == 0 {
+		return
+	}
+	c.dither(c.Width, min(c.Height, len(src)/c.Width), func(x, y int) [3]int32 {
+		p := src[y*c.Width+x]
+		return [3]int32{int32(p.R), int32(p.G), int32(p.B)}
+	})
+}
+
+// dither converts the 16-bit colors returned by at to 8-bit colors in the
+// canvas, for the given number of pixels starting at the top left.
+func (c *Canvas) dither(width, height int, at func(x, y int) [3]int32) {
+	// The errors of the current and the next row, times 16, with one extra
+	// pixel at both sides so that the edges don't need special cases.
+	n := (width + 2) * 3
+	if len(c.ditherErr) < 2*n {
+		c.ditherErr = make([]int32, 2*n)
+	}
+	cur, next := c.ditherErr[:n], c.ditherErr[n:2*n]
+	clear(cur)
+	for y := 0; y < height; y++ {
+		clear(next)
+		row := c.Pix[y*c.Width:][:width]
+		for x := range row {
+			v := at(x, y)
+			var q [3]uint8
+			for ch := range v {
+				i := (x+1)*3 + ch
+				want := v[ch] + (cur[i]+8)>>4 // .16
+				q[ch] = uint8(min(max((want+128)/257, 0), 255))
+				e := want - int32(q[ch])*257
+				cur[i+3] += e * 7
+				next[i-3] += e * 3
+				next[i] += e * 5
+				next[i+3] += e
+			}
+			row[x] = color.RGBA{q[0], q[1], q[2], 0xff}
+		}
+		cur, next = next, cur
+	}
+}
@@ -0,0 +1,74 @@
+package ledsgo
+
+import (
+	"math/bits"
+)
+
+// This file implements division by a runtime constant using a precomputed
+// reciprocal. Hardware division is slow (Cortex-M3/M4) or entirely missing
+// (Cortex-M0, AVR) on small microcontrollers, while multiplication is fast.
+// When the same divisor is used for every pixel in a frame, it pays off to
+// calculate a Divider once and use it in the inner loop.
+//
+// The method used is from "Division by Invariant Integers using
+// Multiplication" by Granlund and Montgomery, which gives exact results for
+// all inputs.
+
+// Divider divides 32-bit values by a constant divisor. It uses a 32x32→64 bit
+// multiplication, which is a single instruction on Cortex-M3 and up.
+type Divider struct {
+	m   uint32
+	sh1 uint8
+	sh2 uint8
+}
+
+// NewDivider returns a Divider for the given divisor. It panics when the
+// divisor is zero.
+func NewDivider(d uint32) Divider {
+	if d == 0 {
+		panic("ledsgo: division by zero")
+	}
+	l := uint(bits.Len32(d - 1)) // ceil(log2(d))
+	m := uint32((uint64(1)<<32*(uint64(1)<<l-uint64(d)))/uint64(d)) + 1
+	return Divider{
+		m:   m,
+		sh1: uint8(min(l, 1)),
+		sh2: uint8(max(int(l)-1, 0)),
+	}
+}
+
+// Div returns x divided by the divisor, rounded down.
+func (d Divider) Div(x uint32) uint32 {
+	t := uint32(uint64(x) * uint64(d.m) >> 32)
+	return (t + (x-t)>>d.sh1) >> d.sh2
+}
+
+// Divider16 divides 16-bit values by a constant divisor. Unlike Divider, it
+// only needs a 32x32→32 bit multiplication, which makes it the better choice
+// on Cortex-M0.
+type Divider16 struct {
+	m   uint32
+	sh1 uint8
+	sh2 uint8
+}
+
+// NewDivider16 returns a Divider16 for the given divisor. It panics when the
+// divisor is zero.
+func NewDivider16(d uint16) Divider16 {
+	if d == 0 {
+		panic("ledsgo: division by zero")
+	}
+	l := uint(bits.Len16(d - 1)) // ceil(log2(d))
+	m := uint32((uint32(1)<<16*(uint32(1)<<l-uint32(d)))/uint32(d)) + 1
+	return Divider16{
+		m:   m,
+		sh1: uint8(min(l, 1)),
+		sh2: uint8(max(int(l)-1, 0)),
+	}
+}
+
+// Div returns x divided by the divisor, rounded down.
+func (d Divider16) Div(x uint16) uint16 {
+	t := uint32(x) * d.m >> 16
+	return uint16((t + (uint32(x)-t)>>d.sh1) >> d.sh2)
+}
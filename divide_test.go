@@ -0,0 +1,42 @@
+package ledsgo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDivider16(t *testing.T) {
+	for _, d := range []uint16{1, 2, 3, 5, 7, 10, 255, 256, 257, 1000, 32767, 32768, 32769, 46360, 65535} {
+		div := NewDivider16(d)
+		for x := 0; x < 0x10000; x++ {
+			if q := div.Div(uint16(x)); q != uint16(x)/d {
+				t.Fatalf("%d / %d: expected %d, got %d", x, d, uint16(x)/d, q)
+			}
+		}
+	}
+}
+
+func TestDivider(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	divisors := []uint32{1, 2, 3, 7, 10, 641, 46360, 64120, 1 << 31, 1<<31 + 1, 0xffffffff}
+	for i := 0; i < 100; i++ {
+		divisors = append(divisors, r.Uint32()>>uint(r.Intn(32)))
+	}
+	for _, d := range divisors {
+		if d == 0 {
+			continue
+		}
+		div := NewDivider(d)
+		for _, x := range []uint32{0, 1, d - 1, d, d + 1, 0xffffffff, 0xfffffffe} {
+			if q := div.Div(x); q != x/d {
+				t.Errorf("%d / %d: expected %d, got %d", x, d, x/d, q)
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			x := r.Uint32()
+			if q := div.Div(x); q != x/d {
+				t.Fatalf("%d / %d: expected %d, got %d", x, d, x/d, q)
+			}
+		}
+	}
+}
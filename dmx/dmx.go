@@ -0,0 +1,127 @@
+// Package dmx packs LED colors into DMX512 channel data for conventional DMX
+// fixtures, which use a variety of channel layouts (profiles). The resulting
+// universes can be sent using any DMX transport, such as Art-Net, sACN or a
+// DMX interface.
+package dmx
+
+import (
+	"image/color"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Channels is the number of channels in a DMX universe.
+const Channels = 512
+
+// Universe contains the channel data of a single DMX universe. Channel 1 is at
+// index 0.
+type Universe [Channels]byte
+
+// Profile is the channel layout of a fixture.
+type Profile uint8
+
+// Common fixture profiles.
+const (
+	RGB        Profile = iota // red, green, blue
+	RGBW                      // red, green, blue, white
+	DimmerRGB                 // master dimmer, red, green, blue
+	RGBDimmer                 // red, green, blue, master dimmer
+	DimmerRGBW                // master dimmer, red, green, blue, white
+)
+
+// Channels returns the number of DMX channels used by a fixture with this
+// profile.
+func (p Profile) Channels() int {
+	switch p {
+	case RGB:
+		return 3
+	case DimmerRGBW:
+		return 5
+	default:
+		return 4
+	}
+}
+
+// Append appends the channel values for a single fixture to buf and returns
+// the resulting slice.
+//
+// For profiles with a white channel, the common part of the red, green and
+// blue channels is sent to the white channel. For profiles with a dimmer, the
+// dimmer channel is set to the brightest channel and the colors are scaled up
+// accordingly, which gives fixtures with a coarse color resolution the best
+// color accuracy.
+func (p Profile) Append(buf []byte, c color.RGBA) []byte {
+	switch p {
+	case RGB:
+		return append(buf, c.R, c.G, c.B)
+	case RGBW:
+		return ledsgo.OrderRGBW.Append(buf, c)
+	}
+
+	dimmer, c := splitDimmer(c)
+	switch p {
+	case RGBDimmer:
+		return append(buf, c.R, c.G, c.B, dimmer)
+	case DimmerRGBW:
+		return ledsgo.OrderRGBW.Append(append(buf, dimmer), c)
+	default: // DimmerRGB
+		return append(buf, dimmer, c.R, c.G, c.B)
+	}
+}
+
+// splitDimmer splits the color into a dimmer value (the brightest channel) and
+// a color at full brightness.
+func splitDimmer(c color.RGBA) (uint8, color.RGBA) {
+	dimmer := max(c.R, c.G, c.B)
+	if dimmer == 0 {
+		return 0, color.RGBA{}
+	}
+	scale := func(v uint8) uint8 {
+		return uint8((uint16(v)*255 + uint16(dimmer)/2) / uint16(dimmer))
+	}
+	return dimmer, color.RGBA{scale(c.R), scale(c.G), scale(c.B), 0}
+}
+
+// checkAddress panics if the address is not a valid DMX address.
+func checkAddress(address int) {
+	if address < 1 || address > Channels {
+		panic("dmx: invalid address")
+	}
+}
+
+// FixturesPerUniverse returns how many fixtures with the given profile fit in
+// a single universe, when starting at the given (1-based) address. It panics if
+// the address is not in the range 1..512.
+func FixturesPerUniverse(profile Profile, address int) int {
+	checkAddress(address)
+	return (Channels - (address - 1)) / profile.Channels()
+}
+
+// Pack packs the frame into DMX universes, one fixture for every LED. The first
+// fixture starts at the given (1-based) address of the first universe and the
+// other fixtures follow directly after it. Fixtures are never split across
+// universes: when a fixture doesn't fit in the rest of a universe, it starts
+// at address 1 of the next universe instead.
+//
+// The universes are stored in dst, which is extended when needed. The
+// resulting slice is returned. Channels that are not used by any fixture are
+// left unchanged. It panics if the address is not in the range 1..512.
+func Pack(dst []Universe, frame ledsgo.Strip, profile Profile, address int) []Universe {
+	checkAddress(address)
+	channels := profile.Channels()
+	index := 0
+	offset := address - 1
+	var buf [8]byte
+	for _, c := range frame {
+		if offset+channels > Channels {
+			index++
+			offset = 0
+		}
+		for index >= len(dst) {
+			dst = append(dst, Universe{})
+		}
+		copy(dst[index][offset:], profile.Append(buf[:0], c))
+		offset += channels
+	}
+	return dst
+}
@@ -0,0 +1,79 @@
+package dmx
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+)
+
+func TestProfiles(t *testing.T) {
+	c := color.RGBA{R: 100, G: 50, B: 150}
+	for _, tc := range []struct {
+		profile  Profile
+		expected []byte
+	}{
+		{RGB, []byte{100, 50, 150}},
+		{RGBW, []byte{50, 0, 100, 50}},
+		{DimmerRGB, []byte{150, 170, 85, 255}},
+		{RGBDimmer, []byte{170, 85, 255, 150}},
+		{DimmerRGBW, []byte{150, 85, 0, 170, 85}},
+	} {
+		buf := tc.profile.Append(nil, c)
+		if !bytes.Equal(buf, tc.expected) {
+			t.Errorf("profile %d: expected %v, got %v", tc.profile, tc.expected, buf)
+		}
+		if len(buf) != tc.profile.Channels() {
+			t.Errorf("profile %d: expected %d channels, got %d", tc.profile, tc.profile.Channels(), len(buf))
+		}
+	}
+}
+
+func TestPack(t *testing.T) {
+	frame := make(ledsgo.Strip, 130)
+	for i := range frame {
+		frame[i] = color.RGBA{R: uint8(i), G: 1, B: 2, A: 0}
+	}
+	universes := Pack(nil, frame, RGBW, 9)
+	if len(universes) != 2 {
+		t.Fatalf("expected 2 universes, got %d", len(universes))
+	}
+	// 126 fixtures fit in the first universe, starting at channel 9.
+	if n := FixturesPerUniverse(RGBW, 9); n != 126 {
+		t.Errorf("expected 126 fixtures per universe, got %d", n)
+	}
+	if !bytes.Equal(universes[0][8:12], []byte{0, 1, 2, 0}) {
+		t.Errorf("unexpected first fixture: %v", universes[0][8:12])
+	}
+	if !bytes.Equal(universes[1][0:4], []byte{125, 0, 1, 1}) {
+		t.Errorf("unexpected first fixture in the second universe: %v", universes[1][0:4])
+	}
+}
+
+func TestPackAddress(t *testing.T) {
+	frame := ledsgo.Strip{{R: 1, G: 2, B: 3}, {R: 4, G: 5, B: 6}}
+
+	// The first and last address of a universe are valid. A fixture that
+	// starts at the last address doesn't fit, so it starts in the next
+	// universe.
+	universes := Pack(nil, frame, RGB, 1)
+	if len(universes) != 1 || !bytes.Equal(universes[0][:6], []byte{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("address 1: unexpected universes %v", universes)
+	}
+	universes = Pack(nil, frame, RGB, Channels)
+	if len(universes) != 2 || !bytes.Equal(universes[1][:6], []byte{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("address 512: unexpected universes %v", universes)
+	}
+
+	for _, address := range []int{0, -1, Channels + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("address %d: expected a panic", address)
+				}
+			}()
+			Pack(nil, frame, RGB, address)
+		}()
+	}
+}
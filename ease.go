@@ -0,0 +1,116 @@
+package ledsgo
+
+// This file implements common easing functions in fixed point. The input is a
+// fraction from 0 (start) to the maximum value (end) and the output is the
+// eased fraction in the same range: 0xff for the 8-bit variants and 0xffff for
+// the 16-bit variants. The In variants start slowly, the Out variants end
+// slowly and the InOut variants do both.
+
+// EaseInQuad8 is a quadratic ease-in.
+func EaseInQuad8(x uint8) uint8 {
+	return Scale8(x, x)
+}
+
+// EaseOutQuad8 is a quadratic ease-out.
+func EaseOutQuad8(x uint8) uint8 {
+	return 0xff - EaseInQuad8(0xff-x)
+}
+
+// EaseInOutQuad8 is a quadratic ease-in and ease-out.
+func EaseInOutQuad8(x uint8) uint8 {
+	if x < 0x80 {
+		return EaseInQuad8(x*2) / 2
+	}
+	return 0xff - EaseInQuad8((0xff-x)*2)/2
+}
+
+// EaseInCubic8 is a cubic ease-in.
+func EaseInCubic8(x uint8) uint8 {
+	return Scale8(Scale8(x, x), x)
+}
+
+// EaseOutCubic8 is a cubic ease-out.
+func EaseOutCubic8(x uint8) uint8 {
+	return 0xff - EaseInCubic8(0xff-x)
+}
+
+// EaseInOutCubic8 is a cubic ease-in and ease-out.
+func EaseInOutCubic8(x uint8) uint8 {
+	if x < 0x80 {
+		return EaseInCubic8(x*2) / 2
+	}
+	return 0xff - EaseInCubic8((0xff-x)*2)/2
+}
+
+// EaseInSine8 is a sinusoidal ease-in.
+func EaseInSine8(x uint8) uint8 {
+	return uint8(EaseInSine16(uint16(x)*0x101) >> 8)
+}
+
+// EaseOutSine8 is a sinusoidal ease-out.
+func EaseOutSine8(x uint8) uint8 {
+	return uint8(EaseOutSine16(uint16(x)*0x101) >> 8)
+}
+
+// EaseInOutSine8 is a sinusoidal ease-in and ease-out.
+func EaseInOutSine8(x uint8) uint8 {
+	return uint8(EaseInOutSine16(uint16(x)*0x101) >> 8)
+}
+
+// EaseInQuad16 is a quadratic ease-in.
+func EaseInQuad16(x uint16) uint16 {
+	return Scale16(x, x)
+}
+
+// EaseOutQuad16 is a quadratic ease-out.
+func EaseOutQuad16(x uint16) uint16 {
+	return 0xffff - EaseInQuad16(0xffff-x)
+}
+
+// EaseInOutQuad16 is a quadratic ease-in and ease-out.
+func EaseInOutQuad16(x uint16) uint16 {
+	if x < 0x8000 {
+		return EaseInQuad16(x*2) / 2
+	}
+	return 0xffff - EaseInQuad16((0xffff-x)*2)/2
+}
+
+// EaseInCubic16 is a cubic ease-in.
+func EaseInCubic16(x uint16) uint16 {
+	return Scale16(Scale16(x, x), x)
+}
+
+// EaseOutCubic16 is a cubic ease-out.
+func EaseOutCubic16(x uint16) uint16 {
+	return 0xffff - EaseInCubic16(0xffff-x)
+}
+
+// EaseInOutCubic16 is a cubic ease-in and ease-out.
+func EaseInOutCubic16(x uint16) uint16 {
+	if x < 0x8000 {
+		return EaseInCubic16(x*2) / 2
+	}
+	return 0xffff - EaseInCubic16((0xffff-x)*2)/2
+}
+
+// sineFraction converts a sine or cosine value in the range 0..32767 to a
+// 16-bit fraction in the range 0..0xffff.
+func sineFraction(n int16) uint16 {
+	return uint16(n)<<1 + uint16(n)>>14
+}
+
+// EaseInSine16 is a sinusoidal ease-in.
+func EaseInSine16(x uint16) uint16 {
+	return 0xffff - sineFraction(Cos16(x>>2+x>>15)) // quarter circle
+}
+
+// EaseOutSine16 is a sinusoidal ease-out.
+func EaseOutSine16(x uint16) uint16 {
+	return sineFraction(Sin16(x>>2 + x>>15)) // quarter circle
+}
+
+// EaseInOutSine16 is a sinusoidal ease-in and ease-out.
+func EaseInOutSine16(x uint16) uint16 {
+	y := uint32(32767 - int32(Cos16(x>>1+x>>15))) // half circle, 0..65534
+	return uint16(y + y>>15)
+}
@@ -0,0 +1,123 @@
+package ledsgo
+
+import (
+	"testing"
+)
+
+var easings8 = []struct {
+	name string
+	fn   func(uint8) uint8
+}{
+	{"EaseInQuad8", EaseInQuad8},
+	{"EaseOutQuad8", EaseOutQuad8},
+	{"EaseInOutQuad8", EaseInOutQuad8},
+	{"EaseInCubic8", EaseInCubic8},
+	{"EaseOutCubic8", EaseOutCubic8},
+	{"EaseInOutCubic8", EaseInOutCubic8},
+	{"EaseInSine8", EaseInSine8},
+	{"EaseOutSine8", EaseOutSine8},
+	{"EaseInOutSine8", EaseInOutSine8},
+}
+
+var easings16 = []struct {
+	name string
+	fn   func(uint16) uint16
+}{
+	{"EaseInQuad16", EaseInQuad16},
+	{"EaseOutQuad16", EaseOutQuad16},
+	{"EaseInOutQuad16", EaseInOutQuad16},
+	{"EaseInCubic16", EaseInCubic16},
+	{"EaseOutCubic16", EaseOutCubic16},
+	{"EaseInOutCubic16", EaseInOutCubic16},
+	{"EaseInSine16", EaseInSine16},
+	{"EaseOutSine16", EaseOutSine16},
+	{"EaseInOutSine16", EaseInOutSine16},
+}
+
+func TestEasing(t *testing.T) {
+	// All easing functions start at 0, end at the maximum value, and never go
+	// back.
+	for _, easing := range easings8 {
+		if result := easing.fn(0); result != 0 {
+			t.Errorf("%s(0): expected 0, got %d", easing.name, result)
+		}
+		if result := easing.fn(0xff); result != 0xff {
+			t.Errorf("%s(0xff): expected 0xff, got %d", easing.name, result)
+		}
+		for x := 1; x <= 0xff; x++ {
+			if easing.fn(uint8(x)) < easing.fn(uint8(x-1)) {
+				t.Errorf("%s: not monotonic at %d", easing.name, x)
+				break
+			}
+		}
+	}
+	for _, easing := range easings16 {
+		if result := easing.fn(0); result != 0 {
+			t.Errorf("%s(0): expected 0, got %d", easing.name, result)
+		}
+		if result := easing.fn(0xffff); result != 0xffff {
+			t.Errorf("%s(0xffff): expected 0xffff, got %d", easing.name, result)
+		}
+		for x := 1; x <= 0xffff; x++ {
+			if easing.fn(uint16(x)) < easing.fn(uint16(x-1)) {
+				t.Errorf("%s: not monotonic at %d", easing.name, x)
+				break
+			}
+		}
+	}
+}
+
+func TestEasingSymmetry(t *testing.T) {
+	// Out is In mirrored, and InOut is symmetric around the middle. The sine
+	// easings are calculated separately, so they may be off by a bit.
+	for _, tc := range []struct {
+		in, out, inOut func(uint8) uint8
+		name           string
+		tolerance      int
+	}{
+		{EaseInQuad8, EaseOutQuad8, EaseInOutQuad8, "Quad8", 0},
+		{EaseInCubic8, EaseOutCubic8, EaseInOutCubic8, "Cubic8", 0},
+		{EaseInSine8, EaseOutSine8, EaseInOutSine8, "Sine8", 1},
+	} {
+		for x := 0; x <= 0xff; x++ {
+			if diff := abs(int(tc.out(uint8(x))) - (0xff - int(tc.in(uint8(0xff-x))))); diff > tc.tolerance {
+				t.Errorf("EaseOut%s(%d) doesn't mirror EaseIn%s: off by %d", tc.name, x, tc.name, diff)
+			}
+			if diff := abs(int(tc.inOut(uint8(x))) - (0xff - int(tc.inOut(uint8(0xff-x))))); diff > tc.tolerance+1 {
+				t.Errorf("EaseInOut%s(%d) isn't symmetric: off by %d", tc.name, x, diff)
+			}
+		}
+	}
+	for _, tc := range []struct {
+		in, out, inOut func(uint16) uint16
+		name           string
+		tolerance      int
+	}{
+		{EaseInQuad16, EaseOutQuad16, EaseInOutQuad16, "Quad16", 0},
+		{EaseInCubic16, EaseOutCubic16, EaseInOutCubic16, "Cubic16", 0},
+		{EaseInSine16, EaseOutSine16, EaseInOutSine16, "Sine16", 8},
+	} {
+		for x := 0; x <= 0xffff; x++ {
+			if diff := abs(int(tc.out(uint16(x))) - (0xffff - int(tc.in(uint16(0xffff-x))))); diff > tc.tolerance {
+				t.Errorf("EaseOut%s(%d) doesn't mirror EaseIn%s: off by %d", tc.name, x, tc.name, diff)
+				break
+			}
+			if diff := abs(int(tc.inOut(uint16(x))) - (0xffff - int(tc.inOut(uint16(0xffff-x))))); diff > tc.tolerance+1 {
+				t.Errorf("EaseInOut%s(%d) isn't symmetric: off by %d", tc.name, x, diff)
+				break
+			}
+		}
+	}
+
+	// Halfway through, the InOut easings are halfway.
+	for _, fn := range []func(uint8) uint8{EaseInOutQuad8, EaseInOutCubic8, EaseInOutSine8} {
+		if result := fn(0x80); abs(int(result)-0x80) > 1 {
+			t.Errorf("EaseInOut8(0x80): expected 0x80, got %#x", result)
+		}
+	}
+	for _, fn := range []func(uint16) uint16{EaseInOutQuad16, EaseInOutCubic16, EaseInOutSine16} {
+		if result := fn(0x8000); abs(int(result)-0x8000) > 4 {
+			t.Errorf("EaseInOut16(0x8000): expected 0x8000, got %#x", result)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// Effect is an animation that can be drawn on a strip of LEDs.
+type Effect interface {
+	// Render draws the effect on the frame at the given animation time.
+	// Effects may rely on the frame still containing the previously rendered
+	// frame, for example to fade it out.
+	Render(frame Strip, t time.Duration)
+}
+
+// EffectFunc is an adapter that allows the use of an ordinary function as an
+// Effect.
+type EffectFunc func(frame Strip, t time.Duration)
+
+// Render calls f(frame, t).
+func (f EffectFunc) Render(frame Strip, t time.Duration) {
+	f(frame, t)
+}
+
+// StandardParams are the parameters that most effects have in common, like
+// the speed, intensity and palette controls of WLED. Once they have been set,
+// the Runner keeps them when switching effects, so that every effect runs with
+// the speed and colors the user chose. Until then, effects keep the settings
+// they were configured with.
+type StandardParams struct {
+	// Speed is the speed of the effect, where 128 is the normal speed of the
+	// effect.
+	Speed uint8
+
+	// Intensity is an effect-specific amount, such as the number of sparks
+	// or the length of a tail, where 128 is the normal amount.
+	Intensity uint8
+
+	// Palette is the palette the effect draws its colors from. If it is nil,
+	// the effect uses its own default colors.
+	Palette *Palette16
+}
+
+// DefaultStandardParams returns the standard parameters of a new Runner: the
+// normal speed and intensity and the default palette of every effect.
+func DefaultStandardParams() StandardParams {
+	return StandardParams{
+		Speed:     128,
+		Intensity: 128,
+	}
+}
+
+// StandardEffect is an effect that uses the standard parameters.
+type StandardEffect interface {
+	Effect
+
+	// SetStandardParams changes the standard parameters of the effect. It is
+	// called when the parameters of a Runner change, and when the effect is
+	// started by a Runner whose parameters have been set, but never at the same time as Render. A nil palette
+	// means that the effect should use its default colors.
+	SetStandardParams(params StandardParams)
+}
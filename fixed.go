@@ -0,0 +1,137 @@
+package ledsgo
+
+import (
+	"strconv"
+)
+
+// This file defines small fixed-point types, so that positions and speeds can
+// be manipulated fractionally without re-deriving the bit shifts every time.
+// All of them are plain integers, so addition, subtraction and comparison work
+// as usual. Multiplication and division need the methods below.
+
+// Accum88 is an unsigned 8.8 fixed-point value (0 to 255.996), like the accum88
+// type in FastLED. It is also used for BPM values, see Beat88.
+type Accum88 uint16
+
+// Q88 is a signed 8.8 fixed-point value (-128 to 127.996).
+type Q88 int16
+
+// Q1212 is a signed fixed-point value with 12 fractional bits, stored in an
+// int32. This is the format used for the inputs to the noise functions: a
+// number of LEDs or pixels converted to Q1212 can be passed directly to Noise1,
+// Noise2 and Noise3.
+type Q1212 int32
+
+// Accum88FromInt returns n as an Accum88.
+func Accum88FromInt(n uint8) Accum88 {
+	return Accum88(n) << 8
+}
+
+// Int returns the integer part, rounded down.
+func (a Accum88) Int() uint8 {
+	return uint8(a >> 8)
+}
+
+// Frac returns the fractional part, as a fraction of 256.
+func (a Accum88) Frac() uint8 {
+	return uint8(a)
+}
+
+// Mul returns a*b, rounded down. The result wraps around on overflow.
+func (a Accum88) Mul(b Accum88) Accum88 {
+	return Accum88(uint32(a) * uint32(b) >> 8)
+}
+
+// Div returns a/b, rounded down. It panics when b is zero.
+func (a Accum88) Div(b Accum88) Accum88 {
+	return Accum88(uint32(a) << 8 / uint32(b))
+}
+
+// String returns a decimal representation of the value, for debugging.
+func (a Accum88) String() string {
+	return formatFixed(int64(a), 8)
+}
+
+// Q88FromInt returns n as a Q88.
+func Q88FromInt(n int8) Q88 {
+	return Q88(n) << 8
+}
+
+// Int returns the integer part, rounded down (towards negative infinity).
+func (q Q88) Int() int8 {
+	return int8(q >> 8)
+}
+
+// Frac returns the fractional part, as a fraction of 256. It is always
+// positive: Int() + Frac()/256 is the original value.
+func (q Q88) Frac() uint8 {
+	return uint8(q)
+}
+
+// Mul returns q*b, rounded down. The result wraps around on overflow.
+func (q Q88) Mul(b Q88) Q88 {
+	return Q88(int32(q) * int32(b) >> 8)
+}
+
+// Div returns q/b, rounded towards zero. It panics when b is zero.
+func (q Q88) Div(b Q88) Q88 {
+	return Q88(int32(q) << 8 / int32(b))
+}
+
+// String returns a decimal representation of the value, for debugging.
+func (q Q88) String() string {
+	return formatFixed(int64(q), 8)
+}
+
+// Q1212FromInt returns n as a Q1212.
+func Q1212FromInt(n int32) Q1212 {
+	return Q1212(n << 12)
+}
+
+// Int returns the integer part, rounded down (towards negative infinity).
+func (q Q1212) Int() int32 {
+	return int32(q >> 12)
+}
+
+// Frac returns the fractional part, as a fraction of 4096. It is always
+// positive: Int() + Frac()/4096 is the original value.
+func (q Q1212) Frac() uint16 {
+	return uint16(q & 0xfff)
+}
+
+// Mul returns q*b, rounded down. The result wraps around on overflow.
+func (q Q1212) Mul(b Q1212) Q1212 {
+	return Q1212(int64(q) * int64(b) >> 12)
+}
+
+// Div returns q/b, rounded towards zero. It panics when b is zero.
+func (q Q1212) Div(b Q1212) Q1212 {
+	return Q1212(int64(q) << 12 / int64(b))
+}
+
+// String returns a decimal representation of the value, for debugging.
+func (q Q1212) String() string {
+	return formatFixed(int64(q), 12)
+}
+
+// formatFixed formats a fixed-point number with the given number of fractional
+// bits as a decimal number, without using floating point.
+func formatFixed(n int64, bits uint) string {
+	var buf []byte
+	if n < 0 {
+		buf = append(buf, '-')
+		n = -n
+	}
+	buf = strconv.AppendInt(buf, n>>bits, 10)
+	frac := n & (1<<bits - 1)
+	if frac == 0 {
+		return string(buf)
+	}
+	buf = append(buf, '.')
+	for frac != 0 {
+		frac *= 10
+		buf = append(buf, byte('0'+frac>>bits))
+		frac &= 1<<bits - 1
+	}
+	return string(buf)
+}
@@ -0,0 +1,189 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// Pixel is the set of color types that can be used in a Frame: 8-bit RGB
+// (color.RGBA, as used by Strip), 16-bit RGB (Color48) and RGBW (ColorRGBW).
+type Pixel interface {
+	color.RGBA | Color48 | ColorRGBW
+}
+
+// Frame is a frame of LEDs with a color type other than (or including) 8-bit
+// RGB, so that high bit depth and RGBW pipelines can use the same operations as
+// Strip instead of their own set of functions. A Strip can be converted to a
+// Frame[color.RGBA] and back without copying.
+//
+// The operations check the color type once per call, not once per LED, so
+// they are about as fast as a version written for one color type.
+type Frame[C Pixel] []C
+
+// Fill sets all LEDs to the given color.
+func (f Frame[C]) Fill(c C) {
+	for i := range f {
+		f[i] = c
+	}
+}
+
+// Scale scales all channels of all LEDs by scale/256, like Scale8. For
+// color.RGBA, the alpha channel is left unchanged.
+func (f Frame[C]) Scale(scale uint8) {
+	switch f := any(f).(type) {
+	case Frame[color.RGBA]:
+		scaleStrip(Strip(f), Strip(f), scale)
+	case Frame[Color48]:
+		k := uint32(scale) + 1
+		for i, c := range f {
+			f[i] = Color48{uint16(uint32(c.R) * k >> 8), uint16(uint32(c.G) * k >> 8), uint16(uint32(c.B) * k >> 8)}
+		}
+	case Frame[ColorRGBW]:
+		for i, c := range f {
+			f[i] = ColorRGBW{Scale8(c.R, scale), Scale8(c.G, scale), Scale8(c.B, scale), Scale8(c.W, scale)}
+		}
+	}
+}
+
+// Blend mixes the colors of other into the frame, like Blend: a frac of 0
+// keeps the frame as it is, 255 replaces it with (almost) other. Only the
+// LEDs that exist in both frames are changed.
+func (f Frame[C]) Blend(other Frame[C], frac uint8) {
+	n := min(len(f), len(other))
+	switch f := any(f).(type) {
+	case Frame[color.RGBA]:
+		other := any(other).(Frame[color.RGBA])
+		for i := 0; i < n; i++ {
+			f[i] = Blend(f[i], other[i], frac)
+		}
+	case Frame[Color48]:
+		other := any(other).(Frame[Color48])
+		frac16 := uint16(frac) * 0x101
+		for i := 0; i < n; i++ {
+			a, b := f[i], other[i]
+			f[i] = Color48{Lerp16by16(a.R, b.R, frac16), Lerp16by16(a.G, b.G, frac16), Lerp16by16(a.B, b.B, frac16)}
+		}
+	case Frame[ColorRGBW]:
+		other := any(other).(Frame[ColorRGBW])
+		for i := 0; i < n; i++ {
+			a, b := f[i], other[i]
+			f[i] = ColorRGBW{Lerp8by8(a.R, b.R, frac), Lerp8by8(a.G, b.G, frac), Lerp8by8(a.B, b.B, frac), Lerp8by8(a.W, b.W, frac)}
+		}
+	}
+}
+
+// Add adds the colors of other to the frame, saturating at the maximum value
+// of each channel. Only the LEDs that exist in both frames are changed.
+func (f Frame[C]) Add(other Frame[C]) {
+	n := min(len(f), len(other))
+	switch f := any(f).(type) {
+	case Frame[color.RGBA]:
+		other := any(other).(Frame[color.RGBA])
+		for i := 0; i < n; i++ {
+			a, b := f[i], other[i]
+			f[i] = color.RGBA{QAdd8(a.R, b.R), QAdd8(a.G, b.G), QAdd8(a.B, b.B), QAdd8(a.A, b.A)}
+		}
+	case Frame[Color48]:
+		other := any(other).(Frame[Color48])
+		add := func(a, b uint16) uint16 {
+			return uint16(min(uint32(a)+uint32(b), 0xffff))
+		}
+		for i := 0; i < n; i++ {
+			a, b := f[i], other[i]
+			f[i] = Color48{add(a.R, b.R), add(a.G, b.G), add(a.B, b.B)}
+		}
+	case Frame[ColorRGBW]:
+		other := any(other).(Frame[ColorRGBW])
+		for i := 0; i < n; i++ {
+			a, b := f[i], other[i]
+			f[i] = ColorRGBW{QAdd8(a.R, b.R), QAdd8(a.G, b.G), QAdd8(a.B, b.B), QAdd8(a.W, b.W)}
+		}
+	}
+}
+
+// ConvertFrame converts the colors in src to the color type of dst, for as
+// many LEDs as exist in both. Conversions to 8-bit colors are rounded, and
+// conversions between RGB and RGBW work like RGBWFromRGBA and
+// ColorRGBW.RGBA8.
+func ConvertFrame[To, From Pixel](dst []To, src []From) {
+	n := min(len(dst), len(src))
+	switch dst := any(dst).(type) {
+	case []color.RGBA:
+		switch src := any(src).(type) {
+		case []color.RGBA:
+			copy(dst, src)
+		case []Color48:
+			for i := 0; i < n; i++ {
+				dst[i] = src[i].RGBA8()
+			}
+		case []ColorRGBW:
+			for i := 0; i < n; i++ {
+				dst[i] = src[i].RGBA8()
+			}
+		}
+	case []Color48:
+		switch src := any(src).(type) {
+		case []color.RGBA:
+			for i := 0; i < n; i++ {
+				dst[i] = Color48FromRGBA(src[i])
+			}
+		case []Color48:
+			copy(dst, src)
+		case []ColorRGBW:
+			for i := 0; i < n; i++ {
+				dst[i] = Color48FromRGBA(src[i].RGBA8())
+			}
+		}
+	case []ColorRGBW:
+		switch src := any(src).(type) {
+		case []color.RGBA:
+			for i := 0; i < n; i++ {
+				dst[i] = RGBWFromRGBA(src[i])
+			}
+		case []Color48:
+			for i := 0; i < n; i++ {
+				dst[i] = RGBWFromRGBA(src[i].RGBA8())
+			}
+		case []ColorRGBW:
+			copy(dst, src)
+		}
+	}
+}
+
+// FrameDisplayer is like Displayer, for frames of a specific color type.
+// Displayers that can send more than 8-bit RGB to the LEDs implement it in
+// addition to Displayer, such as apa102.Displayer for Color48 and
+// WriterDisplayer for ColorRGBW.
+type FrameDisplayer[C Pixel] interface {
+	DisplayFrame(frame []C) error
+}
+
+// NewFrameDisplayer returns a FrameDisplayer for the given displayer. If the
+// displayer implements FrameDisplayer[C] itself it is returned as-is, otherwise
+// frames are converted to 8-bit RGB (see ConvertFrame) before they are sent to
+// the displayer. This way, any Displayer can be used at the end of a Color48 or
+// ColorRGBW pipeline.
+func NewFrameDisplayer[C Pixel](displayer Displayer) FrameDisplayer[C] {
+	if d, ok := displayer.(FrameDisplayer[C]); ok {
+		return d
+	}
+	return &convertingDisplayer[C]{displayer: displayer}
+}
+
+// convertingDisplayer is a FrameDisplayer returned by NewFrameDisplayer for
+// displayers that only support 8-bit RGB.
+type convertingDisplayer[C Pixel] struct {
+	displayer Displayer
+	buf       Strip
+}
+
+// DisplayFrame implements FrameDisplayer.
+func (d *convertingDisplayer[C]) DisplayFrame(frame []C) error {
+	if frame, ok := any(frame).([]color.RGBA); ok {
+		return d.displayer.Display(frame) // no conversion needed
+	}
+	if len(d.buf) != len(frame) {
+		d.buf = make(Strip, len(frame))
+	}
+	ConvertFrame(d.buf, frame)
+	return d.displayer.Display(d.buf)
+}
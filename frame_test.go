@@ -0,0 +1,133 @@
+package ledsgo
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+var _ FrameDisplayer[ColorRGBW] = (*WriterDisplayer)(nil)
+
+func TestFrameOps(t *testing.T) {
+	// Strip operations.
+	strip := Frame[color.RGBA]{{200, 100, 0, 0}, {10, 20, 30, 0}}
+	strip.Scale(127)
+	if strip[0] != (color.RGBA{100, 50, 0, 0}) || strip[1] != (color.RGBA{5, 10, 15, 0}) {
+		t.Errorf("Frame[color.RGBA].Scale: got %v", strip)
+	}
+	strip.Add(Frame[color.RGBA]{{200, 0, 0, 0}})
+	if strip[0] != (color.RGBA{255, 50, 0, 0}) || strip[1] != (color.RGBA{5, 10, 15, 0}) {
+		t.Errorf("Frame[color.RGBA].Add: got %v", strip)
+	}
+
+	// 16-bit colors.
+	f48 := make(Frame[Color48], 3)
+	f48.Fill(Color48{0x8000, 0xffff, 0x0100})
+	f48.Scale(127)
+	if f48[2] != (Color48{0x4000, 0x7fff, 0x0080}) {
+		t.Errorf("Frame[Color48].Scale: got %v", f48[2])
+	}
+	f48.Blend(Frame[Color48]{{0xc000, 0x7fff, 0x0080}}, 128)
+	if f48[0] != (Color48{0x8040, 0x7fff, 0x0080}) || f48[1] != (Color48{0x4000, 0x7fff, 0x0080}) {
+		t.Errorf("Frame[Color48].Blend: got %v", f48)
+	}
+	f48.Add(Frame[Color48]{{0x8000, 0x8000, 0x8000}})
+	if f48[0] != (Color48{0xffff, 0xffff, 0x8080}) {
+		t.Errorf("Frame[Color48].Add: got %v", f48[0])
+	}
+
+	// RGBW colors.
+	fw := Frame[ColorRGBW]{{0, 0, 0, 200}}
+	fw.Blend(Frame[ColorRGBW]{{100, 0, 0, 0}}, 128)
+	if fw[0] != (ColorRGBW{50, 0, 0, 100}) {
+		t.Errorf("Frame[ColorRGBW].Blend: got %v", fw[0])
+	}
+	fw.Scale(63)
+	if fw[0] != (ColorRGBW{12, 0, 0, 25}) {
+		t.Errorf("Frame[ColorRGBW].Scale: got %v", fw[0])
+	}
+}
+
+func TestConvertFrame(t *testing.T) {
+	src := Strip{{255, 128, 64, 0}, {0, 0, 0, 0}, {10, 20, 30, 0}}
+
+	f48 := make([]Color48, len(src))
+	ConvertFrame(f48, src)
+	if f48[0] != (Color48{0xffff, 0x8080, 0x4040}) {
+		t.Errorf("to Color48: got %v", f48[0])
+	}
+	fw := make([]ColorRGBW, len(src))
+	ConvertFrame(fw, f48)
+	if fw[0] != (ColorRGBW{191, 64, 0, 64}) || fw[2] != (ColorRGBW{0, 10, 20, 10}) {
+		t.Errorf("to ColorRGBW: got %v", fw)
+	}
+
+	// Round trip back to 8-bit RGB.
+	dst := make(Strip, len(src))
+	ConvertFrame(dst, fw)
+	for i := range src {
+		if dst[i] != src[i] {
+			t.Errorf("round trip %d: expected %v, got %v", i, src[i], dst[i])
+		}
+	}
+
+	// Only the common part is converted.
+	short := make([]Color48, 1)
+	ConvertFrame(short, src)
+	ConvertFrame(dst, short)
+	if dst[0] != src[0] || dst[1] != src[1] {
+		t.Errorf("partial conversion: got %v", dst)
+	}
+}
+
+func TestFrameDisplayer(t *testing.T) {
+	// Displayers without native support get 8-bit RGB frames.
+	r := &frameRecorder{}
+	d := NewFrameDisplayer[ColorRGBW](r)
+	if err := d.DisplayFrame([]ColorRGBW{{10, 0, 0, 250}, {1, 2, 3, 4}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.frames) != 1 || r.frames[0][0] != (color.RGBA{255, 250, 250, 0}) || r.frames[0][1] != (color.RGBA{5, 6, 7, 0}) {
+		t.Errorf("converted frame: got %v", r.frames)
+	}
+
+	// WriterDisplayer supports RGBW natively.
+	buf := &bytes.Buffer{}
+	w := NewWriterDisplayer(buf, OrderGRBW)
+	if NewFrameDisplayer[ColorRGBW](w) != FrameDisplayer[ColorRGBW](w) {
+		t.Errorf("expected WriterDisplayer to be used as-is")
+	}
+	if err := w.DisplayFrame([]ColorRGBW{{1, 2, 3, 4}}); err != nil {
+		t.Fatal(err)
+	}
+	w.Order = OrderRGB
+	if err := w.DisplayFrame([]ColorRGBW{{1, 2, 3, 4}}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{2, 1, 3, 4, 5, 6, 7}; !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("WriterDisplayer.DisplayFrame: expected %v, got %v", expected, buf.Bytes())
+	}
+}
+
+func TestFrameAllocs(t *testing.T) {
+	f := make(Frame[Color48], 64)
+	other := make(Frame[Color48], 64)
+	strip := make(Strip, 64)
+	d := NewFrameDisplayer[Color48](&discardDisplayer{})
+	allocs := testing.AllocsPerRun(10, func() {
+		f.Scale(200)
+		f.Blend(other, 100)
+		f.Add(other)
+		ConvertFrame(strip, f)
+		d.DisplayFrame(f)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+type discardDisplayer struct{}
+
+func (discardDisplayer) Display(frame Strip) error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// Number of frames over which frame statistics are calculated.
+const frameStatsWindow = 64
+
+// FrameLimiter paces an animation loop to a target frame rate and keeps track
+// of how well that target is met. Call Wait once at the end of every frame,
+// after rendering and displaying it.
+type FrameLimiter struct {
+	interval   time.Duration
+	next       time.Time
+	frameStart time.Time
+	frames     uint64
+	dropped    uint64
+	index      int                             // position in the window of the next frame
+	count      int                             // number of frames in the window, at most frameStatsWindow
+	render     [frameStatsWindow]time.Duration // render times of the last frames
+	ends       [frameStatsWindow]time.Time     // end times of the last frames
+}
+
+// FrameStats contains statistics about the frames rendered by a FrameLimiter.
+// Percentiles are calculated over the last 64 frames.
+type FrameStats struct {
+	Frames    uint64        // total number of frames
+	Dropped   uint64        // number of frames that took longer than the frame interval
+	FPS       int           // achieved frames per second over the last frames
+	RenderP50 time.Duration // median time between the start of a frame and the call to Wait
+	RenderP90 time.Duration // 90th percentile render time
+	RenderP99 time.Duration // 99th percentile render time
+	RenderMax time.Duration // maximum render time
+}
+
+// NewFrameLimiter returns a new frame limiter with the given target frame rate
+// in frames per second. The first frame starts now. A frame rate of zero (or
+// less) means there is no limit: Wait only keeps the statistics, and no frames
+// are counted as dropped.
+func NewFrameLimiter(fps int) *FrameLimiter {
+	now := time.Now()
+	var interval time.Duration
+	if fps > 0 {
+		interval = time.Second / time.Duration(fps)
+	}
+	return &FrameLimiter{
+		interval:   interval,
+		next:       now.Add(interval),
+		frameStart: now,
+	}
+}
+
+// Wait sleeps until it is time to start the next frame. If the current frame
+// took longer than the frame interval, it is counted as a dropped frame and
+// Wait returns immediately without trying to catch up.
+func (l *FrameLimiter) Wait() {
+	now := time.Now()
+	i := l.index
+	l.render[i] = now.Sub(l.frameStart)
+	l.frames++
+	l.index = (l.index + 1) % frameStatsWindow
+	l.count = min(l.count+1, frameStatsWindow)
+	switch {
+	case l.interval == 0:
+		// No limit, so there is nothing to wait for.
+	case now.After(l.next):
+		l.dropped++
+		l.next = now.Add(l.interval)
+	default:
+		time.Sleep(l.next.Sub(now))
+		l.next = l.next.Add(l.interval)
+	}
+	l.frameStart = time.Now()
+	l.ends[i] = l.frameStart
+}
+
+// Stats returns statistics about the frames so far. It does not allocate
+// memory, so it is safe to call regularly on small microcontrollers.
+func (l *FrameLimiter) Stats() FrameStats {
+	stats := FrameStats{
+		Frames:  l.frames,
+		Dropped: l.dropped,
+	}
+	n := l.count
+	if n == 0 {
+		return stats
+	}
+
+	// Achieved frame rate, from the oldest to the newest frame in the window.
+	newest := l.ends[(l.index+frameStatsWindow-1)%frameStatsWindow]
+	oldest := l.ends[(l.index+frameStatsWindow-n)%frameStatsWindow]
+	if span := newest.Sub(oldest); n > 1 && span > 0 {
+		stats.FPS = int((time.Duration(n-1)*time.Second + span/2) / span)
+	}
+
+	// Sort the render times (insertion sort, to avoid allocating).
+	var sorted [frameStatsWindow]time.Duration
+	copy(sorted[:], l.render[:n])
+	for i := 1; i < n; i++ {
+		for j := i; j > 0 && sorted[j] < sorted[j-1]; j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+	stats.RenderP50 = sorted[(n-1)*50/100]
+	stats.RenderP90 = sorted[(n-1)*90/100]
+	stats.RenderP99 = sorted[(n-1)*99/100]
+	stats.RenderMax = sorted[n-1]
+	return stats
+}
@@ -0,0 +1,54 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrameLimiter(t *testing.T) {
+	l := NewFrameLimiter(100)
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		l.Wait()
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected 10 frames at 100fps to take at least 90ms, took %v", elapsed)
+	}
+	stats := l.Stats()
+	if stats.Frames != 10 || stats.FPS < 50 || stats.FPS > 110 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// A slow frame is counted as dropped.
+	time.Sleep(20 * time.Millisecond)
+	l.Wait()
+	stats = l.Stats()
+	if stats.Dropped == 0 || stats.RenderMax < 20*time.Millisecond {
+		t.Errorf("expected a dropped frame of at least 20ms, got %+v", stats)
+	}
+	if stats.RenderP50 > stats.RenderP90 || stats.RenderP90 > stats.RenderP99 || stats.RenderP99 > stats.RenderMax {
+		t.Errorf("expected increasing percentiles, got %+v", stats)
+	}
+}
+
+func TestFrameLimiterNoLimit(t *testing.T) {
+	for _, fps := range []int{0, -1} {
+		l := NewFrameLimiter(fps)
+		start := time.Now()
+		for i := 0; i < 1000; i++ {
+			l.Wait()
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("fps %d: expected no limit, 1000 frames took %v", fps, elapsed)
+		}
+		if stats := l.Stats(); stats.Frames != 1000 || stats.Dropped != 0 {
+			t.Errorf("fps %d: unexpected stats %+v", fps, stats)
+		}
+		if l.index >= frameStatsWindow || l.count != frameStatsWindow {
+			t.Errorf("fps %d: expected the window to wrap around, got index %d and count %d", fps, l.index, l.count)
+		}
+	}
+	if stats := NewFrameLimiter(0).Stats(); stats != (FrameStats{}) {
+		t.Errorf("expected empty stats before the first frame, got %+v", stats)
+	}
+}
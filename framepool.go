@@ -0,0 +1,52 @@
+package ledsgo
+
+import (
+	"image/color"
+	"sync"
+)
+
+// FramePool keeps a list of unused frames of the same size, so that programs
+// that regularly need an extra frame (for example to hand a frame to a sender
+// in a different goroutine, or to render two effects for a transition) don't
+// allocate a new frame every time. Garbage collection pauses can cause visible
+// stutter at high frame rates.
+//
+// A FramePool is safe for concurrent use.
+type FramePool struct {
+	lock    sync.Mutex
+	numLEDs int
+	free    []Strip
+}
+
+// NewFramePool returns a new pool of frames with the given number of LEDs.
+func NewFramePool(numLEDs int) *FramePool {
+	return &FramePool{numLEDs: numLEDs}
+}
+
+// Get returns a black frame from the pool, or a new frame if the pool is
+// empty.
+func (p *FramePool) Get() Strip {
+	p.lock.Lock()
+	n := len(p.free)
+	if n == 0 {
+		p.lock.Unlock()
+		return make(Strip, p.numLEDs)
+	}
+	frame := p.free[n-1]
+	p.free[n-1] = nil
+	p.free = p.free[:n-1]
+	p.lock.Unlock()
+	frame.FillSolid(color.RGBA{})
+	return frame
+}
+
+// Put returns a frame to the pool. The frame must not be used anymore after
+// calling Put. Frames of a different size are ignored.
+func (p *FramePool) Put(frame Strip) {
+	if len(frame) != p.numLEDs {
+		return
+	}
+	p.lock.Lock()
+	p.free = append(p.free, frame)
+	p.lock.Unlock()
+}
@@ -0,0 +1,47 @@
+package ledsgo
+
+import (
+	"image/color"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestFramePool(t *testing.T) {
+	p := NewFramePool(10)
+	a := p.Get()
+	if len(a) != 10 {
+		t.Fatalf("expected a frame of 10 LEDs, got %d", len(a))
+	}
+	a[3] = color.RGBA{R: 255}
+	p.Put(a)
+	p.Put(make(Strip, 5)) // ignored
+	b := p.Get()
+	if &b[0] != &a[0] {
+		t.Error("expected the frame to be reused")
+	}
+	if !isBlack(b) {
+		t.Errorf("expected a black frame, got %v", b)
+	}
+	if n := testing.AllocsPerRun(10, func() { p.Put(p.Get()) }); n != 0 {
+		t.Errorf("expected no allocations, got %v", n)
+	}
+}
+
+// TestRenderAllocs checks that the rendering path doesn't allocate once it
+// runs, to avoid garbage collection pauses.
+func TestRenderAllocs(t *testing.T) {
+	var displayer Displayer = NewWriterDisplayer(io.Discard, OrderGRB)
+	displayer = NewPowerLimiter(displayer, WS2812Power, 500)
+	displayer = NewBlackout(displayer)
+	r := NewRunner(displayer, 300)
+	r.SetBrightness(200)
+	r.SetEffect(EffectFunc(func(frame Strip, t time.Duration) {
+		frame.FadeAll(20)
+		frame.FillPalette(&LavaColors, uint8(t/time.Millisecond), 3)
+	}))
+	r.Frame() // allocate the buffers
+	if n := testing.AllocsPerRun(10, func() { r.Frame() }); n != 0 {
+		t.Errorf("expected no allocations per frame, got %v", n)
+	}
+}
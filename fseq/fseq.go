@@ -0,0 +1,251 @@
+// Package fseq reads FSEQ files, the sequence format used by xLights and
+// Falcon Player, so that light shows sequenced in xLights can be played
+// directly by a ledsgo based controller.
+//
+// Version 1 files and version 2 files that are uncompressed or compressed with
+// zlib are supported. Zstd compression (the default in xLights) is not
+// supported, because there is no zstd decoder in the standard library: export
+// the sequence without compression or with zlib compression instead.
+package fseq
+
+import (
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// Compression types of version 2 files.
+const (
+	CompressionNone = 0
+	CompressionZstd = 1
+	CompressionZlib = 2
+)
+
+var (
+	errInvalid     = errors.New("fseq: invalid file")
+	errUnsupported = errors.New("fseq: unsupported compression")
+	errFrame       = errors.New("fseq: frame out of range")
+)
+
+// maxChannels is the maximum number of channels of a file. It is the largest
+// channel number that can be stored in a sparse range, and more than enough
+// for any real installation.
+const maxChannels = 1 << 24
+
+// block is a compressed block of frames in a version 2 file.
+type block struct {
+	frame  int   // first frame in this block
+	offset int64 // offset in the file
+	size   int64 // compressed size
+}
+
+// span is a sparse channel range in a version 2 file.
+type span struct {
+	start int
+	count int
+}
+
+// File is an opened FSEQ file.
+type File struct {
+	// Channels is the number of channels of every frame, as returned by
+	// ReadFrame.
+	Channels int
+
+	// Frames is the number of frames in the sequence.
+	Frames int
+
+	// Step is the time between two frames.
+	Step time.Duration
+
+	// Major and minor version of the file format.
+	Major, Minor uint8
+
+	// Compression is the compression type of the file.
+	Compression uint8
+
+	r          io.ReaderAt
+	dataOffset int64
+	frameSize  int // number of channels stored per frame
+	blocks     []block
+	spans      []span
+	stored     []byte // frame as stored in the file (sparse channels only)
+	zr         io.ReadCloser
+	zblock     int // index of the block that zr reads from
+	zframe     int // next frame that zr will return
+}
+
+// Open reads the header of an FSEQ file.
+func Open(r io.ReaderAt) (*File, error) {
+	var header [32]byte
+	if _, err := r.ReadAt(header[:28], 0); err != nil {
+		return nil, err
+	}
+	if magic := string(header[:4]); magic != "PSEQ" && magic != "FSEQ" {
+		return nil, errInvalid
+	}
+	channels := binary.LittleEndian.Uint32(header[10:])
+	frames := binary.LittleEndian.Uint32(header[14:])
+	if channels == 0 || channels > maxChannels || frames > math.MaxInt32 {
+		return nil, errInvalid
+	}
+	f := &File{
+		r:          r,
+		dataOffset: int64(binary.LittleEndian.Uint16(header[4:])),
+		Minor:      header[6],
+		Major:      header[7],
+		Channels:   int(channels),
+		Frames:     int(frames),
+		Step:       time.Duration(header[18]) * time.Millisecond,
+	}
+	dataEnd := f.dataOffset + int64(f.Frames)*int64(f.Channels)
+	f.frameSize = f.Channels
+	switch f.Major {
+	case 1:
+		// No compression or sparse ranges.
+	case 2:
+		if _, err := r.ReadAt(header[28:32], 28); err != nil {
+			return nil, err
+		}
+		f.Compression = header[20] & 0x0f
+		numBlocks := int(header[20]&0xf0)<<4 | int(header[21])
+		numSpans := int(header[22])
+		if f.Compression != CompressionNone && f.Compression != CompressionZlib {
+			return nil, errUnsupported
+		}
+
+		// Read the compression block index and the sparse ranges.
+		index := make([]byte, numBlocks*8+numSpans*6)
+		if _, err := r.ReadAt(index, 32); err != nil {
+			return nil, err
+		}
+		offset := f.dataOffset
+		for i := 0; i < numBlocks; i++ {
+			b := block{
+				frame:  int(binary.LittleEndian.Uint32(index[i*8:])),
+				offset: offset,
+				size:   int64(binary.LittleEndian.Uint32(index[i*8+4:])),
+			}
+			offset += b.size
+			if b.size != 0 {
+				f.blocks = append(f.blocks, b)
+			}
+		}
+		if numSpans != 0 {
+			f.frameSize = 0
+			for i := 0; i < numSpans; i++ {
+				buf := index[numBlocks*8+i*6:]
+				s := span{
+					start: int(buf[0]) | int(buf[1])<<8 | int(buf[2])<<16,
+					count: int(buf[3]) | int(buf[4])<<8 | int(buf[5])<<16,
+				}
+				if s.start+s.count > f.Channels {
+					return nil, errInvalid
+				}
+				f.spans = append(f.spans, s)
+				f.frameSize += s.count
+			}
+			if f.frameSize > f.Channels {
+				return nil, errInvalid // overlapping ranges
+			}
+		}
+		if f.Compression != CompressionNone && len(f.blocks) == 0 {
+			return nil, errInvalid
+		}
+		dataEnd = f.dataOffset + int64(f.Frames)*int64(f.frameSize)
+		if f.Compression != CompressionNone {
+			dataEnd = offset
+		}
+	default:
+		return nil, errInvalid
+	}
+
+	// Check that the file is as long as the header says, before allocating
+	// the frame buffer.
+	if dataEnd > f.dataOffset {
+		var last [1]byte
+		if _, err := r.ReadAt(last[:], dataEnd-1); err != nil {
+			if err == io.EOF {
+				err = errInvalid
+			}
+			return nil, err
+		}
+	}
+	f.stored = make([]byte, f.frameSize)
+	return f, nil
+}
+
+// ReadFrame reads the channel data of the given frame into dst, which must be
+// at least Channels bytes long. Channels that aren't stored in a sparse file
+// are set to zero. Reading frames in order is efficient, even for compressed
+// files.
+func (f *File) ReadFrame(n int, dst []byte) error {
+	if n < 0 || n >= f.Frames {
+		return errFrame
+	}
+	if err := f.readStored(n); err != nil {
+		return err
+	}
+	dst = dst[:f.Channels]
+	if f.spans == nil {
+		copy(dst, f.stored)
+		return nil
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	stored := f.stored
+	for _, s := range f.spans {
+		copy(dst[s.start:s.start+s.count], stored)
+		stored = stored[s.count:]
+	}
+	return nil
+}
+
+// readStored reads the frame as stored in the file into f.stored.
+func (f *File) readStored(n int) error {
+	if f.Compression == CompressionNone {
+		_, err := f.r.ReadAt(f.stored, f.dataOffset+int64(n)*int64(f.frameSize))
+		return err
+	}
+
+	// Find the block that contains this frame.
+	bi := len(f.blocks) - 1
+	for i, b := range f.blocks {
+		if b.frame > n {
+			bi = i - 1
+			break
+		}
+	}
+	if bi < 0 {
+		return errInvalid
+	}
+	if f.zr == nil || f.zblock != bi || f.zframe > n {
+		// Start decompressing the block from the start.
+		if f.zr != nil {
+			f.zr.Close()
+			f.zr = nil
+		}
+		b := f.blocks[bi]
+		zr, err := zlib.NewReader(io.NewSectionReader(f.r, b.offset, b.size))
+		if err != nil {
+			return err
+		}
+		f.zr = zr
+		f.zblock = bi
+		f.zframe = b.frame
+	}
+	for ; f.zframe <= n; f.zframe++ {
+		if _, err := io.ReadFull(f.zr, f.stored); err != nil {
+			f.zr.Close()
+			f.zr = nil
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package fseq
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+	"github.com/aykevl/ledsgo/internal/ledstest"
+)
+
+// testFrame returns the stored channel data of a test frame.
+func testFrame(n, size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(n*16 + i)
+	}
+	return data
+}
+
+// buildV2 builds a version 2 file. Frames are split into blocks of
+// framesPerBlock frames when compressed.
+func buildV2(channels, frames int, compression uint8, framesPerBlock int, spans []span) []byte {
+	size := channels
+	if spans != nil {
+		size = 0
+		for _, s := range spans {
+			size += s.count
+		}
+	}
+	var blocks [][]byte
+	var blockFrames []int
+	var raw []byte
+	for n := 0; n < frames; n++ {
+		raw = append(raw, testFrame(n, size)...)
+		if compression == CompressionZlib && ((n+1)%framesPerBlock == 0 || n == frames-1) {
+			var buf bytes.Buffer
+			w := zlib.NewWriter(&buf)
+			w.Write(raw)
+			w.Close()
+			blocks = append(blocks, buf.Bytes())
+			blockFrames = append(blockFrames, n/framesPerBlock*framesPerBlock)
+			raw = nil
+		}
+	}
+	dataOffset := 32 + len(blocks)*8 + len(spans)*6
+	header := make([]byte, 32, dataOffset)
+	copy(header, "PSEQ")
+	binary.LittleEndian.PutUint16(header[4:], uint16(dataOffset))
+	header[6] = 0
+	header[7] = 2
+	binary.LittleEndian.PutUint16(header[8:], 32)
+	binary.LittleEndian.PutUint32(header[10:], uint32(channels))
+	binary.LittleEndian.PutUint32(header[14:], uint32(frames))
+	header[18] = 25
+	header[20] = compression
+	header[21] = byte(len(blocks))
+	header[22] = byte(len(spans))
+	for i, b := range blocks {
+		header = binary.LittleEndian.AppendUint32(header, uint32(blockFrames[i]))
+		header = binary.LittleEndian.AppendUint32(header, uint32(len(b)))
+	}
+	for _, s := range spans {
+		header = append(header, byte(s.start), byte(s.start>>8), byte(s.start>>16))
+		header = append(header, byte(s.count), byte(s.count>>8), byte(s.count>>16))
+	}
+	for _, b := range blocks {
+		header = append(header, b...)
+	}
+	return append(header, raw...)
+}
+
+func TestReadFrame(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		compression uint8
+		spans       []span
+	}{
+		{"uncompressed", CompressionNone, nil},
+		{"zlib", CompressionZlib, nil},
+		{"sparse", CompressionNone, []span{{3, 6}, {12, 3}}},
+		{"sparse zlib", CompressionZlib, []span{{3, 6}, {12, 3}}},
+	} {
+		const channels, frames = 15, 10
+		f, err := Open(bytes.NewReader(buildV2(channels, frames, tc.compression, 4, tc.spans)))
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if f.Channels != channels || f.Frames != frames || f.Step != 25*time.Millisecond {
+			t.Errorf("%s: unexpected header: %d channels, %d frames, step %v", tc.name, f.Channels, f.Frames, f.Step)
+		}
+		dst := make([]byte, channels)
+		// Read frames in order and out of order.
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 9, 2, 7} {
+			if err := f.ReadFrame(n, dst); err != nil {
+				t.Errorf("%s: frame %d: %v", tc.name, n, err)
+				continue
+			}
+			expected := testFrame(n, channels)
+			if tc.spans != nil {
+				stored := testFrame(n, 9)
+				expected = make([]byte, channels)
+				copy(expected[3:9], stored[:6])
+				copy(expected[12:15], stored[6:])
+			}
+			if !bytes.Equal(dst, expected) {
+				t.Errorf("%s: frame %d: expected %v, got %v", tc.name, n, expected, dst)
+			}
+		}
+		if err := f.ReadFrame(frames, dst); err == nil {
+			t.Errorf("%s: expected an error for an out of range frame", tc.name)
+		}
+	}
+}
+
+func TestOpenVersion1(t *testing.T) {
+	data := make([]byte, 28)
+	copy(data, "FSEQ")
+	binary.LittleEndian.PutUint16(data[4:], 28)
+	data[7] = 1
+	binary.LittleEndian.PutUint32(data[10:], 6)
+	binary.LittleEndian.PutUint32(data[14:], 2)
+	data[18] = 50
+	data = append(data, testFrame(0, 6)...)
+	data = append(data, testFrame(1, 6)...)
+	f, err := Open(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := make([]byte, 6)
+	if err := f.ReadFrame(1, dst); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dst, testFrame(1, 6)) {
+		t.Errorf("unexpected frame data: %v", dst)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	if _, err := Open(bytes.NewReader(make([]byte, 32))); err != errInvalid {
+		t.Errorf("expected errInvalid, got %v", err)
+	}
+	data := buildV2(3, 1, CompressionNone, 1, nil)
+	data[20] = CompressionZstd
+	if _, err := Open(bytes.NewReader(data)); err != errUnsupported {
+		t.Errorf("expected errUnsupported, got %v", err)
+	}
+
+	// Headers that don't match the data are rejected before allocating the
+	// frame buffer.
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"no channels", buildV2(0, 1, CompressionNone, 1, nil)},
+		{"too many channels", withHeader(buildV2(3, 1, CompressionNone, 1, nil), 10, 0xffffffff)},
+		{"too many frames", withHeader(buildV2(3, 1, CompressionNone, 1, nil), 14, 0xffffffff)},
+		{"truncated", withHeader(buildV2(3, 2, CompressionNone, 1, nil), 10, 1<<20)},
+		{"truncated sparse", withHeader(buildV2(15, 2, CompressionNone, 1, []span{{3, 6}}), 14, 1000)},
+		{"truncated zlib", buildV2(15, 10, CompressionZlib, 4, nil)[:100]},
+		{"overlapping ranges", buildV2(15, 2, CompressionNone, 1, []span{{0, 10}, {5, 10}})},
+	} {
+		if _, err := Open(bytes.NewReader(tc.data)); err != errInvalid {
+			t.Errorf("%s: expected errInvalid, got %v", tc.name, err)
+		}
+	}
+}
+
+// withHeader returns a copy of data with the 32-bit header field at the given
+// offset set to value.
+func withHeader(data []byte, offset int, value uint32) []byte {
+	data = append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(data[offset:], value)
+	return data
+}
+
+func TestPlayer(t *testing.T) {
+	f, err := Open(bytes.NewReader(buildV2(12, 2, CompressionNone, 1, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var a, b, c, d ledstest.FrameRecorder
+	p := NewPlayer(f, []Output{
+		{Start: 0, LEDs: 2, Displayer: &a},
+		{Start: 9, LEDs: 2, Order: ledsgo.OrderGRB, Displayer: &b},
+		{Start: -4, LEDs: 3, Displayer: &c},
+		{Start: -100, LEDs: 2, Displayer: &d},
+	})
+	if err := p.ShowFrame(1); err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 1 || len(b) != 1 || len(c) != 1 || len(d) != 1 {
+		t.Fatalf("expected a frame on every output")
+	}
+	if a[0][0] != (color.RGBA{R: 16, G: 17, B: 18}) || a[0][1] != (color.RGBA{R: 19, G: 20, B: 21}) {
+		t.Errorf("unexpected first output: %v", a[0])
+	}
+	// The second LED of the second output is beyond the last channel.
+	if b[0][0] != (color.RGBA{R: 26, G: 25, B: 27}) || b[0][1] != (color.RGBA{}) {
+		t.Errorf("unexpected second output: %v", b[0])
+	}
+	// LEDs with channels before the start of the sequence are black.
+	if c[0][0] != (color.RGBA{}) || c[0][1] != (color.RGBA{}) || c[0][2] != (color.RGBA{R: 18, G: 19, B: 20}) {
+		t.Errorf("unexpected output with a negative start: %v", c[0])
+	}
+	if d[0][0] != (color.RGBA{}) || d[0][1] != (color.RGBA{}) {
+		t.Errorf("unexpected output before the sequence: %v", d[0])
+	}
+}
@@ -0,0 +1,79 @@
+package fseq
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Output maps a range of channels of a sequence onto a Displayer. In xLights,
+// this corresponds to the start channel of a model or controller port.
+type Output struct {
+	Start     int               // first channel, counting from 0
+	LEDs      int               // number of LEDs
+	Order     ledsgo.ColorOrder // channel order in the sequence
+	Displayer ledsgo.Displayer
+}
+
+// Player plays a sequence on one or more outputs.
+type Player struct {
+	File    *File
+	Outputs []Output
+
+	data   []byte
+	frames []ledsgo.Strip
+}
+
+// NewPlayer returns a new player for the given file and outputs.
+func NewPlayer(f *File, outputs []Output) *Player {
+	p := &Player{
+		File:    f,
+		Outputs: outputs,
+		data:    make([]byte, f.Channels),
+		frames:  make([]ledsgo.Strip, len(outputs)),
+	}
+	for i, out := range outputs {
+		p.frames[i] = make(ledsgo.Strip, out.LEDs)
+	}
+	return p
+}
+
+// ShowFrame reads the given frame and sends it to all outputs. Channels
+// outside of the sequence are black.
+func (p *Player) ShowFrame(n int) error {
+	if err := p.File.ReadFrame(n, p.data); err != nil {
+		return err
+	}
+	for i, out := range p.Outputs {
+		frame := p.frames[i]
+		frame.FillSolid(color.RGBA{})
+		start, skip := out.Start, 0
+		if start < 0 {
+			// Skip the LEDs with channels before the start of the sequence.
+			channels := out.Order.Channels()
+			skip = min((-start+channels-1)/channels, len(frame))
+			start += skip * channels
+		}
+		if start >= 0 && start < len(p.data) {
+			out.Order.DecodeStrip(frame[skip:], p.data[start:])
+		}
+		if err := out.Displayer.Display(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Play shows all frames of the sequence at the speed stored in the file.
+func (p *Player) Play() error {
+	next := time.Now()
+	for n := 0; n < p.File.Frames; n++ {
+		if err := p.ShowFrame(n); err != nil {
+			return err
+		}
+		next = next.Add(p.File.Step)
+		time.Sleep(time.Until(next))
+	}
+	return nil
+}
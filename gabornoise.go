@@ -0,0 +1,72 @@
+package ledsgo
+
+// GaborNoise is Gabor noise: sparse convolution noise (see SpotNoise) where
+// every spot is a small wave with a given orientation and frequency, faded
+// out towards its edge. The sum of many such waves looks like streaks or
+// brushed metal that all run in the same direction, which is impossible to
+// get from simplex noise. Rotating Angle over time gives a swirling effect.
+//
+// Coordinates are 19.12 fixed-point values like those of Noise2, where a cell
+// of the grid is 0x1000 wide and high.
+type GaborNoise struct {
+	// Density is the number of waves in every cell. Higher densities give a
+	// more even texture.
+	Density uint8
+
+	// Radius is the radius of a wave. It is limited to 0x1000, the size of a
+	// cell.
+	Radius int32
+
+	// Angle is the direction in which the waves travel, where 0x10000 is a
+	// full circle (like the input of Sin16). The streaks are perpendicular to
+	// this direction.
+	Angle uint16
+
+	// Frequency is the number of wave periods per 0x1000, as a .12 fixed-point
+	// value. For example, 0x2000 fits two periods in a cell.
+	Frequency int32
+
+	// Seed selects a different pattern of waves.
+	Seed uint32
+}
+
+// At returns the noise value at (x, y), as a .15 fixed-point value. The sum of
+// all waves covering the point saturates at the limits of an int16, which
+// only happens for high densities.
+func (n *GaborNoise) At(x, y int32) int16 {
+	radius := min(n.Radius, 0x1000)
+	if radius <= 0 || n.Density == 0 {
+		return 0
+	}
+	r2 := uint64(radius) * uint64(radius)                      // .24
+	invR2 := (1 << 48) / r2                                    // .24: 1/r2
+	dirX, dirY := int64(Cos16(n.Angle)), int64(Sin16(n.Angle)) // .15
+	cellX, cellY := x>>12, y>>12                               // .0
+	var sum int32                                              // .15
+	for cy := cellY - 1; cy <= cellY+1; cy++ {
+		for cx := cellX - 1; cx <= cellX+1; cx++ {
+			for k := uint32(0); k < uint32(n.Density); k++ {
+				h := spotHash(uint32(cx), uint32(cy), k, n.Seed)
+
+				// Distance to the center of the wave, see SpotNoise.At.
+				dx := int64(int32(uint32(x) - (uint32(cx)<<12 + h&0xfff)))     // .12
+				dy := int64(int32(uint32(y) - (uint32(cy)<<12 + h>>12&0xfff))) // .12
+				d2 := uint64(dx*dx + dy*dy)                                    // .24
+				if d2 >= r2 {
+					continue
+				}
+				u := uint32(d2 * invR2 >> 32)                 // .16: squared distance relative to the radius
+				envelope := (0xffff - u) * (0xffff - u) >> 16 // .16: (1-u)²
+
+				// The phase of the wave is the distance along its direction
+				// (in periods), plus a random phase so that waves don't line
+				// up.
+				along := dx*dirX + dy*dirY                                       // .12 * .15 = .27
+				phase := uint16(along*int64(n.Frequency)>>23) + uint16(h>>24)<<8 // .27 * .12 >> 23 = .16
+				wave := int32(Cos16(phase))                                      // .15
+				sum += int32(int64(wave) * int64(envelope) >> 16)                // .15 * .16 >> 16 = .15
+			}
+		}
+	}
+	return int16(min(max(sum, -0x8000), 0x7fff))
+}
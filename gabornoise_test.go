@@ -0,0 +1,69 @@
+package ledsgo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGaborNoise(t *testing.T) {
+	for _, n := range []GaborNoise{
+		{Density: 2, Radius: 0x1000, Frequency: 0x2000},
+		{Density: 5, Radius: 0x0c00, Angle: 0x3000, Frequency: 0x1800, Seed: 77},
+		{Density: 1, Radius: 0x3000, Angle: 0xc123, Frequency: 0x4000}, // radius is limited
+	} {
+		// Floating point reference.
+		radius := math.Min(float64(n.Radius), 0x1000) / 4096
+		angle := float64(n.Angle) / 65536 * 2 * math.Pi
+		reference := func(x, y int32) float64 {
+			sum := 0.0
+			for cy := y>>12 - 2; cy <= y>>12+2; cy++ {
+				for cx := x>>12 - 2; cx <= x>>12+2; cx++ {
+					for k := uint32(0); k < uint32(n.Density); k++ {
+						h := spotHash(uint32(cx), uint32(cy), k, n.Seed)
+						dx := (float64(x) - float64(cx)*4096 - float64(h&0xfff)) / 4096
+						dy := (float64(y) - float64(cy)*4096 - float64(h>>12&0xfff)) / 4096
+						u := (dx*dx + dy*dy) / (radius * radius)
+						if u >= 1 {
+							continue
+						}
+						along := dx*math.Cos(angle) + dy*math.Sin(angle)
+						phase := along*float64(n.Frequency)/4096 + float64(h>>24)/256
+						sum += (1 - u) * (1 - u) * math.Cos(phase*2*math.Pi)
+					}
+				}
+			}
+			return math.Max(math.Min(sum, 1), -1)
+		}
+
+		for y := int32(-0x6000); y < 0x6000; y += 0x107 {
+			for x := int32(-0x6000); x < 0x6000; x += 0x0fd {
+				v := n.At(x, y)
+				if diff := math.Abs(float64(v)/32768 - reference(x, y)); diff > 0.005 {
+					t.Errorf("%+v: At(%d, %d) = %d, expected %.5f (diff %.5f)", n, x, y, v, reference(x, y)*32768, diff)
+				}
+			}
+		}
+	}
+
+	// The waves travel along the x axis, so the texture should change much
+	// faster along x than along y.
+	n := GaborNoise{Density: 8, Radius: 0x1000, Frequency: 0x3000}
+	var alongX, alongY int
+	for y := int32(0); y < 0x8000; y += 0x200 {
+		for x := int32(0); x < 0x8000; x += 0x200 {
+			v := int(n.At(x, y))
+			alongX += abs(int(n.At(x+0x80, y)) - v)
+			alongY += abs(int(n.At(x, y+0x80)) - v)
+		}
+	}
+	if alongX < alongY*3 {
+		t.Errorf("expected oriented texture, got differences %d along x and %d along y", alongX, alongY)
+	}
+}
+
+func BenchmarkGaborNoise(b *testing.B) {
+	n := GaborNoise{Density: 2, Radius: 0x0c00, Frequency: 0x2000}
+	for i := 0; i < b.N; i++ {
+		n.At(int32(i)*0x35, 0x1234)
+	}
+}
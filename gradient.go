@@ -0,0 +1,143 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// GradientStop is a color at a position in a GradientPalette.
+type GradientStop struct {
+	Pos   uint8 // position in the palette, 0..255
+	Color color.RGBA
+}
+
+// GradientPalette is a palette described by a list of colors at given
+// positions, like the gradient palettes of FastLED (DEFINE_GRADIENT_PALETTE).
+// Unlike a Palette16, the colors need not be spread evenly: a gradient can
+// have a sharp change at one point and a slow fade elsewhere. The stops must
+// be sorted by position.
+//
+// Looking up colors in a GradientPalette is slower than in a Palette16, so
+// effects that look up many colors every frame should convert it with
+// Palette16 or Palette once.
+type GradientPalette []GradientStop
+
+// ColorAt returns the color at the given position. Colors between two stops
+// are linearly interpolated. Positions before the first stop get the color of
+// the first stop and positions after the last stop get the color of the last
+// stop. An empty gradient returns black.
+func (g GradientPalette) ColorAt(index uint8) color.RGBA {
+	if len(g) == 0 {
+		return color.RGBA{}
+	}
+	if index <= g[0].Pos {
+		return g[0].Color
+	}
+	for i := 1; i < len(g); i++ {
+		b := g[i]
+		if index > b.Pos {
+			continue
+		}
+		a := g[i-1]
+		frac := uint16(index-a.Pos) * 256 / uint16(b.Pos-a.Pos) // b.Pos > a.Pos, see above
+		if frac > 255 {
+			return b.Color
+		}
+		return Blend(a.Color, b.Color, uint8(frac))
+	}
+	return g[len(g)-1].Color
+}
+
+// Palette returns the gradient sampled into a palette of the given size,
+// where the first entry is the color at position 0 and the last entry the
+// color at position 255.
+func (g GradientPalette) Palette(size int) Palette {
+	p := make(Palette, size)
+	g.sample(p)
+	return p
+}
+
+// Palette16 returns the gradient sampled into a 16-entry palette, like
+// Palette.
+func (g GradientPalette) Palette16() Palette16 {
+	var p Palette16
+	g.sample(p[:])
+	return p
+}
+
+// sample fills p with colors from the gradient, spread evenly.
+func (g GradientPalette) sample(p []color.RGBA) {
+	if len(p) == 1 {
+		p[0] = g.ColorAt(0)
+		return
+	}
+	for i := range p {
+		p[i] = g.ColorAt(uint8(i * 255 / (len(p) - 1)))
+	}
+}
+
+// ViridisColors is the viridis colormap of matplotlib: dark blue through teal
+// to yellow. Like the other colormaps below, it is perceptually uniform (equal
+// steps in the index look like equal steps in color) and it gets lighter all
+// the way, so it shows measurements such as sensor data faithfully, even to
+// people with color vision deficiencies. The colormaps are approximated by
+// 16 stops, which are within a few steps of the original colors.
+var ViridisColors = GradientPalette{
+	{0, color.RGBA{0x47, 0x01, 0x55, 0}},
+	{17, color.RGBA{0x48, 0x19, 0x6c, 0}},
+	{34, color.RGBA{0x47, 0x30, 0x7d, 0}},
+	{51, color.RGBA{0x42, 0x44, 0x88, 0}},
+	{68, color.RGBA{0x3b, 0x57, 0x8d, 0}},
+	{85, color.RGBA{0x31, 0x68, 0x8e, 0}},
+	{102, color.RGBA{0x28, 0x78, 0x8e, 0}},
+	{119, color.RGBA{0x21, 0x88, 0x8c, 0}},
+	{136, color.RGBA{0x1f, 0x98, 0x89, 0}},
+	{153, color.RGBA{0x25, 0xa8, 0x84, 0}},
+	{170, color.RGBA{0x35, 0xb7, 0x79, 0}},
+	{187, color.RGBA{0x51, 0xc5, 0x67, 0}},
+	{204, color.RGBA{0x78, 0xd1, 0x4e, 0}},
+	{221, color.RGBA{0xa6, 0xdb, 0x33, 0}},
+	{238, color.RGBA{0xd5, 0xe2, 0x1e, 0}},
+	{255, color.RGBA{0xfc, 0xe7, 0x21, 0}},
+}
+
+// InfernoColors is the inferno colormap of matplotlib: black through purple and
+// orange to light yellow.
+var InfernoColors = GradientPalette{
+	{0, color.RGBA{0x00, 0x00, 0x00, 0}},
+	{17, color.RGBA{0x0c, 0x07, 0x2f, 0}},
+	{34, color.RGBA{0x25, 0x0a, 0x4d, 0}},
+	{51, color.RGBA{0x41, 0x0d, 0x60, 0}},
+	{68, color.RGBA{0x5d, 0x12, 0x69, 0}},
+	{85, color.RGBA{0x79, 0x19, 0x6c, 0}},
+	{102, color.RGBA{0x93, 0x23, 0x67, 0}},
+	{119, color.RGBA{0xad, 0x2f, 0x5a, 0}},
+	{136, color.RGBA{0xc6, 0x3d, 0x48, 0}},
+	{153, color.RGBA{0xdb, 0x50, 0x32, 0}},
+	{170, color.RGBA{0xed, 0x66, 0x1d, 0}},
+	{187, color.RGBA{0xf7, 0x82, 0x10, 0}},
+	{204, color.RGBA{0xfb, 0xa3, 0x11, 0}},
+	{221, color.RGBA{0xf7, 0xc6, 0x27, 0}},
+	{238, color.RGBA{0xf3, 0xe8, 0x58, 0}},
+	{255, color.RGBA{0xfa, 0xff, 0xa8, 0}},
+}
+
+// MagmaColors is the magma colormap of matplotlib: black through purple and
+// pink to light yellow.
+var MagmaColors = GradientPalette{
+	{0, color.RGBA{0x00, 0x00, 0x00, 0}},
+	{17, color.RGBA{0x0b, 0x08, 0x28, 0}},
+	{34, color.RGBA{0x21, 0x0d, 0x4e, 0}},
+	{51, color.RGBA{0x3b, 0x12, 0x6b, 0}},
+	{68, color.RGBA{0x56, 0x17, 0x7e, 0}},
+	{85, color.RGBA{0x72, 0x1d, 0x86, 0}},
+	{102, color.RGBA{0x8e, 0x25, 0x85, 0}},
+	{119, color.RGBA{0xaa, 0x2f, 0x7d, 0}},
+	{136, color.RGBA{0xc5, 0x3c, 0x71, 0}},
+	{153, color.RGBA{0xdc, 0x4d, 0x66, 0}},
+	{170, color.RGBA{0xef, 0x62, 0x60, 0}},
+	{187, color.RGBA{0xfc, 0x7d, 0x61, 0}},
+	{204, color.RGBA{0xff, 0x9d, 0x6c, 0}},
+	{221, color.RGBA{0xfe, 0xc0, 0x81, 0}},
+	{238, color.RGBA{0xfb, 0xe2, 0x9d, 0}},
+	{255, color.RGBA{0xfe, 0xf9, 0xba, 0}},
+}
@@ -0,0 +1,70 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestGradientPalette(t *testing.T) {
+	red, yellow, blue := color.RGBA{R: 255}, color.RGBA{R: 255, G: 255}, color.RGBA{B: 255}
+	g := GradientPalette{{0, red}, {64, yellow}, {64, blue}, {255, blue}}
+	for _, tc := range []struct {
+		index uint8
+		c     color.RGBA
+	}{
+		{0, red},
+		{32, Blend(red, yellow, 128)},
+		{63, Blend(red, yellow, 252)},
+		{64, yellow}, // the first of two stops at the same position
+		{65, blue},
+		{255, blue},
+	} {
+		if c := g.ColorAt(tc.index); c != tc.c {
+			t.Errorf("ColorAt(%d): expected %v, got %v", tc.index, tc.c, c)
+		}
+	}
+
+	// Before the first and after the last stop.
+	g = GradientPalette{{100, red}, {200, blue}}
+	if c := g.ColorAt(10); c != red {
+		t.Errorf("before the first stop: expected %v, got %v", red, c)
+	}
+	if c := g.ColorAt(250); c != blue {
+		t.Errorf("after the last stop: expected %v, got %v", blue, c)
+	}
+
+	p := g.Palette16()
+	if p[0] != red || p[15] != blue || p[8] != g.ColorAt(136) {
+		t.Errorf("Palette16: unexpected result %v", p)
+	}
+	if p2 := g.Palette(16); p2[3] != p[3] {
+		t.Errorf("Palette: expected the same colors as Palette16, got %v", p2)
+	}
+	if c := (GradientPalette{}).ColorAt(5); c != (color.RGBA{}) {
+		t.Errorf("empty gradient: expected black, got %v", c)
+	}
+}
+
+func TestColormaps(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		g    GradientPalette
+	}{
+		{"viridis", ViridisColors},
+		{"inferno", InfernoColors},
+		{"magma", MagmaColors},
+	} {
+		// The lightness must increase evenly over the whole colormap.
+		prev := toLCh(tc.g.ColorAt(0)).L
+		for i := 8; i < 256; i += 8 {
+			L := toLCh(tc.g.ColorAt(uint8(i))).L
+			if L <= prev {
+				t.Errorf("%s: lightness at %d (%d) is not higher than before (%d)", tc.name, i, L, prev)
+			}
+			prev = L
+		}
+		if len(tc.g) != 16 || tc.g[15].Pos != 255 {
+			t.Errorf("%s: expected 16 stops up to 255", tc.name)
+		}
+	}
+}
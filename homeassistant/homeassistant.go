@@ -0,0 +1,254 @@
+// Package homeassistant exposes a ledsgo.Runner as a light over MQTT, using
+// the JSON schema for lights with MQTT discovery in Home Assistant. The light
+// shows up in Home Assistant automatically and supports on/off, brightness,
+// color and effect selection.
+//
+// This package does not include an MQTT client. Instead, it uses the small
+// Client interface which can be implemented on top of any MQTT library.
+package homeassistant
+
+import (
+	"encoding/json"
+	"errors"
+	"image/color"
+	"sync"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// DefaultDiscoveryPrefix is the discovery prefix Home Assistant uses by default.
+const DefaultDiscoveryPrefix = "homeassistant"
+
+// Client is the subset of an MQTT client used by this package.
+type Client interface {
+	// Publish publishes the payload on the given topic.
+	Publish(topic string, payload []byte, retain bool) error
+
+	// Subscribe calls the handler for every message received on the given
+	// topic.
+	Subscribe(topic string, handler func(topic string, payload []byte)) error
+}
+
+// Effect is an effect that can be selected from Home Assistant.
+type Effect struct {
+	Name string
+
+	// New returns a new instance of the effect with the given color. It is
+	// called again whenever the color changes.
+	New func(c color.RGBA) ledsgo.Effect
+}
+
+// Light is a Home Assistant light backed by a Runner.
+type Light struct {
+	// ID uniquely identifies this light. It is used in the MQTT topics and
+	// must only contain letters, digits, underscores and dashes.
+	ID string
+
+	// Name is the name shown in Home Assistant.
+	Name string
+
+	// DiscoveryPrefix is the prefix of the discovery topic. If empty,
+	// DefaultDiscoveryPrefix is used.
+	DiscoveryPrefix string
+
+	client  Client
+	runner  *ledsgo.Runner
+	effects []Effect
+
+	lock   sync.Mutex
+	color  color.RGBA
+	effect int
+}
+
+// NewLight returns a new light for the given runner, offering the given
+// effects. The first effect is started immediately in white.
+func NewLight(client Client, runner *ledsgo.Runner, id, name string, effects []Effect) *Light {
+	l := &Light{
+		ID:      id,
+		Name:    name,
+		client:  client,
+		runner:  runner,
+		effects: effects,
+		color:   color.RGBA{255, 255, 255, 0},
+	}
+	l.updateEffect()
+	return l
+}
+
+// Topic returns the base topic of this light. The command, state and
+// availability topics are below this topic.
+func (l *Light) Topic() string {
+	return "ledsgo/" + l.ID
+}
+
+// Discovery configuration of a light, using the JSON schema.
+type discoveryConfig struct {
+	Name                string   `json:"name"`
+	UniqueID            string   `json:"unique_id"`
+	Schema              string   `json:"schema"`
+	CommandTopic        string   `json:"command_topic"`
+	StateTopic          string   `json:"state_topic"`
+	AvailabilityTopic   string   `json:"availability_topic"`
+	Brightness          bool     `json:"brightness"`
+	SupportedColorModes []string `json:"supported_color_modes"`
+	Effect              bool     `json:"effect"`
+	EffectList          []string `json:"effect_list,omitempty"`
+	Device              device   `json:"device"`
+}
+
+type device struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+}
+
+// Command and state messages of the JSON schema.
+type message struct {
+	State      string    `json:"state,omitempty"`
+	Brightness *uint8    `json:"brightness,omitempty"`
+	ColorMode  string    `json:"color_mode,omitempty"`
+	Color      *rgbColor `json:"color,omitempty"`
+	Effect     string    `json:"effect,omitempty"`
+}
+
+type rgbColor struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+}
+
+// Will returns the topic and payload of the last will that should be set when
+// connecting to the MQTT broker, so that Home Assistant shows the light as
+// unavailable when the connection is lost. The will should be retained.
+func (l *Light) Will() (topic string, payload []byte) {
+	return l.Topic() + "/status", []byte("offline")
+}
+
+// Start subscribes to the command topic and announces the light to Home
+// Assistant. It should be called again whenever the MQTT connection is
+// re-established.
+func (l *Light) Start() error {
+	if err := l.client.Subscribe(l.Topic()+"/set", func(topic string, payload []byte) {
+		l.HandleCommand(payload)
+	}); err != nil {
+		return err
+	}
+	prefix := l.DiscoveryPrefix
+	if prefix == "" {
+		prefix = DefaultDiscoveryPrefix
+	}
+	config := discoveryConfig{
+		Name:                l.Name,
+		UniqueID:            "ledsgo_" + l.ID,
+		Schema:              "json",
+		CommandTopic:        l.Topic() + "/set",
+		StateTopic:          l.Topic() + "/state",
+		AvailabilityTopic:   l.Topic() + "/status",
+		Brightness:          true,
+		SupportedColorModes: []string{"rgb"},
+		Effect:              len(l.effects) != 0,
+		Device: device{
+			Identifiers:  []string{"ledsgo_" + l.ID},
+			Name:         l.Name,
+			Manufacturer: "ledsgo",
+			Model:        "ledsgo",
+		},
+	}
+	for _, effect := range l.effects {
+		config.EffectList = append(config.EffectList, effect.Name)
+	}
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := l.client.Publish(prefix+"/light/"+l.ID+"/config", payload, true); err != nil {
+		return err
+	}
+	if err := l.client.Publish(l.Topic()+"/status", []byte("online"), true); err != nil {
+		return err
+	}
+	return l.PublishState()
+}
+
+// Close announces to Home Assistant that the light is no longer available. It
+// should be called before disconnecting from the MQTT broker, as the last will
+// is only published when the connection is lost.
+func (l *Light) Close() error {
+	topic, payload := l.Will()
+	return l.client.Publish(topic, payload, true)
+}
+
+// HandleCommand handles a message received on the command topic and publishes
+// the resulting state.
+func (l *Light) HandleCommand(payload []byte) error {
+	var msg message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return err
+	}
+	l.lock.Lock()
+	switch msg.State {
+	case "ON":
+		l.runner.SetOn(true)
+	case "OFF":
+		l.runner.SetOn(false)
+	case "":
+	default:
+		l.lock.Unlock()
+		return errors.New("homeassistant: invalid state: " + msg.State)
+	}
+	if msg.Brightness != nil {
+		l.runner.SetBrightness(*msg.Brightness)
+	}
+	changed := false
+	if msg.Color != nil {
+		l.color = color.RGBA{msg.Color.R, msg.Color.G, msg.Color.B, 0}
+		changed = true
+	}
+	if msg.Effect != "" {
+		for i, effect := range l.effects {
+			if effect.Name == msg.Effect {
+				l.effect = i
+				changed = true
+			}
+		}
+	}
+	if changed {
+		l.updateEffect()
+	}
+	l.lock.Unlock()
+	return l.PublishState()
+}
+
+// updateEffect (re)starts the current effect with the current color.
+func (l *Light) updateEffect() {
+	if l.effect < len(l.effects) {
+		l.runner.SetEffect(l.effects[l.effect].New(l.color))
+	}
+}
+
+// PublishState publishes the current state on the state topic. It is called
+// automatically after handling a command, but should also be called after
+// changing the runner in some other way.
+func (l *Light) PublishState() error {
+	l.lock.Lock()
+	brightness := l.runner.Brightness()
+	msg := message{
+		State:      "OFF",
+		Brightness: &brightness,
+		ColorMode:  "rgb",
+		Color:      &rgbColor{l.color.R, l.color.G, l.color.B},
+	}
+	if l.runner.On() {
+		msg.State = "ON"
+	}
+	if l.effect < len(l.effects) {
+		msg.Effect = l.effects[l.effect].Name
+	}
+	l.lock.Unlock()
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return l.client.Publish(l.Topic()+"/state", payload, true)
+}
@@ -0,0 +1,90 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// fakeClient records published messages and the subscribed handlers.
+type fakeClient struct {
+	published map[string][]byte
+	handlers  map[string]func(topic string, payload []byte)
+}
+
+func (c *fakeClient) Publish(topic string, payload []byte, retain bool) error {
+	c.published[topic] = payload
+	return nil
+}
+
+func (c *fakeClient) Subscribe(topic string, handler func(topic string, payload []byte)) error {
+	c.handlers[topic] = handler
+	return nil
+}
+
+type nullDisplayer struct{}
+
+func (nullDisplayer) Display(frame ledsgo.Strip) error {
+	return nil
+}
+
+func TestLight(t *testing.T) {
+	client := &fakeClient{
+		published: make(map[string][]byte),
+		handlers:  make(map[string]func(string, []byte)),
+	}
+	runner := ledsgo.NewRunner(nullDisplayer{}, 10)
+	var lastColor color.RGBA
+	solid := func(c color.RGBA) ledsgo.Effect {
+		lastColor = c
+		return ledsgo.EffectFunc(func(frame ledsgo.Strip, t time.Duration) {
+			frame.FillSolid(c)
+		})
+	}
+	light := NewLight(client, runner, "desk", "Desk", []Effect{{"Solid", solid}, {"Other", solid}})
+	if err := light.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(client.published["homeassistant/light/desk/config"], &config); err != nil {
+		t.Fatal(err)
+	}
+	if config["command_topic"] != "ledsgo/desk/set" || config["schema"] != "json" {
+		t.Errorf("unexpected discovery config: %v", config)
+	}
+	if string(client.published["ledsgo/desk/status"]) != "online" {
+		t.Error("light not announced as online")
+	}
+
+	handler := client.handlers["ledsgo/desk/set"]
+	handler("ledsgo/desk/set", []byte(`{"state":"OFF"}`))
+	if runner.On() {
+		t.Error("expected the light to be off")
+	}
+	handler("ledsgo/desk/set", []byte(`{"state":"ON","brightness":100,"color":{"r":1,"g":2,"b":3},"effect":"Other"}`))
+	if !runner.On() || runner.Brightness() != 100 || lastColor != (color.RGBA{1, 2, 3, 0}) {
+		t.Errorf("command not applied: on=%v brightness=%d color=%v", runner.On(), runner.Brightness(), lastColor)
+	}
+	var state message
+	if err := json.Unmarshal(client.published["ledsgo/desk/state"], &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.State != "ON" || state.Effect != "Other" || *state.Brightness != 100 {
+		t.Errorf("unexpected state: %+v", state)
+	}
+
+	topic, payload := light.Will()
+	if topic != "ledsgo/desk/status" || string(payload) != "offline" {
+		t.Errorf("unexpected last will: %s %s", topic, payload)
+	}
+	if err := light.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if string(client.published["ledsgo/desk/status"]) != "offline" {
+		t.Error("light not announced as offline after Close")
+	}
+}
@@ -0,0 +1,62 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// RotateHue shifts the hue of every color in the frame by delta, where 0x10000
+// is a full circle. This is exact and very cheap, so effects that need global
+// color cycling are best rendered as HSV colors. For content that is already
+// rendered to RGB (such as images and text), see Strip.RotateHue.
+func RotateHue(frame []Color, delta uint16) {
+	for i := range frame {
+		frame[i].H += delta
+	}
+}
+
+// RotateHue shifts the hue of every LED by delta, where 0x10000 is a full
+// circle (like the hue of Color). Instead of converting every LED to HSV and
+// back, it multiplies the colors with a rotation matrix around the gray axis,
+// the same approximation as the hue-rotate CSS filter. Gray colors and the
+// brightness of colors stay the same, but saturated colors may be clipped
+// somewhat. A Canvas can be rotated with Strip(canvas.Pix).RotateHue.
+func (s Strip) RotateHue(delta uint16) {
+	if delta == 0 {
+		return
+	}
+	m := hueRotation(delta)
+	for i, c := range s {
+		r, g, b := int32(c.R), int32(c.G), int32(c.B)
+		s[i] = color.RGBA{
+			R: clampChannel(m[0][0]*r + m[0][1]*g + m[0][2]*b),
+			G: clampChannel(m[1][0]*r + m[1][1]*g + m[1][2]*b),
+			B: clampChannel(m[2][0]*r + m[2][1]*g + m[2][2]*b),
+			A: c.A,
+		}
+	}
+}
+
+// hueRotation returns the matrix of the hue-rotate CSS filter for the given
+// angle, as .16 fixed-point values. See
+// https://www.w3.org/TR/filter-effects-1/#feColorMatrixElement for the
+// definition.
+func hueRotation(delta uint16) (m [3][3]int32) {
+	// Luminance weights and the cosine and sine factors of the matrix, all as
+	// .16 fixed-point values.
+	lum := [3]int32{13959, 46858, 4719}
+	cosFactors := [3][3]int32{{51577, -46858, -4719}, {-13959, 18678, -4719}, {-13959, -46858, 60817}}
+	sinFactors := [3][3]int32{{-13959, -46858, 60817}, {9372, 9175, -18547}, {-51577, 46858, 4719}}
+	cos, sin := int64(Cos16(delta)), int64(Sin16(delta)) // .15
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = lum[j] + int32((cos*int64(cosFactors[i][j])+sin*int64(sinFactors[i][j])+0x4000)>>15)
+		}
+	}
+	return m
+}
+
+// clampChannel converts a .16 fixed-point channel value to 8 bits, rounding it
+// and clipping it to the range 0..255.
+func clampChannel(v int32) uint8 {
+	return uint8(min(max((v+0x8000)>>16, 0), 255))
+}
@@ -0,0 +1,57 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestRotateHue(t *testing.T) {
+	frame := []Color{{H: 0, S: 255, V: 255}, {H: 0xf000, S: 100, V: 50}}
+	RotateHue(frame, 0x2000)
+	if frame[0] != (Color{H: 0x2000, S: 255, V: 255}) || frame[1] != (Color{H: 0x1000, S: 100, V: 50}) {
+		t.Errorf("RotateHue: got %v", frame)
+	}
+
+	s := Strip{{255, 0, 0, 0}, {0, 255, 0, 7}, {0, 0, 255, 0}, {100, 100, 100, 0}, {}}
+	orig := append(Strip(nil), s...)
+	s.RotateHue(0)
+	for i := range s {
+		if s[i] != orig[i] {
+			t.Errorf("RotateHue(0): LED %d changed from %v to %v", i, orig[i], s[i])
+		}
+	}
+
+	// A rotation by a third of the circle moves red towards green, green
+	// towards blue and blue towards red. Grays stay the same.
+	s.RotateHue(0x5555)
+	if s[0].G <= s[0].R || s[0].G <= s[0].B {
+		t.Errorf("red: expected a greenish color, got %v", s[0])
+	}
+	if s[1].B <= s[1].R || s[1].B <= s[1].G || s[1].A != 7 {
+		t.Errorf("green: expected a blueish color, got %v", s[1])
+	}
+	if s[2].R <= s[2].G || s[2].R <= s[2].B {
+		t.Errorf("blue: expected a reddish color, got %v", s[2])
+	}
+	if s[3] != orig[3] || s[4] != orig[4] {
+		t.Errorf("gray: expected no change, got %v and %v", s[3], s[4])
+	}
+
+	// Rotating by a full circle in steps ends up (nearly) at the original
+	// colors.
+	s = Strip{{200, 100, 50, 0}}
+	for i := 0; i < 4; i++ {
+		s.RotateHue(0x4000)
+	}
+	if c := s[0]; abs(int(c.R)-200) > 3 || abs(int(c.G)-100) > 3 || abs(int(c.B)-50) > 3 {
+		t.Errorf("full circle: expected about %v, got %v", color.RGBA{200, 100, 50, 0}, c)
+	}
+}
+
+func BenchmarkRotateHue(b *testing.B) {
+	s := make(Strip, 300)
+	s.FillRainbow(0, 200)
+	for i := 0; i < b.N; i++ {
+		s.RotateHue(0x100)
+	}
+}
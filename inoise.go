@@ -0,0 +1,120 @@
+package ledsgo
+
+// This file contains wrappers around the noise functions with the input
+// scaling and output ranges of the inoise8 and inoise16 functions in FastLED,
+// so that effects can be ported without rescaling every coordinate by hand.
+//
+// Note that FastLED uses Perlin noise while this package uses simplex noise.
+// The scale, speed and range of the patterns are the same, but the exact
+// patterns are not.
+//
+// Like in FastLED, the patterns continue seamlessly when a coordinate wraps
+// around, so that a time coordinate may overflow. The 1D noise tiles by
+// itself. The lattice of 2D and 3D simplex noise doesn't line up at the wrap,
+// so these crossfade to the pattern after the wrap during the last noise unit
+// before it (the last 0x100 of an 8.8 coordinate, the last 0x10000 of a 16.16
+// coordinate), like NoiseTime.Crossfade.
+
+// Wrap-around periods of the coordinates of the 8-bit and 16-bit functions, as
+// 19.12 fixed-point values.
+const (
+	inoise8Period  = 0x10000 << 4
+	inoise16Period = 0x10000 << 12
+)
+
+// Inoise16 returns 1D noise for a 16.16 fixed-point coordinate, as an unsigned
+// value centered around 0x8000.
+func Inoise16(x uint32) uint16 {
+	return noiseToUint16(Noise1(int32(x >> 4)))
+}
+
+// Inoise16XY returns 2D noise for 16.16 fixed-point coordinates, as an
+// unsigned value centered around 0x8000.
+func Inoise16XY(x, y uint32) uint16 {
+	return inoise2(int32(x>>4), int32(y>>4), inoise16Period)
+}
+
+// Inoise16XYZ returns 3D noise for 16.16 fixed-point coordinates, as an
+// unsigned value centered around 0x8000.
+func Inoise16XYZ(x, y, z uint32) uint16 {
+	return inoise3(int32(x>>4), int32(y>>4), int32(z>>4), inoise16Period)
+}
+
+// Inoise8 returns 1D noise for a 8.8 fixed-point coordinate, as an unsigned
+// value centered around 0x80.
+func Inoise8(x uint16) uint8 {
+	return uint8(noiseToUint16(Noise1(int32(x)<<4)) >> 8)
+}
+
+// Inoise8XY returns 2D noise for 8.8 fixed-point coordinates, as an unsigned
+// value centered around 0x80.
+func Inoise8XY(x, y uint16) uint8 {
+	return uint8(inoise2(int32(x)<<4, int32(y)<<4, inoise8Period) >> 8)
+}
+
+// Inoise8XYZ returns 3D noise for 8.8 fixed-point coordinates, as an unsigned
+// value centered around 0x80.
+func Inoise8XYZ(x, y, z uint16) uint8 {
+	return uint8(inoise3(int32(x)<<4, int32(y)<<4, int32(z)<<4, inoise8Period) >> 8)
+}
+
+// inoise2 returns Noise2 at the coordinates in [0, period), crossfaded to the
+// pattern after the wrap near the end of the period.
+func inoise2(x, y, period int32) uint16 {
+	nextX, fracX := inoiseWrap(x, period)
+	nextY, fracY := inoiseWrap(y, period)
+	noise := func(y int32) int32 {
+		n := int32(Noise2(x, y))
+		if fracX != 0 {
+			n += (int32(Noise2(nextX, y)) - n) * fracX >> 12
+		}
+		return n
+	}
+	n := noise(y)
+	if fracY != 0 {
+		n += (noise(nextY) - n) * fracY >> 12
+	}
+	return noiseToUint16(int16(n))
+}
+
+// inoise3 is like inoise2, for Noise3.
+func inoise3(x, y, z, period int32) uint16 {
+	nextX, fracX := inoiseWrap(x, period)
+	nextY, fracY := inoiseWrap(y, period)
+	nextZ, fracZ := inoiseWrap(z, period)
+	noiseX := func(y, z int32) int32 {
+		n := int32(Noise3(x, y, z))
+		if fracX != 0 {
+			n += (int32(Noise3(nextX, y, z)) - n) * fracX >> 12
+		}
+		return n
+	}
+	noiseY := func(z int32) int32 {
+		n := noiseX(y, z)
+		if fracY != 0 {
+			n += (noiseX(nextY, z) - n) * fracY >> 12
+		}
+		return n
+	}
+	n := noiseY(z)
+	if fracZ != 0 {
+		n += (noiseY(nextZ) - n) * fracZ >> 12
+	}
+	return noiseToUint16(int16(n))
+}
+
+// inoiseWrap returns the coordinate where the pattern continues after c wraps
+// around at the period, and the fraction (.12) of the way from c to it. The
+// fraction is 0 except during the last noise unit before the wrap.
+func inoiseWrap(c, period int32) (next, frac int32) {
+	remaining := period - c
+	if remaining > 0x1000 {
+		return c, 0
+	}
+	return c - period, 0x1000 - remaining
+}
+
+// noiseToUint16 converts a signed noise value to an unsigned one.
+func noiseToUint16(n int16) uint16 {
+	return uint16(int32(n) + 0x8000)
+}
@@ -0,0 +1,97 @@
+package ledsgo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestInoiseRange(t *testing.T) {
+	// The results must be centered around the middle of the range, and use
+	// most of it.
+	r := rand.New(rand.NewSource(0))
+	const n = 100000
+	var sum8, sum16 [3]int64 // int overflows on 32-bit platforms
+	var min8, max8 [3]uint8
+	var min16, max16 [3]uint16
+	for i := range min8 {
+		min8[i], min16[i] = 0xff, 0xffff
+	}
+	for i := 0; i < n; i++ {
+		x, y, z := r.Uint32(), r.Uint32(), r.Uint32()
+		values8 := [3]uint8{Inoise8(uint16(x)), Inoise8XY(uint16(x), uint16(y)), Inoise8XYZ(uint16(x), uint16(y), uint16(z))}
+		values16 := [3]uint16{Inoise16(x), Inoise16XY(x, y), Inoise16XYZ(x, y, z)}
+		for d := range values8 {
+			sum8[d] += int64(values8[d])
+			min8[d] = min(min8[d], values8[d])
+			max8[d] = max(max8[d], values8[d])
+			sum16[d] += int64(values16[d])
+			min16[d] = min(min16[d], values16[d])
+			max16[d] = max(max16[d], values16[d])
+		}
+	}
+	for d := range sum8 {
+		if avg := sum8[d] / n; avg < 0x78 || avg > 0x88 {
+			t.Errorf("%dD inoise8: average %#x is not centered", d+1, avg)
+		}
+		if min8[d] > 0x30 || max8[d] < 0xd0 {
+			t.Errorf("%dD inoise8: range [%#x, %#x] is too small", d+1, min8[d], max8[d])
+		}
+		if avg := sum16[d] / n; avg < 0x7800 || avg > 0x8800 {
+			t.Errorf("%dD inoise16: average %#x is not centered", d+1, avg)
+		}
+		if min16[d] > 0x3000 || max16[d] < 0xd000 {
+			t.Errorf("%dD inoise16: range [%#x, %#x] is too small", d+1, min16[d], max16[d])
+		}
+	}
+}
+
+func TestInoiseWrap(t *testing.T) {
+	// Walking a coordinate across the point where it wraps around must not
+	// show a jump, just like anywhere else.
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 20; i++ {
+		a, b := r.Uint32(), r.Uint32()
+		for axis := 0; axis < 3; axis++ {
+			coords16 := func(c uint32) (x, y, z uint32) {
+				x, y, z = a, b, a^b
+				switch axis {
+				case 0:
+					x = c
+				case 1:
+					y = c
+				case 2:
+					z = c
+				}
+				return
+			}
+			coords8 := func(c uint16) (uint16, uint16, uint16) {
+				x, y, z := coords16(uint32(c))
+				return uint16(x), uint16(y), uint16(z)
+			}
+
+			var prev8 [3]uint8
+			for c := uint16(0xfc00); c != 0x400; c++ {
+				x, y, z := coords8(c)
+				values := [3]uint8{Inoise8(x), Inoise8XY(x, y), Inoise8XYZ(x, y, z)}
+				for d, v := range values {
+					if c != 0xfc00 && d >= axis && abs(int(v)-int(prev8[d])) > 8 {
+						t.Fatalf("%dD inoise8 jumps from %d to %d at %#x on axis %d", d+1, prev8[d], v, c, axis)
+					}
+				}
+				prev8 = values
+			}
+
+			var prev16 [3]uint16
+			for c := uint32(0xfffc0000); c != 0x40000; c += 0x100 {
+				x, y, z := coords16(c)
+				values := [3]uint16{Inoise16(x), Inoise16XY(x, y), Inoise16XYZ(x, y, z)}
+				for d, v := range values {
+					if c != 0xfffc0000 && d >= axis && abs(int(v)-int(prev16[d])) > 0x800 {
+						t.Fatalf("%dD inoise16 jumps from %#x to %#x at %#x on axis %d", d+1, prev16[d], v, c, axis)
+					}
+				}
+				prev16 = values
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package ledstest contains helpers for the tests of the ledsgo packages.
+package ledstest
+
+import (
+	"github.com/aykevl/ledsgo"
+)
+
+// FrameRecorder is a Displayer that records copies of all displayed frames.
+type FrameRecorder []ledsgo.Strip
+
+// Display implements ledsgo.Displayer.
+func (r *FrameRecorder) Display(frame ledsgo.Strip) error {
+	*r = append(*r, append(ledsgo.Strip(nil), frame...))
+	return nil
+}
+
+// Last returns the last displayed frame, or nil if no frame has been
+// displayed yet.
+func (r FrameRecorder) Last() ledsgo.Strip {
+	if len(r) == 0 {
+		return nil
+	}
+	return r[len(r)-1]
+}
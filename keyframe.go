@@ -0,0 +1,110 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+// Keyframe is the state of a KeyframeAnimation at a point in time.
+type Keyframe struct {
+	// Time is the time of the keyframe since the start of the animation.
+	Time time.Duration
+
+	// Color is the color of the lit LEDs.
+	Color color.RGBA
+
+	// Brightness scales the color, where 255 is the color itself.
+	Brightness uint8
+
+	// Position is the start of the lit part of the strip, as a 24.8
+	// fixed-point LED index (see Strip.DrawDot).
+	Position int32
+
+	// Ease is the easing function used to go from the previous keyframe to
+	// this one, such as EaseInOutSine16. If it is nil, the animation is
+	// linear.
+	Ease func(x uint16) uint16
+}
+
+// KeyframeAnimation is an Effect that is described by keyframes instead of
+// code: the color, brightness and position are given at some points in time,
+// and interpolated between them. This makes it easy to define choreographed
+// sequences, such as an intro when the LEDs are switched on or an alert that
+// flashes a few times, as data (for example loaded from a configuration
+// file).
+type KeyframeAnimation struct {
+	// Keyframes are the keyframes of the animation, sorted by time.
+	Keyframes []Keyframe
+
+	// Size is the length of the lit part of the strip, as a 24.8 fixed-point
+	// number of LEDs. If it is zero, the whole strip is lit and the position
+	// is ignored. A negative size lights nothing.
+	Size int32
+
+	// Loop makes the animation start again after the last keyframe. If it is
+	// false, the last keyframe is kept.
+	Loop bool
+}
+
+// At returns the state of the animation at time t, interpolated between the
+// keyframes. The time of the result is t (wrapped if the animation loops),
+// and its Ease is nil. Before the first keyframe, the state is that of the
+// first keyframe.
+func (a *KeyframeAnimation) At(t time.Duration) Keyframe {
+	if len(a.Keyframes) == 0 {
+		return Keyframe{Time: t}
+	}
+	if end := a.Keyframes[len(a.Keyframes)-1].Time; a.Loop && end > 0 {
+		t %= end
+		if t < 0 {
+			t += end
+		}
+	}
+	if t <= a.Keyframes[0].Time {
+		k := a.Keyframes[0]
+		k.Time, k.Ease = t, nil
+		return k
+	}
+	for i := 1; i < len(a.Keyframes); i++ {
+		next := &a.Keyframes[i]
+		if t >= next.Time {
+			continue
+		}
+		prev := &a.Keyframes[i-1]
+		frac := durationFraction(t-prev.Time, next.Time-prev.Time) // .16; t < next.Time, so next.Time > prev.Time
+		if next.Ease != nil {
+			frac = next.Ease(frac)
+		}
+		return Keyframe{
+			Time:       t,
+			Color:      Blend(prev.Color, next.Color, uint8(frac>>8)),
+			Brightness: Lerp8by8(prev.Brightness, next.Brightness, uint8(frac>>8)),
+			Position:   prev.Position + int32(int64(next.Position-prev.Position)*int64(frac)>>16),
+		}
+	}
+	k := a.Keyframes[len(a.Keyframes)-1]
+	k.Time, k.Ease = t, nil
+	return k
+}
+
+// Render implements Effect.
+func (a *KeyframeAnimation) Render(frame Strip, t time.Duration) {
+	k := a.At(t)
+	c := scaleRGBA(k.Color, k.Brightness)
+	if a.Size == 0 {
+		frame.FillSolid(c)
+		return
+	}
+	frame.FillSolid(color.RGBA{})
+	if a.Size < 0 {
+		return
+	}
+
+	// Light every LED with the part of it that is covered by the lit part,
+	// so that it moves smoothly.
+	start, end := k.Position, k.Position+a.Size // .8
+	for i := max(int(start>>8), 0); i < len(frame) && int32(i)<<8 < end; i++ {
+		covered := min(end, int32(i+1)<<8) - max(start, int32(i)<<8) // .8
+		frame[i] = addWeighted(frame[i], c, uint16(covered))
+	}
+}
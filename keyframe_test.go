@@ -0,0 +1,90 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestKeyframeAnimation(t *testing.T) {
+	red, blue := color.RGBA{R: 255}, color.RGBA{B: 255}
+	a := &KeyframeAnimation{
+		Keyframes: []Keyframe{
+			{Time: time.Second, Color: red, Brightness: 255, Position: 0},
+			{Time: 3 * time.Second, Color: blue, Brightness: 55, Position: 10 << 8},
+			{Time: 5 * time.Second, Color: blue, Brightness: 255, Position: 0, Ease: EaseInQuad16},
+		},
+	}
+	for _, tc := range []struct {
+		t time.Duration
+		k Keyframe
+	}{
+		{0, Keyframe{Color: red, Brightness: 255}},
+		{2 * time.Second, Keyframe{Color: Blend(red, blue, 128), Brightness: 155, Position: 5 << 8}},
+		{3 * time.Second, Keyframe{Color: blue, Brightness: 55, Position: 10 << 8}},
+		{4 * time.Second, Keyframe{Color: blue, Brightness: 105, Position: 7<<8 + 128}}, // a quarter of the way
+		{10 * time.Second, Keyframe{Color: blue, Brightness: 255}},
+	} {
+		k := a.At(tc.t)
+		if k.Color != tc.k.Color || k.Brightness != tc.k.Brightness || k.Position != tc.k.Position || k.Ease != nil || k.Time != tc.t {
+			t.Errorf("At(%v): expected %+v, got %+v", tc.t, tc.k, k)
+		}
+	}
+
+	// A looping animation starts again after the last keyframe.
+	a.Loop = true
+	if k := a.At(7 * time.Second); k.Position != 5<<8 {
+		t.Errorf("loop: expected to be halfway through the first part, got %+v", k)
+	}
+
+	// The whole strip, or only the lit part, is drawn.
+	frame := make(Strip, 5)
+	a.Render(frame, 0)
+	for i, c := range frame {
+		if c != red {
+			t.Errorf("LED %d: expected %v, got %v", i, red, c)
+		}
+	}
+	a = &KeyframeAnimation{Keyframes: []Keyframe{{Color: red, Brightness: 255, Position: 1<<8 + 128}}, Size: 2 << 8}
+	a.Render(frame, 0)
+	expected := Strip{{}, {R: 127}, {R: 255}, {R: 127}, {}}
+	for i, c := range frame {
+		if c != expected[i] {
+			t.Errorf("LED %d: expected %v, got %v", i, expected[i], c)
+		}
+	}
+	a.Keyframes[0].Position = -1 << 8
+	a.Render(frame, 0)
+	if frame[0] != red || !isBlack(frame[1:]) {
+		t.Errorf("partly outside the strip: unexpected frame %v", frame)
+	}
+
+	// A negative size lights nothing.
+	a.Keyframes[0].Position = 2 << 8
+	a.Size = -1 << 8
+	a.Render(frame, 0)
+	if !isBlack(frame) {
+		t.Errorf("negative size: unexpected frame %v", frame)
+	}
+}
+
+func TestKeyframeAnimationLongGap(t *testing.T) {
+	// Keyframes that are weeks apart are interpolated without overflowing.
+	const day = 24 * time.Hour
+	a := &KeyframeAnimation{Keyframes: []Keyframe{
+		{Time: 0, Brightness: 0},
+		{Time: 28 * day, Brightness: 200},
+	}}
+	for _, tc := range []struct {
+		t          time.Duration
+		brightness uint8
+	}{
+		{7 * day, 50},
+		{14 * day, 100},
+		{21 * day, 150},
+	} {
+		if k := a.At(tc.t); abs(int(k.Brightness)-int(tc.brightness)) > 1 {
+			t.Errorf("At(%v): expected brightness %d, got %d", tc.t, tc.brightness, k.Brightness)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// Interpolation is the color space in which colors are blended, for example
+// in gradients and palette lookups.
+type Interpolation uint8
+
+const (
+	// InterpolateRGB blends the red, green and blue values directly, like
+	// Blend. This is the fastest, but blending two saturated colors of
+	// different hues (such as red and cyan) gives a washed-out, grayish
+	// color halfway.
+	InterpolateRGB Interpolation = iota
+
+	// InterpolateLCh blends lightness, chroma and hue separately, see
+	// BlendLCh. The colors halfway stay as saturated as the colors at the
+	// ends, and the hue takes the shortest way around the color wheel.
+	InterpolateLCh
+
+	// InterpolateLinear blends the red, green and blue values in linear
+	// light, see BlendLinear. This avoids the dark band halfway between two
+	// colors of different brightness.
+	InterpolateLinear
+)
+
+// Blend returns a mix of the colors a and b in this color space, where frac
+// is the fraction of the way from a to b: 0 returns a and 255 returns
+// (almost) b.
+func (m Interpolation) Blend(a, b color.RGBA, frac uint8) color.RGBA {
+	switch m {
+	case InterpolateLCh:
+		return BlendLCh(a, b, frac)
+	case InterpolateLinear:
+		return BlendLinear(a, b, frac)
+	default:
+		return Blend(a, b, frac)
+	}
+}
+
+// BlendLCh returns a mix of the colors a and b like Blend, but interpolated in
+// LCh: the polar form of the perceptual Oklab color space, with a lightness,
+// a chroma (colorfulness) and a hue. This avoids the washed-out colors halfway
+// between saturated colors of different hues that blending in RGB gives. The
+// colors are treated as sRGB colors, like the colors of the palettes and of
+// image files. The alpha channel is blended as in Blend.
+//
+// This is a lot slower than Blend. When blending with the same colors many
+// times, such as in a gradient, FillGradientLCh is faster.
+func BlendLCh(a, b color.RGBA, frac uint8) color.RGBA {
+	if frac == 0 {
+		return a
+	}
+	c := lerpLCh(toLCh(a), toLCh(b), uint16(frac)*0x101).rgba()
+	c.A = Lerp8by8(a.A, b.A, frac)
+	return c
+}
+
+// FillGradientLCh fills the strip with a gradient in LCh (see BlendLCh), where
+// the first LED gets the color from and the last LED gets the color to.
+func (s Strip) FillGradientLCh(from, to color.RGBA) {
+	if len(s) == 0 {
+		return
+	}
+	if len(s) == 1 {
+		s[0] = from
+		return
+	}
+	a, b := toLCh(from), toLCh(to)
+	n := uint32(len(s) - 1)
+	for i := range s {
+		s[i] = lerpLCh(a, b, uint16(uint32(i)*0xffff/n)).rgba()
+	}
+}
+
+// ColorAtLCh is like ColorAt, but colors between palette entries are
+// interpolated in LCh (see BlendLCh).
+func (p *Palette16) ColorAtLCh(index uint8) color.RGBA {
+	i := index >> 4
+	frac := index & 0x0f
+	if frac == 0 {
+		return p[i]
+	}
+	return BlendLCh(p[i], p[(i+1)%16], frac<<4)
+}
+
+// lch is a color in the LCh form of Oklab. See
+// https://bottosson.github.io/posts/oklab/ for the definition of Oklab.
+type lch struct {
+	L int32  // .16: lightness, 0..1
+	C int32  // .16: chroma, 0..about 0.33
+	H uint16 // hue, where 0x10000 is a full circle
+}
+
+// Colors with a chroma below this are considered gray. Their hue isn't
+// meaningful, so it is not interpolated.
+const lchGray = 0x80 // .16
+
+// Matrices used to convert between linear sRGB and Oklab, as .16 fixed-point
+// values. The rows are adjusted slightly so that white maps exactly to a
+// lightness of 1 and a chroma of 0.
+var (
+	oklabM1        = [3][3]int32{{27015, 35149, 3372}, {13887, 44611, 7038}, {5787, 18463, 41286}}
+	oklabM2        = [3][3]int32{{13792, 52011, -267}, {129630, -159160, 29530}, {1698, 51299, -52997}}
+	oklabM2Inverse = [3][3]int32{{65536, 25974, 14143}, {65536, -6918, -4185}, {65536, -5864, -84639}}
+	oklabM1Inverse = [3][3]int32{{267173, -216774, 15137}, {-83128, 171033, -22369}, {-275, -46099, 111910}}
+)
+
+// mul3 multiplies the .16 matrix m with the vector v.
+func mul3(m *[3][3]int32, v [3]int32) [3]int32 {
+	var result [3]int32
+	for i, row := range m {
+		result[i] = int32((int64(row[0])*int64(v[0]) + int64(row[1])*int64(v[1]) + int64(row[2])*int64(v[2]) + 0x8000) >> 16)
+	}
+	return result
+}
+
+// toLCh converts an sRGB color to LCh.
+func toLCh(c color.RGBA) lch {
+	lms := mul3(&oklabM1, [3]int32{int32(srgbToLinear[c.R]), int32(srgbToLinear[c.G]), int32(srgbToLinear[c.B])}) // .16
+	for i, v := range lms {
+		lms[i] = int32(cbrt64(uint64(max(v, 0)) << 32)) // .16
+	}
+	lab := mul3(&oklabM2, lms) // .16
+	a, b := int64(lab[1]), int64(lab[2])
+	return lch{
+		L: lab[0],
+		C: int32(Sqrt32(uint32(a*a + b*b))),
+		H: Atan2(lab[2], lab[1]),
+	}
+}
+
+// rgba converts the color back to sRGB. Colors outside of the sRGB gamut are
+// clipped.
+func (c lch) rgba() color.RGBA {
+	a := c.C * int32(Cos16(c.H)) >> 15 // .16 * .15 >> 15 = .16
+	b := c.C * int32(Sin16(c.H)) >> 15 // .16
+	lms := mul3(&oklabM2Inverse, [3]int32{c.L, a, b})
+	for i, v := range lms {
+		lms[i] = int32(int64(v) * int64(v) * int64(v) >> 32) // .16
+	}
+	rgb := mul3(&oklabM1Inverse, lms) // .16
+	return color.RGBA{linearToSRGB(rgb[0]), linearToSRGB(rgb[1]), linearToSRGB(rgb[2]), 0}
+}
+
+// lerpLCh interpolates between a and b, where frac is a .16 fraction. The hue
+// takes the shortest way around the color wheel.
+func lerpLCh(a, b lch, frac uint16) lch {
+	// A gray color takes the hue of the other color, so that for example a
+	// gradient from white to red only contains shades of red.
+	if a.C < lchGray {
+		a.H = b.H
+	} else if b.C < lchGray {
+		b.H = a.H
+	}
+	f := int64(frac)
+	return lch{
+		L: a.L + int32(int64(b.L-a.L)*f>>16),
+		C: a.C + int32(int64(b.C-a.C)*f>>16),
+		H: a.H + uint16(int32(int16(b.H-a.H))*int32(frac)>>16),
+	}
+}
+
+// cbrt64 returns the integer cube root of x, rounded down. It uses the
+// bit-by-bit method from Hacker's Delight, like Sqrt32.
+func cbrt64(x uint64) uint32 {
+	var y uint64
+	for s := 63; s >= 0; s -= 3 {
+		y <<= 1
+		b := 3*y*(y+1) + 1
+		if x>>s >= b {
+			x -= b << s
+			y++
+		}
+	}
+	return uint32(y)
+}
+
+// linearToSRGB converts a linear .16 value to the nearest sRGB value, clipping
+// it to the range 0..255.
+func linearToSRGB(v int32) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 0xffff {
+		return 255
+	}
+	// Binary search for the last entry that is not above v.
+	i := uint8(0)
+	for step := uint8(0x80); step != 0; step >>= 1 {
+		if int32(srgbToLinear[i+step]) <= v {
+			i += step
+		}
+	}
+	if i < 255 && int32(srgbToLinear[i+1])-v < v-int32(srgbToLinear[i]) {
+		i++
+	}
+	return i
+}
+
+// srgbToLinear converts sRGB values to linear light, as .16 fixed-point
+// values.
+var srgbToLinear = [256]uint16{
+	0, 20, 40, 60, 80, 99, 119, 139, 159, 179, 199, 219,
+	241, 264, 288, 313, 340, 367, 396, 427, 458, 491, 526, 562,
+	599, 637, 677, 718, 761, 805, 851, 898, 947, 997, 1048, 1101,
+	1156, 1212, 1270, 1330, 1391, 1453, 1517, 1583, 1651, 1720, 1790, 1863,
+	1937, 2013, 2090, 2170, 2250, 2333, 2418, 2504, 2592, 2681, 2773, 2866,
+	2961, 3058, 3157, 3258, 3360, 3464, 3570, 3678, 3788, 3900, 4014, 4129,
+	4247, 4366, 4488, 4611, 4736, 4864, 4993, 5124, 5257, 5392, 5530, 5669,
+	5810, 5953, 6099, 6246, 6395, 6547, 6700, 6856, 7014, 7174, 7335, 7500,
+	7666, 7834, 8004, 8177, 8352, 8528, 8708, 8889, 9072, 9258, 9445, 9635,
+	9828, 10022, 10219, 10417, 10619, 10822, 11028, 11235, 11446, 11658, 11873, 12090,
+	12309, 12530, 12754, 12980, 13209, 13440, 13673, 13909, 14146, 14387, 14629, 14874,
+	15122, 15371, 15623, 15878, 16135, 16394, 16656, 16920, 17187, 17456, 17727, 18001,
+	18277, 18556, 18837, 19121, 19407, 19696, 19987, 20281, 20577, 20876, 21177, 21481,
+	21787, 22096, 22407, 22721, 23038, 23357, 23678, 24002, 24329, 24658, 24990, 25325,
+	25662, 26001, 26344, 26688, 27036, 27386, 27739, 28094, 28452, 28813, 29176, 29542,
+	29911, 30282, 30656, 31033, 31412, 31794, 32179, 32567, 32957, 33350, 33745, 34143,
+	34544, 34948, 35355, 35764, 36176, 36591, 37008, 37429, 37852, 38278, 38706, 39138,
+	39572, 40009, 40449, 40891, 41337, 41785, 42236, 42690, 43147, 43606, 44069, 44534,
+	45002, 45473, 45947, 46423, 46903, 47385, 47871, 48359, 48850, 49344, 49841, 50341,
+	50844, 51349, 51858, 52369, 52884, 53401, 53921, 54445, 54971, 55500, 56032, 56567,
+	57105, 57646, 58190, 58737, 59287, 59840, 60396, 60955, 61517, 62082, 62650, 63221,
+	63795, 64372, 64952, 65535,
+}
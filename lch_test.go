@@ -0,0 +1,108 @@
+package ledsgo
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+// oklabFloat converts an sRGB color to Oklab, as a reference for toLCh.
+func oklabFloat(c color.RGBA) (L, a, b float64) {
+	lin := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.04045 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	r, g, bl := lin(c.R), lin(c.G), lin(c.B)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+func TestLCh(t *testing.T) {
+	for r := 0; r < 256; r += 15 {
+		for g := 0; g < 256; g += 15 {
+			for b := 0; b < 256; b += 15 {
+				c := color.RGBA{uint8(r), uint8(g), uint8(b), 0}
+				L, A, B := oklabFloat(c)
+				got := toLCh(c)
+				if diff := math.Abs(float64(got.L)/65536 - L); diff > 0.001 {
+					t.Errorf("%v: expected lightness %.4f, got %.4f", c, L, float64(got.L)/65536)
+				}
+				C := math.Hypot(A, B)
+				if diff := math.Abs(float64(got.C)/65536 - C); diff > 0.001 {
+					t.Errorf("%v: expected chroma %.4f, got %.4f", c, C, float64(got.C)/65536)
+				}
+				if C > 0.05 {
+					H := math.Atan2(B, A) / (2 * math.Pi) * 65536
+					if diff := math.Abs(float64(int16(got.H - uint16(int32(math.Round(H)))))); diff > 256 {
+						t.Errorf("%v: expected hue %.0f, got %d", c, H, got.H)
+					}
+				}
+
+				// Converting back must result in (almost) the same color.
+				back := got.rgba()
+				if abs(int(back.R)-r) > 1 || abs(int(back.G)-g) > 1 || abs(int(back.B)-b) > 1 {
+					t.Errorf("%v: round trip resulted in %v", c, back)
+				}
+			}
+		}
+	}
+	for _, c := range []color.RGBA{{}, {255, 255, 255, 0}, {128, 128, 128, 0}} {
+		if back := toLCh(c).rgba(); back != c {
+			t.Errorf("%v: round trip resulted in %v", c, back)
+		}
+	}
+}
+
+func TestBlendLCh(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 0}
+	cyan := color.RGBA{0, 255, 255, 0}
+	if c := BlendLCh(red, cyan, 0); c != red {
+		t.Errorf("frac 0: expected %v, got %v", red, c)
+	}
+	if c := BlendLCh(red, cyan, 255); abs(int(c.R)-0) > 8 || abs(int(c.G)-255) > 8 || abs(int(c.B)-255) > 8 {
+		t.Errorf("frac 255: expected about %v, got %v", cyan, c)
+	}
+
+	// Halfway between red and cyan, RGB gives gray while LCh keeps the colors
+	// saturated.
+	chroma := func(c color.RGBA) float64 {
+		_, a, b := oklabFloat(c)
+		return math.Hypot(a, b)
+	}
+	rgb, lch := InterpolateRGB.Blend(red, cyan, 128), InterpolateLCh.Blend(red, cyan, 128)
+	if chroma(rgb) > 0.01 || chroma(lch) < 0.1 {
+		t.Errorf("halfway: expected gray in RGB and a saturated color in LCh, got %v (chroma %.3f) and %v (chroma %.3f)", rgb, chroma(rgb), lch, chroma(lch))
+	}
+
+	// A gradient from white to red keeps the hue of red.
+	s := make(Strip, 16)
+	s.FillGradientLCh(color.RGBA{255, 255, 255, 0}, red)
+	if s[0] != (color.RGBA{255, 255, 255, 0}) || s[15] != red {
+		t.Errorf("gradient: unexpected ends %v and %v", s[0], s[15])
+	}
+	// (The hue of light colors with little chroma changes a lot with every
+	// step of the 8-bit channels, so these are skipped.)
+	for i, c := range s[6:] {
+		if diff := int16(toLCh(c).H - toLCh(red).H); diff > 400 || diff < -400 {
+			t.Errorf("gradient: LED %d has a different hue than red: %v", i+6, c)
+		}
+	}
+
+	p := NewPalette16(red, cyan)
+	if c := p.ColorAtLCh(0x18); c != BlendLCh(p[1], p[2], 0x80) {
+		t.Errorf("ColorAtLCh: got %v", c)
+	}
+}
+
+func BenchmarkBlendLCh(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		BlendLCh(color.RGBA{255, 0, uint8(i), 0}, color.RGBA{0, 255, 255, 0}, 100)
+	}
+}
@@ -20,10 +20,20 @@ type Color struct {
 // Return the RGB version of this color, calculated with the common HSV
 // conversion:
 // https://github.com/FastLED/FastLED/wiki/FastLED-HSV-Colors
+//
+// The conversion doesn't use divisions, which are slow (or not even available)
+// on many microcontrollers. The results are exactly the same as those of the
+// divisions in the comments.
 func (c Color) Spectrum() color.RGBA {
-	sectionWidth := uint32((1<<16)/3 + 1) // one third of the hue space
-	section := uint32(c.H) / sectionWidth
-	colorValue := (uint32(c.H) - section*sectionWidth) * 256 / sectionWidth
+	const sectionWidth = (1<<16)/3 + 1   // one third of the hue space
+	section := uint32(c.H) * 49151 >> 30 // c.H / sectionWidth
+	offset := uint32(c.H) - section*sectionWidth
+	// offset * 256 / sectionWidth: the reciprocal is rounded down, which makes
+	// the result at most one too low.
+	colorValue := offset * 767 >> 16
+	if (colorValue+1)*sectionWidth <= offset*256 {
+		colorValue++
+	}
 	var r, g, b uint32
 	switch section {
 	case 0:
@@ -36,9 +46,110 @@ func (c Color) Spectrum() color.RGBA {
 		b = 0xff - colorValue
 		r = colorValue
 	}
-	sat := (0xff - uint32(c.S)) * uint32(c.V) / 256 / 3
+	sat := (0xff - uint32(c.S)) * uint32(c.V) * 43691 >> 25 // (0xff - S) * V / 256 / 3
 	r = r*uint32(c.V)*(uint32(c.S))/(1<<16) + sat
 	g = g*uint32(c.V)*(uint32(c.S))/(1<<16) + sat
 	b = b*uint32(c.V)*(uint32(c.S))/(1<<16) + sat
 	return color.RGBA{uint8(r), uint8(g), uint8(b), 0}
 }
+
+// ColorFromRGBA returns the HSV color that results in the given RGB color when
+// converted with Spectrum. This can be used to derive variants of an arbitrary
+// color, for example one that was received from a remote control, by changing
+// the hue or saturation and converting it back.
+//
+// Spectrum keeps the total brightness of all hues the same, so it can't
+// produce very bright colors with a low saturation: pure white for example
+// results in 84 for every channel. Colors that are too bright are converted to
+// the same hue and saturation at the full value.
+func ColorFromRGBA(c color.RGBA) Color {
+	const sectionWidth = (1<<16)/3 + 1 // see Spectrum
+
+	// One channel is only the desaturated part, the other two are the
+	// saturated part of the hue plus the desaturated part.
+	r, g, b := uint32(c.R), uint32(c.G), uint32(c.B)
+	var section, m, p, q uint32 // p and q: the saturated parts of the channels
+	switch {
+	case b <= r && b <= g:
+		section, m, p, q = 0, b, r-b, g-b
+	case r <= g:
+		section, m, p, q = 1, r, g-r, b-r
+	default:
+		section, m, p, q = 2, g, b-g, r-g
+	}
+
+	// The saturated parts add up to 255*V*S/65536 and the desaturated part is
+	// (255-S)*V/768.
+	vs := (p + q) * 65536 / 255 // V * S
+	if m != 0 {
+		// Spectrum rounds the desaturated part down, so on average it was half
+		// a step higher.
+		m = m*2 + 1
+	}
+	v := (384*m + vs + 127) / 255 // (255-S)*V + V*S = 255*V
+	if v == 0 {
+		return Color{}
+	}
+	hsv := Color{
+		S: uint8(min((vs+v/2)/v, 255)),
+		V: uint8(min(v, 255)),
+	}
+	if p+q != 0 {
+		hsv.H = uint16(section*sectionWidth + (q*sectionWidth+(p+q)/2)/(p+q))
+	}
+	return hsv
+}
+
+// Color48 is a RGB color with 16 bits per channel. It is useful for rendering
+// with more precision than the LEDs support, for example for smooth fades at
+// low brightness using the global brightness of APA102 LEDs or dithering.
+type Color48 struct {
+	R, G, B uint16
+}
+
+// Color48FromRGBA converts a 8-bit color to a 16-bit color.
+func Color48FromRGBA(c color.RGBA) Color48 {
+	return Color48{uint16(c.R) * 0x101, uint16(c.G) * 0x101, uint16(c.B) * 0x101}
+}
+
+// RGBA implements the color.Color interface. The color is fully opaque.
+func (c Color48) RGBA() (r, g, b, a uint32) {
+	return uint32(c.R), uint32(c.G), uint32(c.B), 0xffff
+}
+
+// RGBA8 returns the color rounded to the nearest 8-bit color.
+func (c Color48) RGBA8() color.RGBA {
+	round := func(v uint16) uint8 {
+		return uint8((uint32(v) + 0x80) / 0x101)
+	}
+	return color.RGBA{round(c.R), round(c.G), round(c.B), 0}
+}
+
+// ColorRGBW is a RGB color with an extra white channel, for LEDs with a
+// separate white LED such as the SK6812 RGBW. Rendering the white channel
+// directly (instead of letting ColorOrder derive it from a RGB color) gives
+// full control over it, for example to mix warm white with a bit of color.
+type ColorRGBW struct {
+	R, G, B, W uint8
+}
+
+// RGBWFromRGBA converts a RGB color to a RGBW color. The white channel is the
+// common part of the red, green and blue channels, which is removed from these
+// channels. This is the same conversion as used by the RGBW color orders.
+func RGBWFromRGBA(c color.RGBA) ColorRGBW {
+	w := min(c.R, c.G, c.B)
+	return ColorRGBW{c.R - w, c.G - w, c.B - w, w}
+}
+
+// RGBA implements the color.Color interface. The white channel is added to the
+// red, green and blue channels, see RGBA8. The color is fully opaque.
+func (c ColorRGBW) RGBA() (r, g, b, a uint32) {
+	c8 := c.RGBA8()
+	return uint32(c8.R) * 0x101, uint32(c8.G) * 0x101, uint32(c8.B) * 0x101, 0xffff
+}
+
+// RGBA8 returns the color as a RGB color, with the white channel added to the
+// red, green and blue channels (saturating at 255).
+func (c ColorRGBW) RGBA8() color.RGBA {
+	return color.RGBA{QAdd8(c.R, c.W), QAdd8(c.G, c.W), QAdd8(c.B, c.W), 0}
+}
@@ -0,0 +1,97 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+// spectrumDiv is the original Spectrum implementation using divisions.
+func spectrumDiv(c Color) color.RGBA {
+	sectionWidth := uint32((1<<16)/3 + 1)
+	section := uint32(c.H) / sectionWidth
+	colorValue := (uint32(c.H) - section*sectionWidth) * 256 / sectionWidth
+	var r, g, b uint32
+	switch section {
+	case 0:
+		r = 0xff - colorValue
+		g = colorValue
+	case 1:
+		g = 0xff - colorValue
+		b = colorValue
+	case 2:
+		b = 0xff - colorValue
+		r = colorValue
+	}
+	sat := (0xff - uint32(c.S)) * uint32(c.V) / 256 / 3
+	r = r*uint32(c.V)*(uint32(c.S))/(1<<16) + sat
+	g = g*uint32(c.V)*(uint32(c.S))/(1<<16) + sat
+	b = b*uint32(c.V)*(uint32(c.S))/(1<<16) + sat
+	return color.RGBA{uint8(r), uint8(g), uint8(b), 0}
+}
+
+// TestSpectrum checks that the division-free HSV conversion results in
+// exactly the same colors as the conversion with divisions.
+func TestSpectrum(t *testing.T) {
+	check := func(c Color) {
+		if rgb, expected := c.Spectrum(), spectrumDiv(c); rgb != expected {
+			t.Fatalf("%+v: expected %v, got %v", c, expected, rgb)
+		}
+	}
+	for h := 0; h < 0x10000; h++ {
+		for _, sv := range []uint8{0, 1, 127, 128, 254, 255} {
+			check(Color{H: uint16(h), S: 255, V: sv})
+			check(Color{H: uint16(h), S: sv, V: 255})
+		}
+	}
+	for s := 0; s < 256; s++ {
+		for v := 0; v < 256; v++ {
+			for _, h := range []uint16{0, 0x1234, 0x5555, 0x5556, 0xaaab, 0xffff} {
+				check(Color{H: h, S: uint8(s), V: uint8(v)})
+			}
+		}
+	}
+}
+
+func TestColorFromRGBA(t *testing.T) {
+	for h := 0; h < 0x10000; h += 0x61 {
+		for _, sv := range [][2]uint8{{255, 255}, {255, 100}, {200, 255}, {128, 128}, {40, 220}, {0, 255}} {
+			c := Color{H: uint16(h), S: sv[0], V: sv[1]}
+			rgb := c.Spectrum()
+			hsv := ColorFromRGBA(rgb)
+			back := hsv.Spectrum()
+			if abs(int(back.R)-int(rgb.R)) > 1 || abs(int(back.G)-int(rgb.G)) > 1 || abs(int(back.B)-int(rgb.B)) > 1 {
+				t.Errorf("%v: round trip through %v resulted in %v, expected %v", c, hsv, back, rgb)
+			}
+			if abs(int(hsv.V)-int(c.V)) > 2 || abs(int(hsv.S)-int(c.S)) > 3 {
+				t.Errorf("%v: expected about the same saturation and value, got %v", c, hsv)
+			}
+			if c.S > 100 && c.V > 100 && abs(int(int16(hsv.H-c.H))) > 0x180 {
+				t.Errorf("%v: expected about the same hue, got %v", c, hsv)
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		c   color.RGBA
+		hsv Color
+	}{
+		{color.RGBA{}, Color{}},
+		{color.RGBA{255, 0, 0, 0}, Color{H: 0, S: 255, V: 255}},
+		{color.RGBA{0, 255, 0, 0}, Color{H: 0x5556, S: 255, V: 255}},
+		{color.RGBA{0, 0, 255, 0}, Color{H: 0xaaac, S: 255, V: 255}},
+		{color.RGBA{255, 255, 255, 0}, Color{H: 0, S: 0, V: 255}},      // too bright
+		{color.RGBA{255, 255, 0, 0}, Color{H: 0x2aab, S: 255, V: 255}}, // too bright
+	} {
+		if hsv := ColorFromRGBA(tc.c); hsv != tc.hsv {
+			t.Errorf("ColorFromRGBA(%v): expected %v, got %v", tc.c, tc.hsv, hsv)
+		}
+	}
+}
+
+func BenchmarkSpectrum(b *testing.B) {
+	var sum uint8
+	for i := 0; i < b.N; i++ {
+		sum += Color{H: uint16(i * 97), S: uint8(i), V: 200}.Spectrum().R
+	}
+	_ = sum
+}
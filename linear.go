@@ -0,0 +1,108 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// The functions in this file blend, scale and blur colors in linear light:
+// the colors are converted from sRGB to the amount of light they stand for,
+// changed, and converted back. The sRGB values of palettes and images are not
+// proportional to the light that is emitted, so blending two colors directly
+// (as Blend does) gives a mix that is too dark, which shows up as a dim seam
+// where two effects overlap or where a dot moves between two LEDs. Working in
+// linear light gives the brightness you'd get from actually mixing the light
+// of the two colors.
+
+// BlendLinear returns a mix of the colors a and b like Blend, but mixed in
+// linear light. A mix of black and white, for example, looks half as bright as
+// white instead of a lot darker. The alpha channel is blended as in Blend.
+func BlendLinear(a, b color.RGBA, frac uint8) color.RGBA {
+	if frac == 0 {
+		return a
+	}
+	frac16 := uint16(frac) * 0x101
+	return color.RGBA{
+		R: linearToSRGB(int32(Lerp16by16(srgbToLinear[a.R], srgbToLinear[b.R], frac16))),
+		G: linearToSRGB(int32(Lerp16by16(srgbToLinear[a.G], srgbToLinear[b.G], frac16))),
+		B: linearToSRGB(int32(Lerp16by16(srgbToLinear[a.B], srgbToLinear[b.B], frac16))),
+		A: Lerp8by8(a.A, b.A, frac),
+	}
+}
+
+// ScaleLinear scales the light output of the color by scale/256, like Scale8
+// does for the channels. A scale of 128 results in a color that emits half the
+// light, which is a lot brighter than Scale8 with the same scale. The alpha
+// channel is left unchanged.
+func ScaleLinear(c color.RGBA, scale uint8) color.RGBA {
+	k := uint32(scale) + 1
+	return color.RGBA{
+		R: linearToSRGB(int32(uint32(srgbToLinear[c.R]) * k >> 8)),
+		G: linearToSRGB(int32(uint32(srgbToLinear[c.G]) * k >> 8)),
+		B: linearToSRGB(int32(uint32(srgbToLinear[c.B]) * k >> 8)),
+		A: c.A,
+	}
+}
+
+// FadeAllLinear dims all LEDs by amount/256 like FadeAll, but in linear light
+// (see ScaleLinear).
+func (s Strip) FadeAllLinear(amount uint8) {
+	for i, c := range s {
+		s[i] = ScaleLinear(c, 255-amount)
+	}
+}
+
+// Blur blurs the strip, like blur1d in FastLED: every LED keeps
+// (255-amount)/256 of its color and spreads amount/512 to each neighbour.
+// Light that would spread beyond the ends of the strip is lost. Colors are
+// added saturating at 255.
+func (s Strip) Blur(amount uint8) {
+	s.blur(amount, false)
+}
+
+// BlurLinear blurs the strip like Blur, but in linear light. The total light
+// output stays the same, so a blurred dot doesn't look dimmer than it was.
+func (s Strip) BlurLinear(amount uint8) {
+	s.blur(amount, true)
+}
+
+// blur implements Blur and BlurLinear. The channels are .16 fixed-point values
+// in linear light, or 8-bit sRGB values shifted left by 8 bits.
+func (s Strip) blur(amount uint8, linear bool) {
+	if amount == 0 || len(s) == 0 {
+		return
+	}
+	keep := uint32(255-amount) + 1
+	seep := uint32(amount >> 1)
+	load := func(c color.RGBA) [3]uint32 {
+		if linear {
+			return [3]uint32{uint32(srgbToLinear[c.R]), uint32(srgbToLinear[c.G]), uint32(srgbToLinear[c.B])}
+		}
+		return [3]uint32{uint32(c.R) << 8, uint32(c.G) << 8, uint32(c.B) << 8}
+	}
+	store := func(v [3]uint32, alpha uint8) color.RGBA {
+		if linear {
+			return color.RGBA{linearToSRGB(int32(v[0])), linearToSRGB(int32(v[1])), linearToSRGB(int32(v[2])), alpha}
+		}
+		return color.RGBA{uint8(min(v[0]>>8, 255)), uint8(min(v[1]>>8, 255)), uint8(min(v[2]>>8, 255)), alpha}
+	}
+
+	// The previous LED is only stored once the part of the current LED that
+	// seeps into it is known.
+	var prev, carry [3]uint32
+	for i, c := range s {
+		cur := load(c)
+		var part [3]uint32
+		for ch := range cur {
+			part[ch] = cur[ch] * seep >> 8
+			cur[ch] = cur[ch]*keep>>8 + carry[ch]
+		}
+		if i > 0 {
+			for ch := range prev {
+				prev[ch] += part[ch]
+			}
+			s[i-1] = store(prev, s[i-1].A)
+		}
+		prev, carry = cur, part
+	}
+	s[len(s)-1] = store(prev, s[len(s)-1].A)
+}
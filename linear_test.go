@@ -0,0 +1,100 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+// lightOf returns the total linear light of the strip, as .16 fixed-point
+// values summed over all channels.
+func lightOf(s Strip) int {
+	total := 0
+	for _, c := range s {
+		total += int(srgbToLinear[c.R]) + int(srgbToLinear[c.G]) + int(srgbToLinear[c.B])
+	}
+	return total
+}
+
+func TestBlendLinear(t *testing.T) {
+	black := color.RGBA{}
+	white := color.RGBA{255, 255, 255, 0}
+	if c := BlendLinear(black, white, 0); c != black {
+		t.Errorf("frac 0: expected %v, got %v", black, c)
+	}
+	if c := BlendLinear(black, white, 255); c != white {
+		t.Errorf("frac 255: expected %v, got %v", white, c)
+	}
+
+	// Half of the light of white is 188 in sRGB, while RGB blending gives 128.
+	if c := InterpolateLinear.Blend(black, white, 128); c != (color.RGBA{188, 188, 188, 0}) {
+		t.Errorf("halfway: expected 188, got %v", c)
+	}
+	if c := BlendLinear(color.RGBA{255, 0, 0, 0}, color.RGBA{0, 255, 0, 0}, 128); abs(int(c.R)-188) > 1 || abs(int(c.G)-188) > 1 || c.B != 0 {
+		t.Errorf("red to green: expected no dark yellow halfway, got %v", c)
+	}
+
+	if c := ScaleLinear(white, 127); c != (color.RGBA{188, 188, 188, 0}) {
+		t.Errorf("ScaleLinear: expected 188, got %v", c)
+	}
+	if c := ScaleLinear(color.RGBA{10, 100, 200, 7}, 255); c != (color.RGBA{10, 100, 200, 7}) {
+		t.Errorf("ScaleLinear: expected full scale to keep the color, got %v", c)
+	}
+	s := Strip{white, {100, 50, 0, 0}}
+	s.FadeAllLinear(128)
+	if s[0] != (color.RGBA{188, 188, 188, 0}) || s[1] != ScaleLinear(color.RGBA{100, 50, 0, 0}, 127) {
+		t.Errorf("FadeAllLinear: got %v", s)
+	}
+}
+
+func TestBlur(t *testing.T) {
+	dot := func() Strip {
+		s := make(Strip, 9)
+		s[4] = color.RGBA{255, 128, 0, 0}
+		return s
+	}
+
+	s := dot()
+	s.Blur(0)
+	if s[4] != (color.RGBA{255, 128, 0, 0}) || !isBlack(s[:4]) || !isBlack(s[5:]) {
+		t.Errorf("Blur(0) changed the strip: %v", s)
+	}
+
+	// Blurring in sRGB loses a lot of light, blurring in linear light only
+	// loses what is rounded away.
+	s = dot()
+	s.Blur(128)
+	if s[4] != (color.RGBA{127, 64, 0, 0}) || s[3] != (color.RGBA{63, 32, 0, 0}) || s[3] != s[5] {
+		t.Errorf("Blur(128): unexpected result %v", s)
+	}
+	want := lightOf(dot())
+	if light := lightOf(s); light > want*3/4 {
+		t.Errorf("Blur(128): expected light to be lost, got %d of %d", light, want)
+	}
+	s = dot()
+	s.BlurLinear(128)
+	if s[3] != s[5] || s[4].R <= s[3].R || !isBlack(s[:3]) || !isBlack(s[6:]) {
+		t.Errorf("BlurLinear(128): unexpected result %v", s)
+	}
+	if light := lightOf(s); light < want*98/100 || light > want*102/100 {
+		t.Errorf("BlurLinear(128): expected the same light, got %d of %d", light, want)
+	}
+
+	// Repeated blurring spreads the dot further, without ever going past the
+	// ends of the strip.
+	s = dot()
+	for i := 0; i < 10; i++ {
+		s.BlurLinear(255)
+	}
+	if s[0] == (color.RGBA{}) || s[8] != s[0] {
+		t.Errorf("BlurLinear(255) x10: unexpected result %v", s)
+	}
+	Strip{}.Blur(100)
+}
+
+func BenchmarkBlurLinear(b *testing.B) {
+	s := make(Strip, 300)
+	s.FillRainbow(0, 200)
+	for i := 0; i < b.N; i++ {
+		s.BlurLinear(64)
+	}
+}
@@ -0,0 +1,83 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// LUT is a lookup table for every channel, which maps the value of the
+// channel in a frame to the value that is sent to the LEDs. Lookup tables can
+// correct for anything that affects the channels separately: measured
+// differences between batches of LEDs, a colored diffuser that absorbs more
+// of one color than the others, or a custom gamma curve.
+type LUT struct {
+	Red, Green, Blue [256]uint8
+}
+
+// IdentityLUT returns a lookup table that doesn't change any values. It's a
+// good starting point for a table that only changes a few values.
+func IdentityLUT() LUT {
+	var l LUT
+	for i := range l.Red {
+		l.Red[i] = uint8(i)
+	}
+	l.Green = l.Red
+	l.Blue = l.Red
+	return l
+}
+
+// Apply returns the color with every channel looked up in the table. The alpha
+// channel is left unchanged.
+func (l *LUT) Apply(c color.RGBA) color.RGBA {
+	return color.RGBA{l.Red[c.R], l.Green[c.G], l.Blue[c.B], c.A}
+}
+
+// LUTSegment assigns a lookup table to a range of LEDs in a frame.
+type LUTSegment struct {
+	Start int // index of the first LED
+	Count int // number of LEDs
+	LUT   LUT
+}
+
+// LUTCorrector is a Displayer that applies lookup tables to every frame before
+// it is sent to the underlying displayer. LEDs that are not part of any
+// segment are sent unchanged. See ResponseCorrector for a version that takes a
+// few measured points instead of a full table.
+type LUTCorrector struct {
+	displayer Displayer
+	segments  []LUTSegment
+	buf       Strip
+}
+
+// NewLUTCorrector returns a new LUTCorrector that sends frames to the given
+// displayer. The segments are copied, so changing them afterwards has no
+// effect.
+func NewLUTCorrector(displayer Displayer, segments ...LUTSegment) *LUTCorrector {
+	return &LUTCorrector{
+		displayer: displayer,
+		segments:  append([]LUTSegment(nil), segments...),
+	}
+}
+
+// Display sends the corrected frame to the underlying displayer.
+func (c *LUTCorrector) Display(frame Strip) error {
+	return c.displayer.Display(applyLUTs(&c.buf, frame, c.segments))
+}
+
+// applyLUTs copies the frame into buf (resizing it as needed), applies the
+// lookup tables of the segments to it and returns it.
+func applyLUTs(buf *Strip, frame Strip, segments []LUTSegment) Strip {
+	if len(*buf) != len(frame) {
+		*buf = make(Strip, len(frame))
+	}
+	out := *buf
+	copy(out, frame)
+	for i := range segments {
+		seg := &segments[i]
+		start := min(max(seg.Start, 0), len(frame))
+		end := min(max(seg.Start+seg.Count, start), len(frame))
+		for j, col := range out[start:end] {
+			out[start+j] = seg.LUT.Apply(col)
+		}
+	}
+	return out
+}
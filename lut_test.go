@@ -0,0 +1,56 @@
+package ledsgo
+
+import (
+	"testing"
+)
+
+func TestLUTCorrector(t *testing.T) {
+	if IdentityLUT() != LinearResponse.LUT() {
+		t.Error("the identity table is not the same as the linear response")
+	}
+
+	// A diffuser that absorbs some of the blue light.
+	diffuser := IdentityLUT()
+	for i := range diffuser.Red {
+		diffuser.Red[i] = uint8(i * 3 / 4)
+		diffuser.Green[i] = uint8(i * 3 / 4)
+	}
+	rec := &frameRecorder{}
+	c := NewLUTCorrector(rec, LUTSegment{Start: 1, Count: 2, LUT: diffuser})
+	diffuser.Blue[0] = 99 // must not affect the corrector
+	frame := Strip{{100, 100, 100, 0}, {100, 200, 255, 0xff}, {0, 0, 4, 0}, {8, 8, 8, 0}}
+	if err := c.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	expected := Strip{{100, 100, 100, 0}, {75, 150, 255, 0xff}, {0, 0, 4, 0}, {8, 8, 8, 0}}
+	for i, col := range rec.frames[0] {
+		if col != expected[i] {
+			t.Errorf("LED %d: expected %v, got %v", i, expected[i], col)
+		}
+	}
+	if frame[1].R != 100 {
+		t.Error("the original frame was modified")
+	}
+
+	// Segments are clipped to the frame.
+	rec = &frameRecorder{}
+	c = NewLUTCorrector(rec, LUTSegment{Start: -1, Count: 2, LUT: diffuser}, LUTSegment{Start: 3, Count: -2, LUT: diffuser})
+	if err := c.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	expected = Strip{{75, 75, 100, 0}, {100, 200, 255, 0xff}, {0, 0, 4, 0}, {8, 8, 8, 0}}
+	for i, col := range rec.frames[0] {
+		if col != expected[i] {
+			t.Errorf("clipped segments, LED %d: expected %v, got %v", i, expected[i], col)
+		}
+	}
+
+	// Segments past the end of the frame are ignored.
+	c = NewLUTCorrector(discardDisplayer{}, LUTSegment{Start: 3, Count: 10, LUT: diffuser})
+	if err := c.Display(frame[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { c.Display(frame[:2]) }); allocs != 0 {
+		t.Errorf("expected no allocations, got %.0f", allocs)
+	}
+}
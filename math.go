@@ -0,0 +1,155 @@
+package ledsgo
+
+import (
+	"math/bits"
+)
+
+// Lerp8by8 interpolates between a and b, where frac is the fraction of the way
+// from a to b: 0 returns a and 255 returns a value very close to b. The result
+// is rounded to the nearest integer.
+func Lerp8by8(a, b, frac uint8) uint8 {
+	if b > a {
+		delta := uint16(b - a)
+		return a + uint8((delta*uint16(frac)+0x80)>>8)
+	}
+	delta := uint16(a - b)
+	return a - uint8((delta*uint16(frac)+0x80)>>8)
+}
+
+// Lerp16by16 interpolates between a and b, where frac is the fraction of the
+// way from a to b: 0 returns a and 65535 returns a value very close to b. The
+// result is rounded to the nearest integer.
+func Lerp16by16(a, b, frac uint16) uint16 {
+	if b > a {
+		delta := uint32(b - a)
+		return a + uint16((delta*uint32(frac)+0x8000)>>16)
+	}
+	delta := uint32(a - b)
+	return a - uint16((delta*uint32(frac)+0x8000)>>16)
+}
+
+// Map maps x from the input range to the output range, like the Arduino map()
+// function. Unlike that function, the result is rounded to the nearest integer
+// instead of truncated, so that the output range is covered evenly. The value
+// x is not constrained to the input range. If the input range is empty, outMin
+// is returned.
+func Map(x, inMin, inMax, outMin, outMax int32) int32 {
+	// The differences take up to 33 bits, so their product doesn't fit in an
+	// int64. Calculate with the magnitudes as uint64 instead, which fit the
+	// product of two 32-bit magnitudes.
+	num := int64(x) - int64(inMin)
+	scale := int64(outMax) - int64(outMin)
+	den := int64(inMax) - int64(inMin)
+	if den == 0 {
+		return outMin
+	}
+	negative := (num < 0) != (scale < 0) != (den < 0)
+	product := uint64(abs64(num)) * uint64(abs64(scale))
+	q := product / uint64(abs64(den))
+	if r := product % uint64(abs64(den)); r*2 >= uint64(abs64(den)) {
+		q++ // round half away from zero
+	}
+	if negative {
+		return int32(int64(outMin) - int64(q))
+	}
+	return int32(int64(outMin) + int64(q))
+}
+
+// abs64 returns the absolute value of x.
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Sqrt16 returns the integer square root of x, rounded down.
+func Sqrt16(x uint16) uint8 {
+	return uint8(Sqrt32(uint32(x)))
+}
+
+// Sqrt32 returns the integer square root of x, rounded down. It uses the
+// classic bit-by-bit method, which only needs shifts, additions and
+// subtractions.
+func Sqrt32(x uint32) uint16 {
+	if x == 0 {
+		return 0
+	}
+	var result uint32
+	// Start at the highest power of four that is not above x. bits.Len32 is
+	// a single clz instruction on most architectures.
+	bit := uint32(1) << ((bits.Len32(x) - 1) &^ 1)
+	for bit != 0 {
+		if x >= result+bit {
+			x -= result + bit
+			result = result>>1 + bit
+		} else {
+			result >>= 1
+		}
+		bit >>= 2
+	}
+	return uint16(result)
+}
+
+// QAdd8 adds a and b, saturating at 255 instead of wrapping around.
+func QAdd8(a, b uint8) uint8 {
+	if n := uint16(a) + uint16(b); n < 0xff {
+		return uint8(n)
+	}
+	return 0xff
+}
+
+// QSub8 subtracts b from a, saturating at 0 instead of wrapping around.
+func QSub8(a, b uint8) uint8 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
+
+// QMul8 multiplies a and b, saturating at 255 instead of wrapping around.
+func QMul8(a, b uint8) uint8 {
+	if n := uint16(a) * uint16(b); n < 0xff {
+		return uint8(n)
+	}
+	return 0xff
+}
+
+// Avg8 returns the average of a and b, rounded down.
+func Avg8(a, b uint8) uint8 {
+	return uint8((uint16(a) + uint16(b)) >> 1)
+}
+
+// Avg8r returns the average of a and b, rounded up.
+func Avg8r(a, b uint8) uint8 {
+	return uint8((uint16(a) + uint16(b) + 1) >> 1)
+}
+
+// Avg16 returns the average of a and b, rounded down.
+func Avg16(a, b uint16) uint16 {
+	return uint16((uint32(a) + uint32(b)) >> 1)
+}
+
+// Scale8 scales i by scale/256, where a scale of 255 returns i unchanged and a
+// scale of 0 returns 0.
+func Scale8(i, scale uint8) uint8 {
+	return uint8(uint16(i) * (uint16(scale) + 1) >> 8)
+}
+
+// Scale8Video is like scale8_video in FastLED: it scales i by scale/256 and
+// adds one if both i and scale are non-zero, so that the result is never zero
+// in that case. This is useful when dimming LEDs, to avoid turning them off
+// entirely.
+func Scale8Video(i, scale uint8) uint8 {
+	n := uint8(uint16(i) * uint16(scale) >> 8)
+	if i != 0 && scale != 0 {
+		n++
+	}
+	return n
+}
+
+// Scale16 scales i by scale/65536, where a scale of 65535 returns i unchanged
+// and a scale of 0 returns 0.
+func Scale16(i, scale uint16) uint16 {
+	return uint16(uint32(i) * (uint32(scale) + 1) >> 16)
+}
@@ -0,0 +1,156 @@
+package ledsgo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLerp(t *testing.T) {
+	for _, tc := range []struct {
+		a, b, frac, result uint8
+	}{
+		{0, 255, 0, 0},
+		{0, 255, 128, 128},
+		{0, 255, 255, 254},
+		{255, 0, 128, 127},
+		{10, 20, 128, 15},
+		{20, 10, 128, 15},
+	} {
+		if result := Lerp8by8(tc.a, tc.b, tc.frac); result != tc.result {
+			t.Errorf("Lerp8by8(%d, %d, %d): expected %d, got %d", tc.a, tc.b, tc.frac, tc.result, result)
+		}
+	}
+	for _, tc := range []struct {
+		a, b, frac, result uint16
+	}{
+		{0, 0xffff, 0, 0},
+		{0, 0xffff, 0x8000, 0x8000},
+		{0, 0xffff, 0xffff, 0xfffe},
+		{0xffff, 0, 0x8000, 0x7fff},
+		{1000, 2000, 0x4000, 1250},
+	} {
+		if result := Lerp16by16(tc.a, tc.b, tc.frac); result != tc.result {
+			t.Errorf("Lerp16by16(%d, %d, %d): expected %d, got %d", tc.a, tc.b, tc.frac, tc.result, result)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	for _, tc := range []struct {
+		x, inMin, inMax, outMin, outMax, result int32
+	}{
+		{0, 0, 10, 0, 100, 0},
+		{5, 0, 10, 0, 100, 50},
+		{10, 0, 10, 0, 100, 100},
+		{1, 0, 3, 0, 10, 3},
+		{2, 0, 3, 0, 10, 7},
+		{2, 0, 3, 10, 0, 3},
+		{-5, -10, 0, 0, 255, 128},
+		{15, 0, 10, 0, 100, 150},
+		{5, 5, 5, 1, 2, 1},
+
+		// The differences don't fit in an int32.
+		{math.MaxInt32, math.MinInt32, math.MaxInt32, 0, 255, 255},
+		{0, math.MinInt32, math.MaxInt32, 0, 255, 128},
+		{math.MinInt32, math.MinInt32, math.MaxInt32, 0, 255, 0},
+		{100, 0, 200, math.MinInt32, math.MaxInt32, 0},
+		{200, 0, 200, math.MaxInt32, math.MinInt32, math.MinInt32},
+		{0, math.MinInt32, math.MaxInt32, math.MinInt32, math.MaxInt32, 0},
+		{math.MaxInt32, math.MinInt32, math.MaxInt32, math.MinInt32, math.MaxInt32, math.MaxInt32},
+		{-1, math.MaxInt32, math.MinInt32, 0, 1000, 500},
+	} {
+		if result := Map(tc.x, tc.inMin, tc.inMax, tc.outMin, tc.outMax); result != tc.result {
+			t.Errorf("Map(%d, %d, %d, %d, %d): expected %d, got %d", tc.x, tc.inMin, tc.inMax, tc.outMin, tc.outMax, tc.result, result)
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	for x := 0; x < 0x10000; x++ {
+		r := int(Sqrt16(uint16(x)))
+		if r*r > x || (r+1)*(r+1) <= x {
+			t.Fatalf("Sqrt16(%d): got %d", x, r)
+		}
+	}
+	for _, x := range []uint32{0x10000, 0x12345678, 0xfffe0001, 0xfffe0000, 0xffffffff} {
+		r := uint64(Sqrt32(x))
+		if r*r > uint64(x) || (r+1)*(r+1) <= uint64(x) {
+			t.Errorf("Sqrt32(%d): got %d", x, r)
+		}
+	}
+}
+
+func TestFixed(t *testing.T) {
+	for _, tc := range []struct {
+		value    interface{ String() string }
+		expected string
+	}{
+		{Accum88FromInt(3) + 0x80, "3.5"},
+		{Accum88(0x180).Mul(0x200), "3"},
+		{Accum88(0x300).Div(0x200), "1.5"},
+		{Q88(-0x40), "-0.25"},
+		{Q88FromInt(-2).Mul(0x180), "-3"},
+		{Q1212FromInt(5).Div(Q1212FromInt(4)), "1.25"},
+		{Q1212(1), "0.000244140625"},
+	} {
+		if s := tc.value.String(); s != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, s)
+		}
+	}
+	if q := Q88(-0x40); q.Int() != -1 || q.Frac() != 0xc0 {
+		t.Errorf("Q88(-0.25): unexpected Int() %d and Frac() %d", q.Int(), q.Frac())
+	}
+}
+
+func TestSaturating(t *testing.T) {
+	for a := 0; a < 256; a++ {
+		for b := 0; b < 256; b++ {
+			if n := int(QAdd8(uint8(a), uint8(b))); n != min(a+b, 255) {
+				t.Fatalf("QAdd8(%d, %d): got %d", a, b, n)
+			}
+			if n := int(QSub8(uint8(a), uint8(b))); n != max(a-b, 0) {
+				t.Fatalf("QSub8(%d, %d): got %d", a, b, n)
+			}
+			if n := int(QMul8(uint8(a), uint8(b))); n != min(a*b, 255) {
+				t.Fatalf("QMul8(%d, %d): got %d", a, b, n)
+			}
+			if n := int(Avg8(uint8(a), uint8(b))); n != (a+b)/2 {
+				t.Fatalf("Avg8(%d, %d): got %d", a, b, n)
+			}
+		}
+	}
+}
+
+func TestScale8Video(t *testing.T) {
+	// Reference values from scale8_video in FastLED.
+	for _, tc := range []struct {
+		i, scale, result uint8
+	}{
+		{0, 0, 0},
+		{0, 255, 0},
+		{255, 0, 0},
+		{1, 1, 1},
+		{10, 1, 1},
+		{100, 50, 20},
+		{128, 128, 65},
+		{200, 100, 79},
+		{255, 128, 128},
+		{255, 254, 254},
+		{255, 255, 255},
+	} {
+		if result := Scale8Video(tc.i, tc.scale); result != tc.result {
+			t.Errorf("Scale8Video(%d, %d): expected %d, got %d", tc.i, tc.scale, tc.result, result)
+		}
+	}
+	for i := 0; i < 256; i++ {
+		for scale := 0; scale < 256; scale++ {
+			expected := i * scale >> 8
+			if i != 0 && scale != 0 {
+				expected++
+			}
+			if result := Scale8Video(uint8(i), uint8(scale)); int(result) != expected {
+				t.Fatalf("Scale8Video(%d, %d): expected %d, got %d", i, scale, expected, result)
+			}
+		}
+	}
+}
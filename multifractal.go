@@ -0,0 +1,91 @@
+package ledsgo
+
+// HybridMultifractal combines multiple octaves of simplex noise into a
+// terrain-like pattern, as described by F. Kenton Musgrave. Every octave has
+// twice the frequency of the previous one, like in fractal Brownian motion
+// (fBm). Unlike fBm, the weight of every octave depends on the value of the
+// previous octaves: low areas stay smooth while high areas get a lot of
+// detail. This looks much richer than plain noise for fire and water effects.
+type HybridMultifractal struct {
+	// Octaves is the number of noise layers.
+	Octaves uint8
+
+	// Gain is the amplitude of every octave relative to the previous one, as a
+	// .16 fixed-point value. Lower values give smoother results.
+	Gain uint16
+
+	// Offset is added to the noise value of every octave, as a .15
+	// fixed-point value. Higher values give more detail in more places.
+	Offset int16
+}
+
+// NewHybridMultifractal returns a HybridMultifractal with the default settings:
+// 5 octaves, a gain of 0.5 and an offset of 0.7.
+func NewHybridMultifractal() *HybridMultifractal {
+	return &HybridMultifractal{
+		Octaves: 5,
+		Gain:    0x8000, // 0.5
+		Offset:  0x5a00, // 0.7
+	}
+}
+
+// Offset added to the coordinates of every next octave, so that all octaves
+// don't have a lattice point at the origin.
+const multifractalShift = 0x3a5c7
+
+// Noise2 returns the multifractal of 2D simplex noise at (x, y). The inputs are
+// 19.12 fixed-point values like those of Noise2, and the result is scaled to
+// the full range of an uint16.
+func (m *HybridMultifractal) Noise2(x, y int32) uint16 {
+	s := newMultifractalState()
+	for i := uint8(0); i < m.Octaves; i++ {
+		s.add(m, Noise2(x, y))
+		x = x<<1 + multifractalShift
+		y = y<<1 + multifractalShift
+	}
+	return s.value()
+}
+
+// Noise3 returns the multifractal of 3D simplex noise at (x, y, z), see Noise2.
+// Only x and y get a higher frequency for every octave, so that the z axis can
+// be used for time.
+func (m *HybridMultifractal) Noise3(x, y, z int32) uint16 {
+	s := newMultifractalState()
+	for i := uint8(0); i < m.Octaves; i++ {
+		s.add(m, Noise3(x, y, z))
+		x = x<<1 + multifractalShift
+		y = y<<1 + multifractalShift
+		z += multifractalShift
+	}
+	return s.value()
+}
+
+// multifractalState is the state of a HybridMultifractal while adding octaves.
+type multifractalState struct {
+	result    int32 // .15
+	weight    int32 // .15: weight of the next octave
+	amplitude int32 // .16: amplitude of the next octave
+	max       int32 // .15: highest possible result
+}
+
+// newMultifractalState returns the state before adding the first octave.
+func newMultifractalState() multifractalState {
+	return multifractalState{weight: 1 << 15, amplitude: 1 << 16}
+}
+
+// add adds the next octave with noise value n.
+func (s *multifractalState) add(m *HybridMultifractal, n int16) {
+	signal := int32((int64(n) + int64(m.Offset)) * int64(s.amplitude) >> 16) // .15 * .16 >> 16 = .15
+	s.result += s.weight * signal >> 15                                      // .15 * .15 >> 15 = .15
+	s.max += int32((0x8000 + int64(m.Offset)) * int64(s.amplitude) >> 16)    // .15
+	s.weight = min(max(s.weight*signal>>15, 0), 1<<15)                       // .15
+	s.amplitude = int32(uint32(s.amplitude) * uint32(m.Gain) >> 16)          // .16
+}
+
+// value returns the result scaled to the range of an uint16.
+func (s *multifractalState) value() uint16 {
+	if s.max <= 0 || s.result <= 0 {
+		return 0
+	}
+	return uint16(int64(min(s.result, s.max)) * 0xffff / int64(s.max))
+}
@@ -0,0 +1,71 @@
+package ledsgo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHybridMultifractal(t *testing.T) {
+	// Floating point reference of the same algorithm, using the fixed-point
+	// noise so that only the combining is compared.
+	reference := func(m *HybridMultifractal, noise func(i int) int16) float64 {
+		gain := float64(m.Gain) / 65536
+		offset := float64(m.Offset) / 32768
+		result, weight, amplitude, maxResult := 0.0, 1.0, 1.0, 0.0
+		for i := 0; i < int(m.Octaves); i++ {
+			signal := (float64(noise(i))/32768 + offset) * amplitude
+			result += weight * signal
+			maxResult += (1 + offset) * amplitude
+			weight = math.Max(math.Min(weight*signal, 1), 0)
+			amplitude *= gain
+		}
+		return math.Max(math.Min(result/maxResult, 1), 0)
+	}
+
+	for _, m := range []*HybridMultifractal{
+		NewHybridMultifractal(),
+		{Octaves: 1, Gain: 0x8000, Offset: 0},
+		{Octaves: 8, Gain: 0xb000, Offset: 0x7fff},
+		{Octaves: 3, Gain: 0x4000, Offset: -0x2000},
+	} {
+		var lowest, highest uint16 = 0xffff, 0
+		for y := int32(-0x8000); y < 0x8000; y += 0x333 {
+			for x := int32(-0x8000); x < 0x8000; x += 0x1c7 {
+				v := m.Noise2(x, y)
+				lowest, highest = min(lowest, v), max(highest, v)
+				expected := reference(m, func(i int) int16 {
+					ox, oy := x, y
+					for j := 0; j < i; j++ {
+						ox, oy = ox<<1+multifractalShift, oy<<1+multifractalShift
+					}
+					return Noise2(ox, oy)
+				})
+				if diff := math.Abs(float64(v)/0xffff - expected); diff > 0.002 {
+					t.Errorf("%+v: Noise2(%d, %d) = %#x, expected %.4f", m, x, y, v, expected*0xffff)
+				}
+
+				v = m.Noise3(x, y, 0x4321)
+				expected = reference(m, func(i int) int16 {
+					ox, oy, oz := x, y, int32(0x4321)
+					for j := 0; j < i; j++ {
+						ox, oy, oz = ox<<1+multifractalShift, oy<<1+multifractalShift, oz+multifractalShift
+					}
+					return Noise3(ox, oy, oz)
+				})
+				if diff := math.Abs(float64(v)/0xffff - expected); diff > 0.002 {
+					t.Errorf("%+v: Noise3(%d, %d, 0x4321) = %#x, expected %.4f", m, x, y, v, expected*0xffff)
+				}
+			}
+		}
+		if m.Offset >= 0 && (lowest > 0x4000 || highest < 0x8000) {
+			t.Errorf("%+v: expected a wide range of values, got %#x..%#x", m, lowest, highest)
+		}
+	}
+}
+
+func BenchmarkHybridMultifractal(b *testing.B) {
+	m := NewHybridMultifractal()
+	for i := 0; i < b.N; i++ {
+		m.Noise2(int32(i)*0x35, 0x1234)
+	}
+}
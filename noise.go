@@ -6,7 +6,11 @@ package ledsgo
 // all inputs have been tested in Noise2 and up, so there might be inputs that
 // overflow int16. For Noise2, exhaustive testing might be possible but
 // computationally expensive (2**64 combinations). For Noise3 and up it is
-// impossible to check all inputs (2**96 inputs).
+// impossible to check all inputs (2**96 inputs). The fuzz tests FuzzNoise2 and
+// FuzzNoise3 search for such inputs, and for inputs where the result differs
+// too much from the floating point version:
+//
+//     go test -run=NONE -fuzz=FuzzNoise2
 //
 // Warning: there are patents on simplex noise for certain uses, which probably
 // doesn't include LED animations (no guarantee).
@@ -31,38 +35,12 @@ package ledsgo
 //
 //     nf := float64(n) / (1 << 12)
 
-// Permutation table. This is just a random jumble of all numbers.
-// This needs to be exactly the same for all instances on all platforms,
-// so it's easiest to just keep it as static explicit data.
-var perm = [256]uint8{
-	151, 160, 137, 91, 90, 15,
-	131, 13, 201, 95, 96, 53, 194, 233, 7, 225, 140, 36, 103, 30, 69, 142, 8, 99, 37, 240, 21, 10, 23,
-	190, 6, 148, 247, 120, 234, 75, 0, 26, 197, 62, 94, 252, 219, 203, 117, 35, 11, 32, 57, 177, 33,
-	88, 237, 149, 56, 87, 174, 20, 125, 136, 171, 168, 68, 175, 74, 165, 71, 134, 139, 48, 27, 166,
-	77, 146, 158, 231, 83, 111, 229, 122, 60, 211, 133, 230, 220, 105, 92, 41, 55, 46, 245, 40, 244,
-	102, 143, 54, 65, 25, 63, 161, 1, 216, 80, 73, 209, 76, 132, 187, 208, 89, 18, 169, 200, 196,
-	135, 130, 116, 188, 159, 86, 164, 100, 109, 198, 173, 186, 3, 64, 52, 217, 226, 250, 124, 123,
-	5, 202, 38, 147, 118, 126, 255, 82, 85, 212, 207, 206, 59, 227, 47, 16, 58, 17, 182, 189, 28, 42,
-	223, 183, 170, 213, 119, 248, 152, 2, 44, 154, 163, 70, 221, 153, 101, 155, 167, 43, 172, 9,
-	129, 22, 39, 253, 19, 98, 108, 110, 79, 113, 224, 232, 178, 185, 112, 104, 218, 246, 97, 228,
-	251, 34, 242, 193, 238, 210, 144, 12, 191, 179, 162, 241, 81, 51, 145, 235, 249, 14, 239, 107,
-	49, 192, 214, 31, 181, 199, 106, 157, 184, 84, 204, 176, 115, 121, 50, 45, 127, 4, 150, 254,
-	138, 236, 205, 93, 222, 114, 67, 29, 24, 72, 243, 141, 128, 195, 78, 66, 215, 61, 156, 180,
-}
-
 // Helper functions to compute gradients-dot-residualvectors (1D to 4D)
 // Note that these generate gradients of more than unit length. To make
 // a close match with the value range of classic Perlin noise, the final
 // noise values need to be rescaled to fit nicely within [-1,1].
 // (The simplex noise functions as such also have different scaling.)
 
-func q(cond bool, v1 int32, v2 int32) int32 {
-	if cond {
-		return v1
-	}
-	return v2
-}
-
 // hash is 0..0xff, x is 0.12 fixed point
 // returns *.12 fixed-point value
 func grad1(hash uint8, x int32) int32 {
@@ -74,18 +52,32 @@ func grad1(hash uint8, x int32) int32 {
 	return grad * x // Multiply the gradient with the distance (integer * 0.12 = *.12)
 }
 
+// Gradient directions for 2D noise, indexed by the low 3 bits of the hash.
+// Looking up the gradient and calculating the dot product with multiplications
+// avoids the unpredictable branches of the original implementation, which are
+// slow on microcontrollers without branch prediction.
+var grad2Table = [8][2]int8{
+	{1, 2}, {-1, 2}, {1, -2}, {-1, -2},
+	{2, 1}, {2, -1}, {-2, 1}, {-2, -1},
+}
+
+// Gradient directions for 3D noise, indexed by the low 4 bits of the hash:
+// the 12 edges of a cube, with 4 of them repeated to fill the table.
+var grad3Table = [16][3]int8{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+	{1, 1, 0}, {0, -1, 1}, {-1, 1, 0}, {0, -1, -1},
+}
+
 func grad2(hash uint8, x, y int32) int32 {
-	h := hash & 7       // Convert low 3 bits of hash code
-	u := q(h < 4, x, y) // into 8 simple gradient directions,
-	v := q(h < 4, y, x) // and compute the dot product with (x,y).
-	return q(h&1 != 0, -u, u) + q(h&2 != 0, -2*v, 2*v)
+	g := &grad2Table[hash&7]
+	return int32(g[0])*x + int32(g[1])*y
 }
 
 func grad3(hash uint8, x, y, z int32) int32 {
-	h := hash & 15                                // Convert low 4 bits of hash code into 12 simple
-	u := q(h < 8, x, y)                           // gradient directions, and compute dot product.
-	v := q(h < 4, y, q(h == 12 || h == 14, x, z)) // Fix repeats at h = 12 to 15
-	return q(h&1 != 0, -u, u) + q(h&2 != 0, -v, v)
+	g := &grad3Table[hash&15]
+	return int32(g[0])*x + int32(g[1])*y + int32(g[2])*z
 }
 
 // 1D simplex noise.
@@ -108,10 +100,14 @@ func Noise1(x int32) int16 {
 	t1 = (t1 * t1) >> 15                        // .15
 	n1 := (t1 * grad1(perm[i1&0xff], x1)) >> 12 // .15 * .12 = .15
 
-	n := n0 + n1          // .15
-	n += 2503             // .15: fix offset, adjust to +0.03
-	n = (n << 14) / 40225 // .15: fix scale to fit in [-1,1]
-	return int16(n)
+	n := n0 + n1               // .15
+	n += 2503                  // .15: fix offset, adjust to +0.03
+	return int16(divNoise1(n)) // .15: fix scale to fit in [-1,1]
+}
+
+// noise1Grad returns the gradient of Noise1 at lattice point i, see grad1.
+func noise1Grad(i int32) int32 {
+	return grad1(perm[i&0xff], 1)
 }
 
 // 2D simplex noise.
@@ -119,6 +115,12 @@ func Noise1(x int32) int16 {
 // The x and y inputs are 19.12 fixed-point value. The result covers the full
 // range of an int16 so is a 0.15 fixed-point value.
 func Noise2(x, y int32) int16 {
+	return int16(noise2(x, y))
+}
+
+// noise2 implements Noise2. It returns the result before converting it to an
+// int16, so that tests can check whether it overflows.
+func noise2(x, y int32) int32 {
 	const F2 = 1572067135 // .32: F2 = 0.5*(sqrt(3.0)-1.0)
 	const G2 = 907633384  // .32: G2 = (3.0-Math.sqrt(3.0))/6.0
 
@@ -179,9 +181,46 @@ func Noise2(x, y int32) int16 {
 
 	// Add contributions from each corner to get the final noise value.
 	// The result is scaled to return values in the interval [-1,1].
-	n := n0 + n1 + n2    // .30
-	n = (n << 6) / 46360 // fix scale to fit exactly in an int16
-	return int16(n)
+	n := n0 + n1 + n2   // .30
+	return divNoise2(n) // fix scale to fit exactly in an int16
+}
+
+// NoiseCell identifies a simplex (a triangle) of the lattice that 2D simplex
+// noise is built on: every square cell of the skewed lattice is split into a
+// lower and an upper triangle.
+type NoiseCell struct {
+	I, J  int32 // lattice coordinates of the square cell
+	Upper bool  // whether this is the upper triangle of the square cell
+	Hash  uint8 // pseudo-random value of this triangle
+}
+
+// Noise2Cell is like Noise2, but also returns the simplex cell that (x, y)
+// lies in. All points in a cell get the same NoiseCell, which is useful for
+// cell-quantized effects such as a mosaic or stained glass, for example by
+// giving every cell a color based on its Hash. The noise value is the same as
+// that returned by Noise2.
+func Noise2Cell(x, y int32) (int16, NoiseCell) {
+	// The cell is determined in the same way as in noise2. It is not returned
+	// from noise2 itself, because that makes Noise2 noticeably slower.
+	const F2 = 1572067135 // .32: F2 = 0.5*(sqrt(3.0)-1.0)
+	const G2 = 907633384  // .32: G2 = (3.0-Math.sqrt(3.0))/6.0
+
+	s := int32(((int64(x) + int64(y)) * F2) >> 32) // .12
+	i := (x>>1 + s>>1) >> 11                       // .0
+	j := (y>>1 + s>>1) >> 11                       // .0
+	t := ((int64(i) + int64(j)) * G2)              // .32
+	x0 := int64(x)<<20 - (int64(i)<<32 - t)        // .32
+	y0 := int64(y)<<20 - (int64(j)<<32 - t)        // .32
+
+	// The hash of the first corner (also used for its gradient) is shared by
+	// both triangles of the square cell, so hash it once more for the upper
+	// triangle.
+	cell := NoiseCell{I: i, J: j, Upper: x0 <= y0}
+	cell.Hash = perm[(i+int32(perm[j&0xff]))&0xff]
+	if cell.Upper {
+		cell.Hash = perm[cell.Hash^0x55]
+	}
+	return Noise2(x, y), cell
 }
 
 // 3D simplex noise.
@@ -189,6 +228,11 @@ func Noise2(x, y int32) int16 {
 // The x and y inputs are 19.12 fixed-point value. The result covers the full
 // range of an int16 so is a 0.15 fixed-point value.
 func Noise3(x, y, z int32) int16 {
+	return int16(noise3(x, y, z))
+}
+
+// noise3 implements Noise3, see noise2.
+func noise3(x, y, z int32) int32 {
 	// Simple skewing factors for the 3D case
 	const F3 = 1431655764 // .32: 0.333333333
 	const G3 = 715827884  // .32: 0.166666667
@@ -315,6 +359,33 @@ func Noise3(x, y, z int32) int16 {
 	// Add contributions from each corner to get the final noise value.
 	// The result is scaled to stay just inside [-1,1]
 	n := n0 + n1 + n2 + n3 // .30
-	n = (n << 6) / 64120   // fix scale to fit exactly in an int16
-	return int16(n)
+	return divNoise3(n)    // fix scale to fit exactly in an int16
+}
+
+// Reciprocals used to scale the noise output, rounded up.
+const (
+	noise1Reciprocal = 3498756703 // .47: 1/40225
+	noise2Reciprocal = 3035752553 // .47: 1/46360
+	noise3Reciprocal = 2194907804 // .47: 1/64120
+)
+
+// divNoise1 returns (n << 14) / 40225 for any n where n << 14 fits in an
+// int32, exactly. See divNoise2.
+func divNoise1(n int32) int32 {
+	return int32((int64(n)*noise1Reciprocal)>>33) - n>>31 // .15 << 14 * .47 >> 47 = .15
+}
+
+// divNoise2 returns (n << 6) / 46360, for any n where n << 6 fits in an int32.
+// Microcontrollers such as the Cortex-M0 have no division instruction, so a
+// multiplication with the reciprocal is a lot faster. The result is exactly
+// the same as that of the division: the error of the rounded up reciprocal is
+// too small to change the result for these inputs, and the sign correction
+// makes it round towards zero like a division.
+func divNoise2(n int32) int32 {
+	return int32((int64(n)*noise2Reciprocal)>>41) - n>>31 // .30 << 6 * .47 >> 41 = .0
+}
+
+// divNoise3 returns (n << 6) / 64120, exactly. See divNoise2.
+func divNoise3(n int32) int32 {
+	return int32((int64(n)*noise3Reciprocal)>>41) - n>>31 // .30 << 6 * .47 >> 41 = .0
 }
@@ -1,13 +1,20 @@
 package ledsgo
 
-// Note: this file contains external code, see below.
-
 import (
+	"bufio"
+	"flag"
+	"fmt"
 	"math"
 	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 )
 
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
 func TestNoise1(t *testing.T) {
 	numTests := int64(10000000) // ~0.25s
 	//numTests = 0xffffffff // for exhaustive testing (takes ~100 seconds)
@@ -177,6 +184,296 @@ func TestNoise3(t *testing.T) {
 	}
 }
 
+// TestPerm checks that the permutation table is the same in all builds (see
+// footprint_small.go), since all noise functions depend on it.
+func TestPerm(t *testing.T) {
+	var seen [256]bool
+	var sum uint32
+	for i := 0; i < 256; i++ {
+		seen[perm[i]] = true
+		sum = sum*31 + uint32(perm[i])
+	}
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("perm: missing %d", i)
+		}
+	}
+	if sum != 0x570e7cb8 {
+		t.Errorf("perm: expected checksum 0x570e7cb8, got %#x", sum)
+	}
+}
+
+// TestGradTables checks that the gradient tables result in exactly the same
+// dot products as the branchy reference implementation.
+func TestGradTables(t *testing.T) {
+	inputs := []int32{0, 1, -1, 0x1000, -0x1000, 12345, -32768, 32767}
+	for hash := 0; hash < 256; hash++ {
+		for _, x := range inputs {
+			for _, y := range inputs {
+				expected := grad2Float(uint8(hash), float64(x), float64(y))
+				if n := grad2(uint8(hash), x, y); float64(n) != expected {
+					t.Errorf("grad2(%d, %d, %d): expected %v, got %d", hash, x, y, expected, n)
+				}
+				for _, z := range inputs {
+					expected := grad3Float(uint8(hash), float64(x), float64(y), float64(z))
+					if n := grad3(uint8(hash), x, y, z); float64(n) != expected {
+						t.Errorf("grad3(%d, %d, %d, %d): expected %v, got %d", hash, x, y, z, expected, n)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestNoiseScale checks that the division-free output scaling results in
+// exactly the same values as a division, for all possible inputs.
+func TestNoiseScale(t *testing.T) {
+	step := int32(1)
+	if testing.Short() {
+		step = 97
+	}
+	for n := int32(-1<<17 + 1); n < 1<<17; n++ {
+		if got, expected := divNoise1(n), (n<<14)/40225; got != expected {
+			t.Fatalf("divNoise1(%d): expected %d, got %d", n, expected, got)
+		}
+	}
+	for n := int32(-1 << 25); n < 1<<25; n += step {
+		if got, expected := divNoise2(n), (n<<6)/46360; got != expected {
+			t.Fatalf("divNoise2(%d): expected %d, got %d", n, expected, got)
+		}
+		if got, expected := divNoise3(n), (n<<6)/64120; got != expected {
+			t.Fatalf("divNoise3(%d): expected %d, got %d", n, expected, got)
+		}
+	}
+}
+
+// TestNoiseContinuity checks that a step of the smallest possible size in the
+// input never results in a big jump in the output. A bug at the boundaries of
+// the simplex cells would result in visible lines on a LED matrix, while the
+// average and maximum difference with the float version (see TestNoise2)
+// could still look fine.
+//
+// The bounds are a bit above the largest steps found by a long run of this
+// test: the steepest slope of the noise itself, plus a bit of rounding noise.
+func TestNoiseContinuity(t *testing.T) {
+	walks := 200
+	if testing.Short() {
+		walks = 20
+	}
+	const (
+		max1 = 64
+		max2 = 256
+		max3 = 320
+	)
+	directions := [][3]int32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, 1, 1}, {1, -1, 0}}
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < walks; i++ {
+		// Start somewhere in the range of ±32768 and walk one unit (several
+		// cell boundaries) in every direction.
+		x, y, z := int32(r.Uint32())>>4, int32(r.Uint32())>>4, int32(r.Uint32())>>4
+		for _, d := range directions {
+			prev1, prev2, prev3 := int(Noise1(x)), int(Noise2(x, y)), int(Noise3(x, y, z))
+			for k := int32(1); k <= 0x1000; k++ {
+				px, py, pz := x+k*d[0], y+k*d[1], z+k*d[2]
+				n1, n2, n3 := int(Noise1(px)), int(Noise2(px, py)), int(Noise3(px, py, pz))
+				if d[0] != 0 && abs(n1-prev1) > max1 {
+					t.Fatalf("Noise1 jumps from %d to %d at x=%d", prev1, n1, px)
+				}
+				if (d[0] != 0 || d[1] != 0) && abs(n2-prev2) > max2 {
+					t.Fatalf("Noise2 jumps from %d to %d at x=%d, y=%d", prev2, n2, px, py)
+				}
+				if abs(n3-prev3) > max3 {
+					t.Fatalf("Noise3 jumps from %d to %d at x=%d, y=%d, z=%d", prev3, n3, px, py, pz)
+				}
+				prev1, prev2, prev3 = n1, n2, n3
+			}
+		}
+	}
+}
+
+// FuzzNoise2 cross-checks Noise2 against the floating point reference over the
+// full input range, and checks that the result doesn't overflow an int16. The
+// tolerance is the same as in TestNoise2.
+func FuzzNoise2(f *testing.F) {
+	for _, v := range []int32{0, 1, -1, 0x1000, -0x1000, 0x7fff, 1 << 30, math.MaxInt32, math.MinInt32} {
+		f.Add(v, v)
+		f.Add(v, -v)
+		f.Add(v, int32(0x1234))
+	}
+	f.Fuzz(func(t *testing.T, x, y int32) {
+		n := noise2(x, y)
+		if n < math.MinInt16 || n > math.MaxInt16 {
+			t.Fatalf("Noise2(%d, %d) overflows: %d", x, y, n)
+		}
+		expected := Noise2Float(float64(x)/0x1000, float64(y)/0x1000)
+		if diff := float64(n)/0x8000 - expected; math.Abs(diff) > 0.005 {
+			t.Fatalf("Noise2(%d, %d) = %d, expected %f: difference %f is too big", x, y, n, expected*0x8000, diff)
+		}
+	})
+}
+
+// FuzzNoise3 is like FuzzNoise2, for Noise3.
+func FuzzNoise3(f *testing.F) {
+	for _, v := range []int32{0, 1, -1, 0x1000, -0x1000, 0x7fff, 1 << 30, math.MaxInt32, math.MinInt32} {
+		f.Add(v, v, v)
+		f.Add(v, -v, v)
+		f.Add(v, int32(0x1234), -v)
+	}
+	f.Fuzz(func(t *testing.T, x, y, z int32) {
+		n := noise3(x, y, z)
+		if n < math.MinInt16 || n > math.MaxInt16 {
+			t.Fatalf("Noise3(%d, %d, %d) overflows: %d", x, y, z, n)
+		}
+		expected := Noise3Float(float64(x)/0x1000, float64(y)/0x1000, float64(z)/0x1000)
+		if diff := float64(n)/0x8000 - expected; math.Abs(diff) > 0.008 {
+			t.Fatalf("Noise3(%d, %d, %d) = %d, expected %f: difference %f is too big", x, y, z, n, expected*0x8000, diff)
+		}
+	})
+}
+
+// noiseGolden describes a golden file with the outputs of a noise function
+// over a dense grid. The grid has size samples along every axis, starting at
+// start and stepping by step (all .12 fixed-point). The step is chosen to not
+// line up with the simplex cells, so that all kinds of positions within a cell
+// are tested.
+type noiseGolden struct {
+	file      string
+	dims      int
+	start     int32
+	step      int32
+	size      int
+	tolerance float64 // maximum difference with the floating point version
+}
+
+var noiseGoldens = []noiseGolden{
+	{"noise1.golden", 1, -0x20000, 0x81, 2048, 0.0003},
+	{"noise2.golden", 2, -0x20000, 0x1c3, 64, 0.005},
+	{"noise3.golden", 3, -0x8000, 0x7b3, 16, 0.008},
+}
+
+// coords returns the grid coordinates of the sample at the given index, with
+// x changing the fastest.
+func (g noiseGolden) coords(index int) (x, y, z int32) {
+	x = g.start + int32(index%g.size)*g.step
+	y = g.start + int32(index/g.size%g.size)*g.step
+	z = g.start + int32(index/g.size/g.size)*g.step
+	return
+}
+
+// point formats the coordinates of the sample at the given index.
+func (g noiseGolden) point(index int) string {
+	x, y, z := g.coords(index)
+	return fmt.Sprint([]int32{x, y, z}[:g.dims])
+}
+
+// sample returns the fixed-point output and the floating point reference
+// output at the given grid index.
+func (g noiseGolden) sample(index int) (int16, float64) {
+	x, y, z := g.coords(index)
+	xf, yf, zf := float64(x)/0x1000, float64(y)/0x1000, float64(z)/0x1000
+	switch g.dims {
+	case 1:
+		return Noise1(x), Noise1Float(xf)
+	case 2:
+		return Noise2(x, y), Noise2Float(xf, yf)
+	default:
+		return Noise3(x, y, z), Noise3Float(xf, yf, zf)
+	}
+}
+
+func (g noiseGolden) len() int {
+	n := 1
+	for i := 0; i < g.dims; i++ {
+		n *= g.size
+	}
+	return n
+}
+
+func (g noiseGolden) write(values []int16) error {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "# %dD noise over a grid of %d points per axis: start %d, step %d (.12).\n", g.dims, g.size, g.start, g.step)
+	fmt.Fprintf(&buf, "# Generated by TestNoiseGolden with -update. Do not edit.\n")
+	for i, v := range values {
+		buf.WriteString(strconv.Itoa(int(v)))
+		if i%16 == 15 || i == len(values)-1 {
+			buf.WriteByte('\n')
+		} else {
+			buf.WriteByte(' ')
+		}
+	}
+	return os.WriteFile(filepath.Join("testdata", g.file), []byte(buf.String()), 0o644)
+}
+
+func (g noiseGolden) read() ([]int16, error) {
+	f, err := os.Open(filepath.Join("testdata", g.file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var values []int16
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			v, err := strconv.ParseInt(field, 10, 16)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, int16(v))
+		}
+	}
+	return values, scanner.Err()
+}
+
+// TestNoiseGolden checks that the noise functions return exactly the values in
+// the golden files, and that these values match the floating point reference
+// implementations. Optimizations must not change the visual result, so any
+// change to the golden files should be a deliberate one. Run the test with
+// -update to regenerate them.
+func TestNoiseGolden(t *testing.T) {
+	for _, g := range noiseGoldens {
+		values := make([]int16, g.len())
+		for i := range values {
+			values[i], _ = g.sample(i)
+		}
+		if *updateGolden {
+			if err := g.write(values); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		golden, err := g.read()
+		if err != nil {
+			t.Fatalf("%s: %v", g.file, err)
+		}
+		if len(golden) != len(values) {
+			t.Fatalf("%s: expected %d values, found %d", g.file, len(values), len(golden))
+		}
+		changed := 0
+		for i, v := range values {
+			if v != golden[i] {
+				if changed == 0 {
+					t.Errorf("%s: output at %s changed from %d to %d", g.file, g.point(i), golden[i], v)
+				}
+				changed++
+			}
+		}
+		if changed != 0 {
+			t.Errorf("%s: %d of %d outputs changed", g.file, changed, len(values))
+		}
+		for i, v := range golden {
+			_, ref := g.sample(i)
+			if diff := math.Abs(float64(v)/0x8000 - ref); diff > g.tolerance {
+				t.Errorf("%s: golden value at %s differs %f from the float reference", g.file, g.point(i), diff)
+			}
+		}
+	}
+}
+
 // avoid compiler optimizations
 var (
 	resultInt16   int16
@@ -231,306 +528,51 @@ func BenchmarkNoise3Float(b *testing.B) {
 	resultFloat64 = r
 }
 
-// The following code was written by Stefan Gustavson and was converted to Go by
-// Lars Pensjö. It is released in the public domain.
-//
-// Original note:
-//
-//     SimplexNoise1234, Simplex noise with true analytic
-//     derivative in 1D to 4D.
-//
-//     Author: Stefan Gustavson, 2003-2005
-//     Contact: stegu@itn.liu.se
-//
-//     This code was GPL licensed until February 2011.
-//     As the original author of this code, I hereby
-//     release it into the public domain.
-//     Please feel free to use it for whatever you want.
-//     Credit is appreciated where appropriate, and I also
-//     appreciate being told where this code finds any use,
-//     but you may do as you like.
-//
-//
-//     C implementation of Perlin Simplex Noise over 1,2,3, and 4 dimensions.
-//     Author: Stefan Gustavson (stegu@itn.liu.se)
-//
-//     Adapted to Go by Lars Pensjö (lars.pensjo@gmail.com)
-//     This implementation is "Simplex Noise" as presented by
-//     Ken Perlin at a relatively obscure and not often cited course
-//     session "Real-Time Shading" at Siggraph 2001 (before real
-//     time shading actually took on), under the title "hardware noise".
-//     The 3D function is numerically equivalent to his Java reference
-//     code available in the PDF course notes, although I re-implemented
-//     it from scratch to get more readable code. The 1D, 2D and 4D cases
-//     were implemented from scratch by me from Ken Perlin's text.
-
-func FASTFLOOR(x float64) int {
-	if x > 0 {
-		return int(x)
-	}
-	return int(x) - 1
-}
+func TestNoise2Cell(t *testing.T) {
+	F2 := 0.5 * (math.Sqrt(3) - 1)
+	G2 := (3 - math.Sqrt(3)) / 6
+	hashes := make(map[NoiseCell]uint8)
+	for y := int32(-0x8000); y < 0x8000; y += 0x95 {
+		for x := int32(-0x8000); x < 0x8000; x += 0x83 {
+			n, cell := Noise2Cell(x, y)
+			if n != Noise2(x, y) {
+				t.Fatalf("Noise2Cell(%d, %d): noise %d differs from Noise2 %d", x, y, n, Noise2(x, y))
+			}
 
-// Helper functions to compute gradients-dot-residualvectors (1D to 4D)
-// Note that these generate gradients of more than unit length. To make
-// a close match with the value range of classic Perlin noise, the final
-// noise values need to be rescaled to fit nicely within [-1,1].
-// (The simplex noise functions as such also have different scaling.)
-// Note also that these noise functions are the most practical and useful
-// signed version of Perlin noise. To return values according to the
-// RenderMan specification from the SL noise() and pnoise() functions,
-// the noise values need to be scaled and offset to [0,1], like this:
-// float SLnoise = (noise(x,y,z) + 1.0) * 0.5;
-
-func qFloat(cond bool, v1 float64, v2 float64) float64 {
-	if cond {
-		return v1
-	}
-	return v2
-}
+			// Compare against the floating point cell, except close to the
+			// edges where rounding may put the point in a neighbouring cell.
+			xf, yf := float64(x)/4096, float64(y)/4096
+			s := (xf + yf) * F2
+			i, j := math.Floor(xf+s), math.Floor(yf+s)
+			x0 := xf - (i - (i+j)*G2)
+			y0 := yf - (j - (i+j)*G2)
+			fx, fy := xf+s-i, yf+s-j
+			nearEdge := math.Abs(x0-y0) < 0.001 || fx < 0.001 || fx > 0.999 || fy < 0.001 || fy > 0.999
+			if !nearEdge && (cell.I != int32(i) || cell.J != int32(j) || cell.Upper != (x0 <= y0)) {
+				t.Errorf("Noise2Cell(%d, %d): expected cell (%v, %v, %v), got %+v", x, y, i, j, x0 <= y0, cell)
+			}
 
-func grad1Float(hash uint8, x float64) float64 {
-	h := hash & 15
-	grad := float64(1 + h&7) // Gradient value 1.0, 2.0, ..., 8.0
-	if h&8 != 0 {
-		grad = -grad // Set a random sign for the gradient
+			hash := cell.Hash
+			cell.Hash = 0
+			if h, ok := hashes[cell]; ok && h != hash {
+				t.Errorf("Noise2Cell(%d, %d): hash %d differs from %d earlier in the same cell", x, y, hash, h)
+			}
+			hashes[cell] = hash
+		}
 	}
-	return grad * x // Multiply the gradient with the distance
-}
-
-func grad2Float(hash uint8, x float64, y float64) float64 {
-	h := hash & 7            // Convert low 3 bits of hash code
-	u := qFloat(h < 4, x, y) // into 8 simple gradient directions,
-	v := qFloat(h < 4, y, x) // and compute the dot product with (x,y).
-	return qFloat(h&1 != 0, -u, u) + qFloat(h&2 != 0, -2*v, 2*v)
-}
-
-func grad3Float(hash uint8, x, y, z float64) float64 {
-	h := hash & 15                                          // Convert low 4 bits of hash code into 12 simple
-	u := qFloat(h < 8, x, y)                                // gradient directions, and compute dot product.
-	v := qFloat(h < 4, y, qFloat(h == 12 || h == 14, x, z)) // Fix repeats at h = 12 to 15
-	return qFloat(h&1 != 0, -u, u) + qFloat(h&2 != 0, -v, v)
-}
-
-// 1D simplex noise
-func Noise1Float(x float64) float64 {
-	i0 := FASTFLOOR(x)
-	i1 := i0 + 1
-	x0 := x - float64(i0)
-	x1 := x0 - 1
-
-	t0 := 1 - x0*x0
-	t0 *= t0
-	n0 := t0 * t0 * grad1Float(perm[i0&0xff], x0)
-
-	t1 := 1 - x1*x1
-	t1 *= t1
-	n1 := t1 * t1 * grad1Float(perm[i1&0xff], x1)
-	// The maximum value of this noise is 8*(3/4)^4 = 2.53125
-	// A factor of 0.395 would scale to fit exactly within [-1,1].
-	// fmt.Printf("Noise1 x %.4f, i0 %v, i1 %v, x0 %.4f, x1 %.4f, perm0 %d, perm1 %d: %.4f,%.4f\n", x, i0, i1, x0, x1, perm[i0&0xff], perm[i1&0xff], n0, n1)
-	// The algorithm isn't perfect, as it is assymetric. The correction will normalize the result to the interval [-1,1], but the average will be off by 3%.
-	return (n0 + n1 + 0.076368899) / 2.45488110001
-}
 
-// 2D simplex noise
-func Noise2Float(x, y float64) float64 {
-	const F2 = 0.366025403 // F2 = 0.5*(sqrt(3.0)-1.0)
-	const G2 = 0.211324865 // G2 = (3.0-Math.sqrt(3.0))/6.0
-
-	var n0, n1, n2 float64 // Noise contributions from the three corners
-
-	// Skew the input space to determine which simplex cell we're in
-	s := (x + y) * F2 // Hairy factor for 2D
-	xs := x + s
-	ys := y + s
-	i := FASTFLOOR(xs)
-	j := FASTFLOOR(ys)
-
-	t := float64(i+j) * G2
-	X0 := float64(i) - t // Unskew the cell origin back to (x,y) space
-	Y0 := float64(j) - t
-	x0 := x - X0 // The x,y distances from the cell origin
-	y0 := y - Y0
-
-	// For the 2D case, the simplex shape is an equilateral triangle.
-	// Determine which simplex we are in.
-	var i1, j1 int // Offsets for second (middle) corner of simplex in (i,j) coords
-	if x0 > y0 {
-		i1 = 1
-		j1 = 0 // lower triangle, XY order: (0,0)->(1,0)->(1,1)
-	} else {
-		i1 = 0
-		j1 = 1
-	} // upper triangle, YX order: (0,0)->(0,1)->(1,1)
-
-	// A step of (1,0) in (i,j) means a step of (1-c,-c) in (x,y), and
-	// a step of (0,1) in (i,j) means a step of (-c,1-c) in (x,y), where
-	// c = (3-sqrt(3))/6
-
-	x1 := x0 - float64(i1) + G2 // Offsets for middle corner in (x,y) unskewed coords
-	y1 := y0 - float64(j1) + G2
-	x2 := x0 - 1 + 2*G2 // Offsets for last corner in (x,y) unskewed coords
-	y2 := y0 - 1 + 2*G2
-
-	// Calculate the contribution from the three corners
-	t0 := 0.5 - x0*x0 - y0*y0
-	if t0 < 0 {
-		n0 = 0
-	} else {
-		t0 *= t0
-		n0 = t0 * t0 * grad2Float(perm[(i+int(perm[j&0xff]))&0xff], x0, y0)
-	}
-
-	t1 := 0.5 - x1*x1 - y1*y1
-	if t1 < 0 {
-		n1 = 0
-	} else {
-		t1 *= t1
-		n1 = t1 * t1 * grad2Float(perm[(i+i1+int(perm[((j+j1)&0xff)&0xff]))&0xff], x1, y1)
-	}
-
-	t2 := 0.5 - x2*x2 - y2*y2
-	if t2 < 0 {
-		n2 = 0
-	} else {
-		t2 *= t2
-		n2 = t2 * t2 * grad2Float(perm[(i+1+int(perm[(j+1)&0xff]))&0xff], x2, y2)
-	}
-
-	// Add contributions from each corner to get the final noise value.
-	// The result is scaled to return values in the interval [-1,1].
-	return (n0 + n1 + n2) / 0.022108854818853867
-}
-
-// 3D simplex noise
-func Noise3Float(x, y, z float64) float64 {
-	// Simple skewing factors for the 3D case
-	const F3 = 0.333333333
-	const G3 = 0.166666667
-
-	var n0, n1, n2, n3 float64 // Noise contributions from the four corners
-
-	// Skew the input space to determine which simplex cell we're in
-	s := (x + y + z) * F3 // Very nice and simple skew factor for 3D
-	xs := x + s
-	ys := y + s
-	zs := z + s
-	i := FASTFLOOR(xs)
-	j := FASTFLOOR(ys)
-	k := FASTFLOOR(zs)
-
-	t := float64(i+j+k) * G3
-	X0 := float64(i) - t // Unskew the cell origin back to (x,y,z) space
-	Y0 := float64(j) - t
-	Z0 := float64(k) - t
-	x0 := float64(x) - X0 // The x,y,z distances from the cell origin
-	y0 := float64(y) - Y0
-	z0 := float64(z) - Z0
-
-	// For the 3D case, the simplex shape is a slightly irregular tetrahedron.
-	// Determine which simplex we are in.
-	var i1, j1, k1 int // Offsets for second corner of simplex in (i,j,k) coords
-	var i2, j2, k2 int // Offsets for third corner of simplex in (i,j,k) coords
-
-	// This code would benefit from a backport from the GLSL version!
-	if x0 >= y0 {
-		if y0 >= z0 {
-			i1 = 1
-			j1 = 0
-			k1 = 0
-			i2 = 1
-			j2 = 1
-			k2 = 0 // X Y Z order
-		} else if x0 >= z0 {
-			i1 = 1
-			j1 = 0
-			k1 = 0
-			i2 = 1
-			j2 = 0
-			k2 = 1 // X Z Y order
-		} else {
-			i1 = 0
-			j1 = 0
-			k1 = 1
-			i2 = 1
-			j2 = 0
-			k2 = 1 // Z X Y order
+	// The two triangles of a square cell should usually get different hashes.
+	same := 0
+	for cell, hash := range hashes {
+		if !cell.Upper {
+			continue
 		}
-	} else { // x0<y0
-		if y0 < z0 {
-			i1 = 0
-			j1 = 0
-			k1 = 1
-			i2 = 0
-			j2 = 1
-			k2 = 1 // Z Y X order
-		} else if x0 < z0 {
-			i1 = 0
-			j1 = 1
-			k1 = 0
-			i2 = 0
-			j2 = 1
-			k2 = 1 // Y Z X order
-		} else {
-			i1 = 0
-			j1 = 1
-			k1 = 0
-			i2 = 1
-			j2 = 1
-			k2 = 0 // Y X Z order
+		cell.Upper = false
+		if h, ok := hashes[cell]; ok && h == hash {
+			same++
 		}
 	}
-
-	// A step of (1,0,0) in (i,j,k) means a step of (1-c,-c,-c) in (x,y,z),
-	// a step of (0,1,0) in (i,j,k) means a step of (-c,1-c,-c) in (x,y,z), and
-	// a step of (0,0,1) in (i,j,k) means a step of (-c,-c,1-c) in (x,y,z), where
-	// c = 1/6.
-
-	x1 := x0 - float64(i1) + G3 // Offsets for second corner in (x,y,z) coords
-	y1 := y0 - float64(j1) + G3
-	z1 := z0 - float64(k1) + G3
-	x2 := x0 - float64(i2) + 2*G3 // Offsets for third corner in (x,y,z) coords
-	y2 := y0 - float64(j2) + 2*G3
-	z2 := z0 - float64(k2) + 2*G3
-	x3 := x0 - 1 + 3*G3 // Offsets for last corner in (x,y,z) coords
-	y3 := y0 - 1 + 3*G3
-	z3 := z0 - 1 + 3*G3
-
-	// Calculate the contribution from the four corners
-	t0 := 0.6 - x0*x0 - y0*y0 - z0*z0
-	if t0 < 0 {
-		n0 = 0
-	} else {
-		t0 *= t0
-		n0 = t0 * t0 * grad3Float(perm[(i+int(perm[(j+int(perm[k&0xff]))&0xff]))&0xff], x0, y0, z0)
-	}
-
-	t1 := 0.6 - x1*x1 - y1*y1 - z1*z1
-	if t1 < 0 {
-		n1 = 0
-	} else {
-		t1 *= t1
-		n1 = t1 * t1 * grad3Float(perm[(i+i1+int(perm[(j+j1+int(perm[(k+k1)&0xff]))&0xff]))&0xff], x1, y1, z1)
-	}
-
-	t2 := 0.6 - x2*x2 - y2*y2 - z2*z2
-	if t2 < 0 {
-		n2 = 0
-	} else {
-		t2 *= t2
-		n2 = t2 * t2 * grad3Float(perm[(i+i2+int(perm[(j+j2+int(perm[(k+k2)&0xff]))&0xff]))&0xff], x2, y2, z2)
-	}
-
-	t3 := 0.6 - x3*x3 - y3*y3 - z3*z3
-	if t3 < 0 {
-		n3 = 0
-	} else {
-		t3 *= t3
-		n3 = t3 * t3 * grad3Float(perm[(i+1+int(perm[(j+1+int(perm[(k+1)&0xff]))&0xff]))&0xff], x3, y3, z3)
-	}
-
-	// Add contributions from each corner to get the final noise value.
-	// The result is scaled to stay just inside [-1,1]
-	return (n0 + n1 + n2 + n3) / 0.030555466710745972
+	if same > len(hashes)/50 {
+		t.Errorf("too many cells with the same hash for both triangles: %d of %d", same, len(hashes)/2)
+	}
 }
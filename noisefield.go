@@ -0,0 +1,123 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// NoiseField samples simplex noise for every LED of a strip or matrix, with
+// the offset, zoom and speed that every noise animation needs. This replaces
+// the fixed-point arithmetic that would otherwise be repeated in every
+// animation loop:
+//
+//	field := NoiseField{Zoom: 0x0400, Speed: 0x2000}
+//	field.SetTime(t)
+//	for i := range frame {
+//		frame[i] = palette.ColorAt(uint8(field.At(i)>>8 + 128))
+//	}
+//
+// All values are 19.12 fixed-point values in the coordinate space of the
+// noise functions, where 0x1000 is about the size of a blob.
+type NoiseField struct {
+	X, Y, Z int32 // offset of the LED at index (0, 0)
+	Zoom    int32 // distance between two neighbouring LEDs
+	Speed   int32 // movement along the time axis per second
+
+	time int32 // position on the time axis, set by SetTime
+}
+
+// SetTime moves the field along the time axis to time t, by Speed per second.
+// The position on the time axis wraps around like NoiseTime.At.
+func (f *NoiseField) SetTime(t time.Duration) {
+	f.time = NoiseTime{Speed: f.Speed}.At(t)
+}
+
+// At returns the noise value of the LED at index i of a strip. The LEDs are
+// placed along the x axis of 2D noise, and the y axis is used for time.
+func (f *NoiseField) At(i int) int16 {
+	return Noise2(f.X+int32(i)*f.Zoom, f.Y+f.time)
+}
+
+// AtXY returns the noise value of the LED at (x, y) of a matrix. The LEDs are
+// placed in the x/y plane of 3D noise, and the z axis is used for time.
+func (f *NoiseField) AtXY(x, y int) int16 {
+	return Noise3(f.X+int32(x)*f.Zoom, f.Y+int32(y)*f.Zoom, f.Z+f.time)
+}
+
+// Fill stores At(i) in dst[i] for all values in dst, which is faster than
+// calling At for every LED (see FillNoise2).
+func (f *NoiseField) Fill(dst []int16) {
+	FillNoise2(dst, f.X, f.Y+f.time, f.Zoom, 0)
+}
+
+// NoiseTime is the time axis of an animated noise pattern, such as the z axis
+// of Noise3 for a matrix. Multiplying the time by the speed directly would
+// overflow the 19.12 input range of the noise functions after a few days of
+// uptime (or even overflow 64 bits for high speeds), so the coordinate wraps
+// around every Period instead. This leaves plenty of room for offsets.
+//
+// The simplex noise functions other than Noise1 don't repeat along a single
+// axis, so the pattern jumps when the coordinate wraps around. Use Crossfade
+// to hide this jump.
+type NoiseTime struct {
+	// Speed is the distance per second along the time axis, as a 19.12
+	// fixed-point value.
+	Speed int32
+
+	// Period is the number of cells (of 0x1000) after which the coordinate
+	// wraps around, at most 0x7ffff. The default (0) is 0x10000, which wraps
+	// around once every 18 hours at a speed of 0x1000.
+	Period int32
+}
+
+// period returns the period in 19.12 fixed-point units.
+func (nt NoiseTime) period() int64 {
+	if nt.Period <= 0 {
+		return 0x10000 << 12
+	}
+	return int64(min(nt.Period, 0x7ffff)) << 12
+}
+
+// At returns the coordinate along the time axis at time t, which is in the
+// range [0, Period*0x1000).
+func (nt NoiseTime) At(t time.Duration) int32 {
+	period := nt.period()
+	speed := int64(nt.Speed)
+	// Calculate whole seconds and the remaining nanoseconds separately, to
+	// avoid overflow.
+	seconds := int64(t/time.Second) % period
+	nanos := int64(t % time.Second)
+	z := (seconds*speed + nanos*speed/int64(time.Second)) % period
+	if z < 0 {
+		z += period
+	}
+	return int32(z)
+}
+
+// Crossfade returns the coordinate at time t like At. During the last cell
+// before the coordinate wraps around, it also returns the coordinate where the
+// pattern continues after wrapping (which is slightly negative) with the
+// fraction of the way from z to next, as for Lerp8by8 and Blend. Otherwise,
+// next is equal to z and frac is 0. Blending the noise at z and next hides the
+// jump when the coordinate wraps around:
+//
+//	z, next, frac := nt.Crossfade(t)
+//	n := Noise3(x, y, z)
+//	if frac != 0 {
+//		n += int16((int32(Noise3(x, y, next)) - int32(n)) * int32(frac) >> 8)
+//	}
+func (nt NoiseTime) Crossfade(t time.Duration) (z, next int32, frac uint8) {
+	period := nt.period()
+	z = nt.At(t)
+	if nt.Speed < 0 {
+		// The coordinate wraps around at 0 instead.
+		if z >= 0x1000 {
+			return z, z, 0
+		}
+		return z, int32(int64(z) + period), uint8((0x1000 - z - 1) >> 4)
+	}
+	remaining := period - int64(z)
+	if remaining > 0x1000 {
+		return z, z, 0
+	}
+	return z, int32(int64(z) - period), uint8((0x1000 - remaining) >> 4)
+}
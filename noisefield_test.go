@@ -0,0 +1,66 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNoiseField(t *testing.T) {
+	f := NoiseField{X: 0x1234, Y: -0x5000, Z: 0x777, Zoom: 0x0300, Speed: 0x2000}
+	f.SetTime(1500 * time.Millisecond)
+	if f.time != 0x3000 {
+		t.Fatalf("SetTime: expected time 0x3000, got %#x", f.time)
+	}
+	var row [40]int16
+	f.Fill(row[:])
+	for i, n := range row {
+		if expected := Noise2(0x1234+int32(i)*0x0300, -0x2000); f.At(i) != expected || n != expected {
+			t.Errorf("LED %d: expected %d, got At=%d Fill=%d", i, expected, f.At(i), n)
+		}
+	}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			expected := Noise3(0x1234+int32(x)*0x0300, -0x5000+int32(y)*0x0300, 0x3777)
+			if n := f.AtXY(x, y); n != expected {
+				t.Errorf("LED (%d, %d): expected %d, got %d", x, y, expected, n)
+			}
+		}
+	}
+}
+
+func TestNoiseTime(t *testing.T) {
+	for _, test := range []struct {
+		nt       NoiseTime
+		t        time.Duration
+		expected int32
+	}{
+		{NoiseTime{Speed: 0x1000}, 1500 * time.Millisecond, 0x1800},
+		{NoiseTime{Speed: 0x1000}, 0x10000 * time.Second, 0},
+		{NoiseTime{Speed: 0x1000}, (0x10000 + 3) * time.Second, 0x3000},
+		{NoiseTime{Speed: -0x1000}, time.Second, 0x10000<<12 - 0x1000},
+		{NoiseTime{Speed: 0x100, Period: 16}, 17 * 16 * time.Second, 0x1000},
+		{NoiseTime{Speed: 0x7fffffff}, 1<<63 - 1, 0x7a7ce27}, // no overflow
+		{NoiseTime{Speed: 0x1000, Period: 0x7fffffff}, 0x7ffff * time.Second, 0},
+	} {
+		z := test.nt.At(test.t)
+		if z != test.expected {
+			t.Errorf("%+v.At(%v): expected %#x, got %#x", test.nt, test.t, test.expected, z)
+		}
+	}
+
+	// The noise must be continuous when crossfading around the wrap.
+	for _, nt := range []NoiseTime{{Speed: 0x1000, Period: 64}, {Speed: -0x0800, Period: 32}} {
+		var last int16
+		for ms := 0; ms < 150000; ms++ {
+			z, next, frac := nt.Crossfade(time.Duration(ms) * time.Millisecond)
+			n := Noise3(0x1234, 0x5678, z)
+			if frac != 0 {
+				n += int16((int32(Noise3(0x1234, 0x5678, next)) - int32(n)) * int32(frac) >> 8)
+			}
+			if ms != 0 && abs(int(n)-int(last)) > 1000 {
+				t.Errorf("%+v: jump from %d to %d at %dms (z=%#x, next=%#x, frac=%d)", nt, last, n, ms, z, next, frac)
+			}
+			last = n
+		}
+	}
+}
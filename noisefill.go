@@ -0,0 +1,66 @@
+package ledsgo
+
+// FillNoise1 fills dst with 1D simplex noise, starting at x and stepping by dx
+// for every next sample:
+//
+//	dst[k] = Noise1(x+k*dx)
+//
+// All values are 19.12 fixed-point values, like the input of Noise1. When
+// filling a strip with small steps, most samples lie in the same lattice cell
+// as the previous one, or in the next cell which shares a corner with it. The
+// gradients of these corners are reused instead of being looked up again for
+// every sample. The results are exactly the same as those of Noise1.
+func FillNoise1(dst []int16, x, dx int32) {
+	i0 := x >> 12
+	g0, g1 := noise1Grad(i0), noise1Grad(i0+1)
+	for k := range dst {
+		if i := x >> 12; i != i0 {
+			switch i {
+			case i0 + 1:
+				g0, g1 = g1, noise1Grad(i+1) // the right corner becomes the left corner
+			case i0 - 1:
+				g0, g1 = noise1Grad(i), g0 // the left corner becomes the right corner
+			default:
+				g0, g1 = noise1Grad(i), noise1Grad(i+1)
+			}
+			i0 = i
+		}
+		x0 := x & 0xfff   // .12
+		x1 := x0 - 0x1000 // .12
+
+		// Same calculation as in Noise1.
+		t0 := 0x8000 - (x0*x0)>>9    // .15
+		t0 = (t0 * t0) >> 15         // .15
+		t0 = (t0 * t0) >> 15         // .15
+		n0 := (t0 * (g0 * x0)) >> 12 // .15 * .12 = .15
+		t1 := 0x8000 - (x1*x1)>>9    // .15
+		t1 = (t1 * t1) >> 15         // .15
+		t1 = (t1 * t1) >> 15         // .15
+		n1 := (t1 * (g1 * x1)) >> 12 // .15 * .12 = .15
+
+		dst[k] = int16(divNoise1(n0 + n1 + 2503)) // .15
+		x += dx
+	}
+}
+
+// FillNoise2 fills dst with 2D simplex noise sampled along a line, starting
+// at (x, y) and stepping by (dx, dy) for every next sample:
+//
+//	dst[k] = Noise2(x+k*dx, y+k*dy)
+//
+// All values are 19.12 fixed-point values, like the inputs of Noise2. Filling
+// a whole row of a matrix at once avoids the function call overhead of Noise2
+// and allows the use of SIMD instructions where available (AVX2 on amd64).
+// The results are exactly the same as those of Noise2.
+func FillNoise2(dst []int16, x, y, dx, dy int32) {
+	fillNoise2(dst, x, y, dx, dy)
+}
+
+// fillNoise2Generic is the portable implementation of FillNoise2.
+func fillNoise2Generic(dst []int16, x, y, dx, dy int32) {
+	for i := range dst {
+		dst[i] = Noise2(x, y)
+		x += dx
+		y += dy
+	}
+}
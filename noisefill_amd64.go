@@ -0,0 +1,47 @@
+//go:build amd64 && !tinygo && !purego
+
+package ledsgo
+
+// useAVX2 is true if the CPU and the operating system support AVX2.
+var useAVX2 = hasAVX2()
+
+// perm32 is perm with 32-bit entries, which can be loaded directly into the
+// lanes of a vector register.
+var perm32 [256]int32
+
+func init() {
+	for i := range perm32 {
+		perm32[i] = int32(perm[i])
+	}
+}
+
+func fillNoise2(dst []int16, x, y, dx, dy int32) {
+	if useAVX2 && len(dst) >= 4 && noise2SIMDRange(x, dx, len(dst)) && noise2SIMDRange(y, dy, len(dst)) {
+		// Process blocks of 4 samples with AVX2, and the rest one by one.
+		n := len(dst) &^ 3
+		fillNoise2AVX2(dst[:n], x, y, dx, dy)
+		dst = dst[n:]
+		x += int32(n) * dx
+		y += int32(n) * dy
+	}
+	fillNoise2Generic(dst, x, y, dx, dy)
+}
+
+// noise2SIMDRange returns whether all n values starting at v and stepping by
+// delta stay within ±2**30. In this range, all intermediate values of Noise2
+// are small enough for the AVX2 implementation to return exactly the same
+// result.
+func noise2SIMDRange(v, delta int32, n int) bool {
+	const limit = 1 << 30
+	end := int64(v) + int64(delta)*int64(n-1)
+	return v > -limit && v < limit && end > -limit && end < limit
+}
+
+// hasAVX2 returns whether AVX2 instructions can be used.
+func hasAVX2() bool
+
+// fillNoise2AVX2 implements FillNoise2 using AVX2 instructions. The length of
+// dst must be a multiple of 4.
+//
+//go:noescape
+func fillNoise2AVX2(dst []int16, x, y, dx, dy int32)
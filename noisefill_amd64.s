@@ -0,0 +1,312 @@
+//go:build amd64 && !tinygo && !purego
+
+#include "textflag.h"
+
+// Constants for VPMULDQ, which only uses the low 32 bits of every quadword.
+DATA noiseF2<>+0(SB)/8, $1572067135 // .32: F2
+DATA noiseF2<>+8(SB)/8, $1572067135
+DATA noiseF2<>+16(SB)/8, $1572067135
+DATA noiseF2<>+24(SB)/8, $1572067135
+GLOBL noiseF2<>(SB), RODATA|NOPTR, $32
+
+DATA noiseG2<>+0(SB)/8, $907633384 // .32: G2
+DATA noiseG2<>+8(SB)/8, $907633384
+DATA noiseG2<>+16(SB)/8, $907633384
+DATA noiseG2<>+24(SB)/8, $907633384
+GLOBL noiseG2<>(SB), RODATA|NOPTR, $32
+
+DATA noiseRecip<>+0(SB)/8, $-1259214743 // noise2Reciprocal - 1<<32
+DATA noiseRecip<>+8(SB)/8, $-1259214743
+DATA noiseRecip<>+16(SB)/8, $-1259214743
+DATA noiseRecip<>+24(SB)/8, $-1259214743
+GLOBL noiseRecip<>(SB), RODATA|NOPTR, $32
+
+// Quadword constants.
+DATA noiseCorner2<>+0(SB)/8, $-2479700528 // .32: 2*G2 - 1
+DATA noiseCorner2<>+8(SB)/8, $-2479700528
+DATA noiseCorner2<>+16(SB)/8, $-2479700528
+DATA noiseCorner2<>+24(SB)/8, $-2479700528
+GLOBL noiseCorner2<>(SB), RODATA|NOPTR, $32
+
+DATA noiseOne<>+0(SB)/8, $0x100000000 // .32: 1
+DATA noiseOne<>+8(SB)/8, $0x100000000
+DATA noiseOne<>+16(SB)/8, $0x100000000
+DATA noiseOne<>+24(SB)/8, $0x100000000
+GLOBL noiseOne<>(SB), RODATA|NOPTR, $32
+
+DATA noiseHalf<>+0(SB)/8, $0x80000000 // .32: 0.5
+DATA noiseHalf<>+8(SB)/8, $0x80000000
+DATA noiseHalf<>+16(SB)/8, $0x80000000
+DATA noiseHalf<>+24(SB)/8, $0x80000000
+GLOBL noiseHalf<>(SB), RODATA|NOPTR, $32
+
+// VPERMD indices to move the low or high doublewords of all quadwords to the
+// low 128 bits.
+DATA noiseLow<>+0(SB)/4, $0
+DATA noiseLow<>+4(SB)/4, $2
+DATA noiseLow<>+8(SB)/4, $4
+DATA noiseLow<>+12(SB)/4, $6
+DATA noiseLow<>+16(SB)/4, $0
+DATA noiseLow<>+20(SB)/4, $2
+DATA noiseLow<>+24(SB)/4, $4
+DATA noiseLow<>+28(SB)/4, $6
+GLOBL noiseLow<>(SB), RODATA|NOPTR, $32
+
+DATA noiseHigh<>+0(SB)/4, $1
+DATA noiseHigh<>+4(SB)/4, $3
+DATA noiseHigh<>+8(SB)/4, $5
+DATA noiseHigh<>+12(SB)/4, $7
+DATA noiseHigh<>+16(SB)/4, $1
+DATA noiseHigh<>+20(SB)/4, $3
+DATA noiseHigh<>+24(SB)/4, $5
+DATA noiseHigh<>+28(SB)/4, $7
+GLOBL noiseHigh<>(SB), RODATA|NOPTR, $32
+
+// Doubleword constants.
+DATA noiseLanes<>+0(SB)/4, $0
+DATA noiseLanes<>+4(SB)/4, $1
+DATA noiseLanes<>+8(SB)/4, $2
+DATA noiseLanes<>+12(SB)/4, $3
+GLOBL noiseLanes<>(SB), RODATA|NOPTR, $16
+
+DATA noiseByte<>+0(SB)/8, $0x000000ff000000ff
+DATA noiseByte<>+8(SB)/8, $0x000000ff000000ff
+GLOBL noiseByte<>(SB), RODATA|NOPTR, $16
+
+DATA noiseOnes<>+0(SB)/8, $0x0000000100000001
+DATA noiseOnes<>+8(SB)/8, $0x0000000100000001
+GLOBL noiseOnes<>(SB), RODATA|NOPTR, $16
+
+// VPSHUFB mask to truncate 4 doublewords to 4 words.
+DATA noiseTrunc<>+0(SB)/8, $0x0d0c090805040100
+DATA noiseTrunc<>+8(SB)/8, $0x8080808080808080
+GLOBL noiseTrunc<>(SB), RODATA|NOPTR, $16
+
+// func hasAVX2() bool
+TEXT ·hasAVX2(SB), NOSPLIT, $0-1
+	XORL AX, AX
+	XORL CX, CX
+	CPUID
+	CMPL AX, $7
+	JB   no
+
+	// The CPU must support AVX and the OS must save the YMM registers.
+	MOVL  $1, AX
+	XORL  CX, CX
+	CPUID
+	ANDL  $(1<<27|1<<28), CX // OSXSAVE, AVX
+	CMPL  CX, $(1<<27|1<<28)
+	JNE   no
+	XORL  CX, CX
+	XGETBV
+	ANDL  $6, AX // XMM and YMM state
+	CMPL  AX, $6
+	JNE   no
+
+	MOVL  $7, AX
+	XORL  CX, CX
+	CPUID
+	ANDL  $(1<<5), BX // AVX2
+	JZ    no
+	MOVB  $1, ret+0(FP)
+	RET
+
+no:
+	MOVB $0, ret+0(FP)
+	RET
+
+// Look up the perm32 entries of the 4 indices in idx into dst.
+#define LOOKUP(idx, dst) \
+	VMOVD   idx, AX; \
+	VPEXTRD $1, idx, BX; \
+	VPEXTRD $2, idx, DX; \
+	VPEXTRD $3, idx, R10; \
+	VMOVD   (SI)(AX*4), dst; \
+	VPINSRD $1, (SI)(BX*4), dst, dst; \
+	VPINSRD $2, (SI)(DX*4), dst, dst; \
+	VPINSRD $3, (SI)(R10*4), dst, dst
+
+// Calculate the perm hash of the cells at (X3, X4) into X4, where X3 and X4
+// hold the i and j cell coordinates of 4 samples. Clobbers X3 and X6.
+#define HASH \
+	VPAND      noiseByte<>(SB), X4, X4; \
+	LOOKUP(X4, X6); \
+	VPADDD     X6, X3, X3; \
+	VPAND      noiseByte<>(SB), X3, X3; \
+	LOOKUP(X3, X4)
+
+// Add the contribution of the corner at distance (Y0, Y1) and with hash X4 to
+// X9. The gradient is calculated instead of looked up in grad2Table: bit 2
+// of the hash swaps x and y, bit 0 and 1 negate them. Instead of skipping
+// the corner when t <= 0, t is clamped to 0 which results in the same zero
+// contribution. As t is then at most 0x8000, it can be squared with the
+// (faster) 16-bit VPMULHUW. Clobbers X2-X8.
+#define CORNER \
+	VPSRLQ     $16, Y0, Y6; \
+	VPMULDQ    Y6, Y6, Y6; \
+	VPSRLQ     $16, Y1, Y7; \
+	VPMULDQ    Y7, Y7, Y7; \
+	VPADDQ     Y7, Y6, Y6; \
+	VMOVDQU    noiseHalf<>(SB), Y7; \
+	VPSUBQ     Y6, Y7, Y6; \
+	VPSRLQ     $16, Y6, Y6; \
+	VPERMD     Y6, Y10, Y6; \
+	VPXOR      X7, X7, X7; \
+	VPMAXSD    X7, X6, X6; \
+	VPMULHUW   X6, X6, X6; \
+	VPMULHUW   X6, X6, X6; \
+	VPSRLD     $1, X6, X6; \
+	VPSRLQ     $17, Y0, Y7; \
+	VPERMD     Y7, Y10, Y7; \
+	VPSRLQ     $17, Y1, Y8; \
+	VPERMD     Y8, Y10, Y8; \
+	VPSLLD     $29, X4, X5; \
+	VPSRAD     $31, X5, X5; \
+	VPBLENDVB  X5, X8, X7, X2; \
+	VPBLENDVB  X5, X7, X8, X3; \
+	VPSLLD     $31, X4, X5; \
+	VPSRAD     $31, X5, X5; \
+	VPXOR      X5, X2, X2; \
+	VPSUBD     X5, X2, X2; \
+	VPSLLD     $30, X4, X5; \
+	VPSRAD     $31, X5, X5; \
+	VPXOR      X5, X3, X3; \
+	VPSUBD     X5, X3, X3; \
+	VPSLLD     $1, X3, X3; \
+	VPADDD     X3, X2, X7; \
+	VPMULLD    X7, X6, X6; \
+	VPADDD     X6, X9, X9
+
+// func fillNoise2AVX2(dst []int16, x, y, dx, dy int32)
+//
+// This is a direct translation of Noise2 that processes 4 samples at a time.
+// Values that are int32 in Noise2 are kept in doublewords in the X registers,
+// int64 values in quadwords in the Y registers. Since the calculation must be
+// exactly the same, all 32-bit operations wrap around like they do in Go.
+TEXT ·fillNoise2AVX2(SB), NOSPLIT, $128-40
+	MOVQ dst_base+0(FP), DI
+	MOVQ dst_len+8(FP), CX
+	SHRQ $2, CX
+	JZ   done
+	LEAQ ·perm32(SB), SI
+
+	// X14 and X15 are the x and y coordinates of the next 4 samples, X13 and
+	// X12 are the steps to the 4 samples after that.
+	MOVL         x+24(FP), AX
+	VMOVD        AX, X14
+	VPBROADCASTD X14, X14
+	MOVL         dx+32(FP), AX
+	VMOVD        AX, X13
+	VPBROADCASTD X13, X13
+	VPMULLD      noiseLanes<>(SB), X13, X0
+	VPADDD       X0, X14, X14
+	VPSLLD       $2, X13, X13
+	MOVL         y+28(FP), AX
+	VMOVD        AX, X15
+	VPBROADCASTD X15, X15
+	MOVL         dy+36(FP), AX
+	VMOVD        AX, X12
+	VPBROADCASTD X12, X12
+	VPMULLD      noiseLanes<>(SB), X12, X0
+	VPADDD       X0, X15, X15
+	VPSLLD       $2, X12, X12
+	VMOVDQU      noiseLow<>(SB), Y10
+	VMOVDQU      noiseHigh<>(SB), Y11
+
+loop:
+	// Skew the input space to determine which simplex cell we're in.
+	VPMOVSXDQ X14, Y0
+	VPMOVSXDQ X15, Y1
+	VPMULDQ   noiseF2<>(SB), Y0, Y2
+	VPMULDQ   noiseF2<>(SB), Y1, Y3
+	VPADDQ    Y3, Y2, Y2
+	VPERMD    Y2, Y11, Y2 // s
+	VPSRAD    $1, X2, X4
+	VPSRAD    $1, X14, X3
+	VPADDD    X4, X3, X3
+	VPSRAD    $11, X3, X3 // i
+	VPSRAD    $1, X15, X5
+	VPADDD    X4, X5, X5
+	VPSRAD    $11, X5, X5 // j
+	VMOVDQU   X3, 0(SP)
+	VMOVDQU   X5, 16(SP)
+
+	// Distances from the cell origin.
+	VPMOVSXDQ X3, Y6
+	VPMOVSXDQ X5, Y7
+	VPMULDQ   noiseG2<>(SB), Y6, Y8
+	VPMULDQ   noiseG2<>(SB), Y7, Y9
+	VPADDQ    Y9, Y8, Y8 // t
+	VPSLLQ    $20, Y0, Y0
+	VPSLLQ    $32, Y6, Y6
+	VPSUBQ    Y6, Y0, Y0
+	VPADDQ    Y8, Y0, Y0 // x0
+	VPSLLQ    $20, Y1, Y1
+	VPSLLQ    $32, Y7, Y7
+	VPSUBQ    Y7, Y1, Y1
+	VPADDQ    Y8, Y1, Y1 // y0
+	VPCMPGTQ  Y1, Y0, Y2 // x0 > y0
+	VMOVDQU   Y0, 32(SP)
+	VMOVDQU   Y1, 64(SP)
+	VMOVDQU   Y2, 96(SP)
+
+	// First corner.
+	VPXOR   X9, X9, X9
+	VMOVDQU 16(SP), X4
+	HASH
+	CORNER
+
+	// Middle corner.
+	VMOVDQU 96(SP), Y2
+	VPAND   noiseOne<>(SB), Y2, Y6
+	VPSUBQ  Y6, Y0, Y0
+	VPADDQ  noiseG2<>(SB), Y0, Y0 // x1
+	VMOVDQU 64(SP), Y1
+	VPANDN  noiseOne<>(SB), Y2, Y6
+	VPSUBQ  Y6, Y1, Y1
+	VPADDQ  noiseG2<>(SB), Y1, Y1 // y1
+	VPERMD  Y2, Y10, Y2           // -1 if x0 > y0, else 0
+	VMOVDQU 0(SP), X3
+	VPSUBD  X2, X3, X3            // i + i1
+	VMOVDQU 16(SP), X4
+	VPADDD  X2, X4, X4
+	VPADDD  noiseOnes<>(SB), X4, X4 // j + j1
+	HASH
+	CORNER
+
+	// Last corner.
+	VMOVDQU 32(SP), Y0
+	VPADDQ  noiseCorner2<>(SB), Y0, Y0 // x2
+	VMOVDQU 64(SP), Y1
+	VPADDQ  noiseCorner2<>(SB), Y1, Y1 // y2
+	VMOVDQU 0(SP), X3
+	VPADDD  noiseOnes<>(SB), X3, X3
+	VMOVDQU 16(SP), X4
+	VPADDD  noiseOnes<>(SB), X4, X4
+	HASH
+	CORNER
+
+	// Same as divNoise2. The reciprocal doesn't fit in a signed doubleword,
+	// so multiply by noise2Reciprocal - 1<<32 and add n<<32.
+	VPMOVSXDQ X9, Y0
+	VPMULDQ   noiseRecip<>(SB), Y0, Y1
+	VPSLLQ    $32, Y0, Y0
+	VPADDQ    Y0, Y1, Y1
+	VPSRLQ    $41, Y1, Y1
+	VPERMD    Y1, Y10, Y1
+	VPSLLD    $9, X1, X1
+	VPSRAD    $9, X1, X1 // sign extend the 23-bit result
+	VPSRAD    $31, X9, X2
+	VPSUBD    X2, X1, X1
+	VPSHUFB   noiseTrunc<>(SB), X1, X1
+	VMOVQ     X1, (DI)
+
+	ADDQ   $8, DI
+	VPADDD X13, X14, X14
+	VPADDD X12, X15, X15
+	DECQ   CX
+	JNZ    loop
+	VZEROUPPER
+
+done:
+	RET
@@ -0,0 +1,7 @@
+//go:build !amd64 || tinygo || purego
+
+package ledsgo
+
+func fillNoise2(dst []int16, x, y, dx, dy int32) {
+	fillNoise2Generic(dst, x, y, dx, dy)
+}
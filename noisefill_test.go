@@ -0,0 +1,106 @@
+package ledsgo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFillNoise1(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	buf := make([]int16, 64)
+	for i := 0; i < 20000; i++ {
+		n := r.Intn(len(buf) + 1)
+		x := int32(r.Uint32())
+		dx := int32(r.Intn(1<<14) - 1<<13)
+		switch i % 4 {
+		case 0:
+			// Small steps, in both directions.
+			dx >>= 4
+		case 1:
+			// Large steps.
+			dx <<= 10
+		case 2:
+			// Wrap around at the edge of the int32 range.
+			x = 1<<31 - 1 - int32(r.Intn(1<<16))
+			dx = int32(r.Intn(1 << 12))
+		}
+		dst := buf[:n]
+		FillNoise1(dst, x, dx)
+		for k, v := range dst {
+			sx := x + int32(k)*dx
+			if expected := Noise1(sx); v != expected {
+				t.Fatalf("FillNoise1(%d, %d)[%d] = %d, Noise1(%d) = %d", x, dx, k, v, sx, expected)
+			}
+		}
+	}
+}
+
+func TestFillNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	buf := make([]int16, 64)
+	for i := 0; i < 20000; i++ {
+		n := r.Intn(len(buf) + 1)
+		x, y := int32(r.Uint32()), int32(r.Uint32())
+		dx, dy := int32(r.Intn(1<<14)-1<<13), int32(r.Intn(1<<14)-1<<13)
+		switch i % 4 {
+		case 0:
+			// Stay in the range of typical coordinates.
+			x >>= 8
+			y >>= 8
+		case 1:
+			// Close to the edge of the SIMD range.
+			x >>= 1
+			y >>= 1
+		case 2:
+			// Large steps.
+			x >>= 4
+			y >>= 4
+			dx <<= 10
+			dy <<= 10
+		}
+		dst := buf[:n]
+		FillNoise2(dst, x, y, dx, dy)
+		for k, v := range dst {
+			sx, sy := x+int32(k)*dx, y+int32(k)*dy
+			if expected := Noise2(sx, sy); v != expected {
+				t.Fatalf("FillNoise2(%d, %d, %d, %d)[%d] = %d, Noise2(%d, %d) = %d", x, y, dx, dy, k, v, sx, sy, expected)
+			}
+		}
+	}
+}
+
+func BenchmarkFillNoise1(b *testing.B) {
+	buf := make([]int16, 1024)
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		FillNoise1(buf, int32(i)<<8, 1<<8)
+	}
+}
+
+func BenchmarkFillNoise1Loop(b *testing.B) {
+	buf := make([]int16, 1024)
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		x := int32(i) << 8
+		for k := range buf {
+			buf[k] = Noise1(x)
+			x += 1 << 8
+		}
+	}
+}
+
+func BenchmarkFillNoise2(b *testing.B) {
+	buf := make([]int16, 1024)
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		FillNoise2(buf, 0, int32(i)<<8, 1<<8, 0)
+	}
+}
+
+func BenchmarkFillNoise2Generic(b *testing.B) {
+	buf := make([]int16, 1024)
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		fillNoise2Generic(buf, 0, int32(i)<<8, 1<<8, 0)
+	}
+}
@@ -0,0 +1,100 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// NoiseFire is an Effect that shows flames on a LED matrix. Instead of
+// simulating the heat of every pixel like Fire2012, the flames are a slice of
+// 3D noise that scrolls upwards, so that they flicker and change shape
+// without any state between frames. The heat fades out towards the top of the
+// matrix.
+//
+// The frame is treated as a Canvas of the given size: the LEDs are row by
+// row, starting at the top left.
+type NoiseFire struct {
+	Width, Height int
+
+	// Intensity is the height of the flames: at 128 they just reach the top
+	// of the matrix, at 0 they reach half of it and at 255 they go off the
+	// top.
+	Intensity uint8
+
+	// Wind blows the flames sideways, to the right for positive values. At 64
+	// the flames lean over by one pixel per row.
+	Wind int8
+
+	// Palette is the color of the flames from cold (index 0) to hot (index
+	// 240). If it is nil, HeatColor is used.
+	Palette *Palette16
+}
+
+// fireTime is the time axis of NoiseFire. The period keeps the coordinates
+// derived from it within the range of an int32.
+var fireTime = NoiseTime{Speed: 0x800, Period: 0x4000}
+
+// NewNoiseFire returns a new fire effect for a matrix of the given size, with
+// flames that just reach the top and no wind.
+func NewNoiseFire(width, height int) *NoiseFire {
+	return &NoiseFire{
+		Width:     width,
+		Height:    height,
+		Intensity: 128,
+	}
+}
+
+// SetStandardParams implements StandardEffect. The intensity is the height of
+// the flames, and the palette their color (HeatColor if it is nil).
+func (f *NoiseFire) SetStandardParams(params StandardParams) {
+	f.Intensity = params.Intensity
+	f.Palette = params.Palette
+}
+
+// Params implements ParamEffect.
+func (f *NoiseFire) Params() []Param {
+	return []Param{
+		{Name: "Intensity", Min: 0, Max: 255, Default: 128, Value: &f.Intensity},
+		{Name: "Wind", Min: -128, Max: 127, Default: 0, Value: &f.Wind},
+	}
+}
+
+// Render implements Effect.
+func (f *NoiseFire) Render(frame Strip, t time.Duration) {
+	canvas := frameCanvas(frame, f.Width, f.Height)
+	if canvas.Height == 0 {
+		return
+	}
+
+	// One noise unit is four pixels. The noise changes at half a noise unit
+	// every second, the flames rise at eight pixels per second and the wind
+	// moves them at a pixel per second for every 8 of wind force. All of them
+	// are derived from the same time coordinate, so that they wrap around at
+	// the same time and the jump can be hidden with a crossfade.
+	z, next, frac := fireTime.Crossfade(t)
+	coords := func(z int32) (rise, drift int32) {
+		return z * 4, int32(int64(f.Wind) * int64(z) >> 4) // .12
+	}
+	rise, drift := coords(z)
+	nextRise, nextDrift := coords(next)
+	reach := uint32(canvas.Height) * (uint32(f.Intensity)/2 + 64)
+	for y := 0; y < canvas.Height; y++ {
+		// The heat of a row is 255 at the bottom, and fades out with a
+		// steepness that depends on the intensity.
+		above := canvas.Height - 1 - y // rows above the bottom row
+		level := uint8(max(255-int32(uint32(above)*255*128/reach), 0))
+		lean := int32(f.Wind) * int32(above) << 10 / 64 // .12
+		for x := 0; x < canvas.Width; x++ {
+			n := Noise3(int32(x)<<10-lean-drift, int32(y)<<10+rise, z)
+			if frac != 0 {
+				n2 := Noise3(int32(x)<<10-lean-nextDrift, int32(y)<<10+nextRise, next)
+				n += int16((int32(n2) - int32(n)) * int32(frac) >> 8)
+			}
+			heat := QAdd8(Scale8(uint8(uint16(n)>>8^0x80), level), level/2)
+			if f.Palette != nil {
+				canvas.Pix[y*canvas.Width+x] = f.Palette.ColorAt(Scale8(heat, 240))
+			} else {
+				canvas.Pix[y*canvas.Width+x] = HeatColor(heat)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNoiseFire(t *testing.T) {
+	const width, height = 16, 16
+	f := NewNoiseFire(width, height)
+	frame := make(Strip, width*height)
+	c := &Canvas{Width: width, Height: height, Pix: frame}
+	rowLight := func(y int) (total int) {
+		for x := 0; x < width; x++ {
+			col := c.RGBAAt(x, y)
+			total += int(col.R) + int(col.G) + int(col.B)
+		}
+		return total
+	}
+
+	// The fire is hot at the bottom and fades out towards the top.
+	var bottom, top int
+	for ts := time.Duration(0); ts < 2*time.Second; ts += 100 * time.Millisecond {
+		f.Render(frame, ts)
+		bottom += rowLight(height - 1)
+		top += rowLight(0)
+		for _, col := range frame {
+			if col.B > col.G || col.G > col.R {
+				t.Fatalf("expected fire colors, got %v", col)
+			}
+		}
+	}
+	if bottom < 20*width*255 || top > bottom/8 {
+		t.Errorf("expected a hot bottom and a cool top, got %d and %d", bottom, top)
+	}
+
+	// It doesn't reach as high at a low intensity.
+	f.Intensity = 0
+	f.Render(frame, time.Second)
+	for y := 0; y < height/2; y++ {
+		if rowLight(y) != 0 {
+			t.Fatalf("intensity 0: expected the top half to be dark, row %d is lit", y)
+		}
+	}
+
+	// The flames change over time and with the wind.
+	f.Intensity = 128
+	f.Render(frame, time.Second)
+	still := append(Strip(nil), frame...)
+	f.Wind = 64
+	f.Render(frame, time.Second)
+	changed := false
+	for i := range frame {
+		if frame[i] != still[i] {
+			changed = true
+		}
+	}
+	if !changed {
+		t.Error("expected the wind to change the flames")
+	}
+	// At the bottom row the flames don't lean, so only the drift moves them.
+	f.Render(frame, 0)
+	f.Wind = 0
+	bottomRow := append(Strip(nil), frame[(height-1)*width:]...)
+	f.Render(frame, 0)
+	for i, col := range frame[(height-1)*width:] {
+		if col != bottomRow[i] {
+			t.Fatalf("expected the bottom row not to lean, got %v and %v", bottomRow, frame[(height-1)*width:])
+		}
+	}
+
+	f.Palette = &OceanColors
+	f.Render(frame, 0)
+	if col := c.RGBAAt(0, height-1); col.B < col.R {
+		t.Errorf("expected the palette to be used, got %v", col)
+	}
+
+	if n := testing.AllocsPerRun(10, func() { f.Render(frame, 3*time.Second) }); n != 0 {
+		t.Errorf("expected no allocations, got %.1f", n)
+	}
+}
+
+func TestNoiseFireLongUptime(t *testing.T) {
+	// The flames must not jump after a long uptime, including when the time
+	// axis wraps around.
+	f := NewNoiseFire(16, 16)
+	f.Wind = -128
+	frame := make(Strip, 16*16)
+	prev := make(Strip, len(frame))
+	wrap := 0x4000 * time.Second / 0x800 * 0x1000 // period of fireTime
+	for _, start := range []time.Duration{3 * 24 * time.Hour, 14 * 24 * time.Hour, wrap - time.Second, 5*wrap - time.Second} {
+		f.Render(prev, start)
+		for ts := start; ts < start+2*time.Second; ts += 20 * time.Millisecond {
+			f.Render(frame, ts)
+			for i := range frame {
+				if d := abs(int(frame[i].R) - int(prev[i].R)); d > 64 {
+					t.Fatalf("%v: the flames jumped by %d at LED %d", ts, d, i)
+				}
+			}
+			copy(prev, frame)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// Palette16 is a color palette with 16 entries, like CRGBPalette16 in FastLED.
+// Palettes are a cheap way to give effects a consistent look: an effect
+// calculates an index (for example using noise) and looks up the color in the
+// palette, so the same effect can look very different with another palette.
+type Palette16 [16]color.RGBA
+
+// ColorAt returns the color at the given position in the palette. Colors
+// between palette entries are linearly interpolated, and the last entry blends
+// back into the first so that the palette wraps around smoothly.
+func (p *Palette16) ColorAt(index uint8) color.RGBA {
+	i := index >> 4
+	frac := index & 0x0f
+	c := p[i]
+	if frac == 0 {
+		return c
+	}
+	return Blend(c, p[(i+1)%16], frac<<4)
+}
+
+// NewPalette16 returns a palette with a gradient through the given colors,
+// spread evenly over the 16 palette entries. This is the same as the
+// CRGBPalette16 constructors in FastLED that take 2, 3 or 4 colors, but it
+// accepts any number of colors. With more than 16 colors, some colors are
+// skipped.
+func NewPalette16(colors ...color.RGBA) Palette16 {
+	var p Palette16
+	fillPalette(p[:], colors)
+	return p
+}
+
+// Blend mixes the colors of other into the palette, like Blend: a frac of 0
+// keeps the palette as it is, 255 replaces it with (almost) other. Blending a
+// little every frame gives a smooth transition from one palette to another.
+func (p *Palette16) Blend(other *Palette16, frac uint8) {
+	for i := range p {
+		p[i] = Blend(p[i], other[i], frac)
+	}
+}
+
+// Palette returns the palette as a Palette of 16 entries. It shares the colors
+// with p, so changes to one are visible in the other.
+func (p *Palette16) Palette() Palette {
+	return p[:]
+}
+
+// ReversePalette returns the palette with the entries in reverse order, so
+// that an effect runs through the colors the other way around.
+func ReversePalette(p *Palette16) Palette16 {
+	var r Palette16
+	for i, c := range p {
+		r[len(r)-1-i] = c
+	}
+	return r
+}
+
+// MirrorPalette returns a symmetric palette that runs through all entries of p
+// in the first half and back in the second half: index 0 has the first color
+// of p, index 128 the last color of p, and from there it goes back to the
+// first color. This is useful for effects that start in the center and go
+// outwards in both directions, and for palettes that must wrap around
+// smoothly.
+func MirrorPalette(p *Palette16) Palette16 {
+	var m Palette16
+	fillPalette(m[:len(m)/2+1], p[:])
+	for i := 1; i < len(m)/2; i++ {
+		m[len(m)-i] = m[i]
+	}
+	return m
+}
+
+// Palette is a color palette with any number of entries. Palette16 is
+// usually enough, as effects tend to use saturated colors that are far apart,
+// but it is too coarse for very smooth gradients over many LEDs. A Palette of
+// 256 entries on the other hand takes a lot of RAM on small microcontrollers,
+// so choose the smallest size that looks good.
+type Palette []color.RGBA
+
+// NewPalette returns a palette of the given size with a gradient through the
+// given colors, see NewPalette16.
+func NewPalette(size int, colors ...color.RGBA) Palette {
+	p := make(Palette, size)
+	fillPalette(p, colors)
+	return p
+}
+
+// ColorAt returns the color at the given position in the palette, where the
+// whole range of an uint16 covers all entries. Colors between palette entries
+// are linearly interpolated, and the last entry blends back into the first so
+// that the palette wraps around smoothly. An empty palette returns black.
+func (p Palette) ColorAt(index uint16) color.RGBA {
+	if len(p) == 0 {
+		return color.RGBA{}
+	}
+	pos := uint32(index) * uint32(len(p)) // .16
+	i := int(pos >> 16)
+	c := p[i]
+	if frac := uint8(pos >> 8); frac != 0 {
+		c = Blend(c, p[(i+1)%len(p)], frac)
+	}
+	return c
+}
+
+// fillPalette fills p with a gradient through the given colors, spreading the
+// colors evenly over the palette entries.
+func fillPalette(p []color.RGBA, colors []color.RGBA) {
+	if len(colors) == 0 {
+		return
+	}
+	if len(p) == 1 {
+		p[0] = colors[0]
+		return
+	}
+	for i := range p {
+		pos := i * (len(colors) - 1) * 256 / (len(p) - 1) // .8
+		c := colors[pos>>8]
+		if frac := uint8(pos); frac != 0 {
+			c = Blend(c, colors[pos>>8+1], frac)
+		}
+		p[i] = c
+	}
+}
+
+// The palettes below are the same as the predefined palettes in FastLED.
+
+// RainbowColors is a rainbow with all hues, like FillSpectrum.
+var RainbowColors = Palette16{
+	{0xff, 0x00, 0x00, 0},
+	{0xd5, 0x2a, 0x00, 0},
+	{0xab, 0x55, 0x00, 0},
+	{0xab, 0x7f, 0x00, 0},
+	{0xab, 0xab, 0x00, 0},
+	{0x56, 0xd5, 0x00, 0},
+	{0x00, 0xff, 0x00, 0},
+	{0x00, 0xd5, 0x2a, 0},
+	{0x00, 0xab, 0x55, 0},
+	{0x00, 0x56, 0xaa, 0},
+	{0x00, 0x00, 0xff, 0},
+	{0x2a, 0x00, 0xd5, 0},
+	{0x55, 0x00, 0xab, 0},
+	{0x7f, 0x00, 0x81, 0},
+	{0xab, 0x00, 0x55, 0},
+	{0xd5, 0x00, 0x2b, 0},
+}
+
+// PartyColors is a rainbow without the greens, for a party look.
+var PartyColors = Palette16{
+	{0x55, 0x00, 0xab, 0},
+	{0x84, 0x00, 0x7c, 0},
+	{0xb5, 0x00, 0x4b, 0},
+	{0xe5, 0x00, 0x1b, 0},
+	{0xe8, 0x17, 0x00, 0},
+	{0xb8, 0x47, 0x00, 0},
+	{0xab, 0x77, 0x00, 0},
+	{0xab, 0xab, 0x00, 0},
+	{0xab, 0x55, 0x00, 0},
+	{0xdd, 0x22, 0x00, 0},
+	{0xf2, 0x00, 0x0e, 0},
+	{0xc2, 0x00, 0x3e, 0},
+	{0x8f, 0x00, 0x71, 0},
+	{0x5f, 0x00, 0xa1, 0},
+	{0x2f, 0x00, 0xd0, 0},
+	{0x00, 0x07, 0xf9, 0},
+}
+
+// LavaColors is dark reds with orange and white highlights.
+var LavaColors = Palette16{
+	{0x00, 0x00, 0x00, 0},
+	{0x80, 0x00, 0x00, 0},
+	{0x00, 0x00, 0x00, 0},
+	{0x80, 0x00, 0x00, 0},
+	{0x8b, 0x00, 0x00, 0},
+	{0x8b, 0x00, 0x00, 0},
+	{0x80, 0x00, 0x00, 0},
+	{0x8b, 0x00, 0x00, 0},
+	{0x8b, 0x00, 0x00, 0},
+	{0x8b, 0x00, 0x00, 0},
+	{0xff, 0x00, 0x00, 0},
+	{0xff, 0xa5, 0x00, 0},
+	{0xff, 0xff, 0xff, 0},
+	{0xff, 0xa5, 0x00, 0},
+	{0xff, 0x00, 0x00, 0},
+	{0x8b, 0x00, 0x00, 0},
+}
+
+// OceanColors is blues and greens, like an ocean.
+var OceanColors = Palette16{
+	{0x19, 0x19, 0x70, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x19, 0x19, 0x70, 0},
+	{0x00, 0x00, 0x80, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x00, 0x00, 0xcd, 0},
+	{0x2e, 0x8b, 0x57, 0},
+	{0x00, 0x80, 0x80, 0},
+	{0x5f, 0x9e, 0xa0, 0},
+	{0x00, 0x00, 0xff, 0},
+	{0x00, 0x8b, 0x8b, 0},
+	{0x64, 0x95, 0xed, 0},
+	{0x7f, 0xff, 0xd4, 0},
+	{0x2e, 0x8b, 0x57, 0},
+	{0x00, 0xff, 0xff, 0},
+	{0x87, 0xce, 0xfa, 0},
+}
+
+// ForestColors is greens, like a forest.
+var ForestColors = Palette16{
+	{0x00, 0x64, 0x00, 0},
+	{0x00, 0x64, 0x00, 0},
+	{0x55, 0x6b, 0x2f, 0},
+	{0x00, 0x64, 0x00, 0},
+	{0x00, 0x80, 0x00, 0},
+	{0x22, 0x8b, 0x22, 0},
+	{0x6b, 0x8e, 0x23, 0},
+	{0x00, 0x80, 0x00, 0},
+	{0x2e, 0x8b, 0x57, 0},
+	{0x66, 0xcd, 0xaa, 0},
+	{0x32, 0xcd, 0x32, 0},
+	{0x9a, 0xcd, 0x32, 0},
+	{0x90, 0xee, 0x90, 0},
+	{0x7c, 0xfc, 0x00, 0},
+	{0x66, 0xcd, 0xaa, 0},
+	{0x22, 0x8b, 0x22, 0},
+}
+
+// CloudColors is blues and white, like clouds in the sky.
+var CloudColors = Palette16{
+	{0x00, 0x00, 0xff, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x00, 0x00, 0xff, 0},
+	{0x00, 0x00, 0x8b, 0},
+	{0x87, 0xce, 0xeb, 0},
+	{0x87, 0xce, 0xeb, 0},
+	{0xad, 0xd8, 0xe6, 0},
+	{0xff, 0xff, 0xff, 0},
+	{0xad, 0xd8, 0xe6, 0},
+	{0x87, 0xce, 0xeb, 0},
+}
+
+// HeatColors is black through red and yellow to white, like fire.
+var HeatColors = Palette16{
+	{0x00, 0x00, 0x00, 0},
+	{0x33, 0x00, 0x00, 0},
+	{0x66, 0x00, 0x00, 0},
+	{0x99, 0x00, 0x00, 0},
+	{0xcc, 0x00, 0x00, 0},
+	{0xff, 0x00, 0x00, 0},
+	{0xff, 0x33, 0x00, 0},
+	{0xff, 0x66, 0x00, 0},
+	{0xff, 0x99, 0x00, 0},
+	{0xff, 0xcc, 0x00, 0},
+	{0xff, 0xff, 0x00, 0},
+	{0xff, 0xff, 0x33, 0},
+	{0xff, 0xff, 0x66, 0},
+	{0xff, 0xff, 0x99, 0},
+	{0xff, 0xff, 0xcc, 0},
+	{0xff, 0xff, 0xff, 0},
+}
+
+// HeatColor returns the color of the given temperature, going from black
+// through red and yellow to (nearly) white. It is the same ramp as HeatColor
+// in FastLED, so that the Fire2012 effect family can be ported as-is, but it
+// is also useful on its own, for example to show temperatures. HeatColors is
+// a palette with nearly the same colors.
+func HeatColor(heat uint8) color.RGBA {
+	// Scale the heat down to 0..191, so that it can be split in three equal
+	// parts: red, then yellow, then white. Non-zero temperatures are never
+	// black.
+	t192 := Scale8Video(heat, 191)
+	ramp := (t192 & 0x3f) << 2 // 0..252 within every part
+	switch {
+	case t192&0x80 != 0:
+		return color.RGBA{255, 255, ramp, 0}
+	case t192&0x40 != 0:
+		return color.RGBA{255, ramp, 0, 0}
+	default:
+		return color.RGBA{ramp, 0, 0, 0}
+	}
+}
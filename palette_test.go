@@ -0,0 +1,161 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestNewPalette16(t *testing.T) {
+	red := color.RGBA{R: 255}
+	blue := color.RGBA{B: 255}
+	p := NewPalette16(red, blue)
+	if p[0] != red || p[15] != blue {
+		t.Errorf("expected the gradient to start and end with the given colors, got %v and %v", p[0], p[15])
+	}
+	for i := 1; i < len(p); i++ {
+		if p[i].R > p[i-1].R || p[i].B < p[i-1].B {
+			t.Errorf("entry %d: gradient is not monotonic: %v after %v", i, p[i], p[i-1])
+		}
+	}
+
+	// Exactly 16 colors are copied as-is.
+	var colors []color.RGBA
+	for i := 0; i < 16; i++ {
+		colors = append(colors, color.RGBA{R: uint8(i * 10)})
+	}
+	p = NewPalette16(colors...)
+	for i, c := range p {
+		if c != colors[i] {
+			t.Errorf("entry %d: expected %v, got %v", i, colors[i], c)
+		}
+	}
+
+	p = NewPalette16(red)
+	for i, c := range p {
+		if c != red {
+			t.Errorf("entry %d: expected %v, got %v", i, red, c)
+		}
+	}
+}
+
+func TestPaletteColorAt(t *testing.T) {
+	p := NewPalette16(color.RGBA{R: 255}, color.RGBA{B: 255})
+	for i := 0; i < 16; i++ {
+		if c := p.ColorAt(uint8(i << 4)); c != p[i] {
+			t.Errorf("index %#x: expected %v, got %v", i<<4, p[i], c)
+		}
+	}
+	// Between two entries, the colors are blended.
+	for _, index := range []uint8{0x01, 0x08, 0x4c, 0xef} {
+		i := index >> 4
+		if c, expected := p.ColorAt(index), Blend(p[i], p[i+1], index<<4); c != expected {
+			t.Errorf("index %#x: expected %v, got %v", index, expected, c)
+		}
+	}
+	// Halfway between the last and the first entry.
+	if c := p.ColorAt(0xf8); c.R < 0x70 || c.R > 0x90 || c.B < 0x70 || c.B > 0x90 {
+		t.Errorf("expected the palette to wrap around, got %v", c)
+	}
+}
+
+func TestHeatColor(t *testing.T) {
+	// Values from FastLED.
+	for _, tc := range []struct {
+		heat uint8
+		c    color.RGBA
+	}{
+		{0, color.RGBA{}},
+		{1, color.RGBA{4, 0, 0, 0}},
+		{64, color.RGBA{192, 0, 0, 0}},
+		{84, color.RGBA{252, 0, 0, 0}},
+		{85, color.RGBA{255, 0, 0, 0}},
+		{128, color.RGBA{255, 128, 0, 0}},
+		{200, color.RGBA{255, 255, 88, 0}},
+		{255, color.RGBA{255, 255, 252, 0}},
+	} {
+		if c := HeatColor(tc.heat); c != tc.c {
+			t.Errorf("HeatColor(%d): expected %v, got %v", tc.heat, tc.c, c)
+		}
+	}
+
+	// The ramp only gets brighter.
+	prev := HeatColor(0)
+	for heat := 1; heat < 256; heat++ {
+		c := HeatColor(uint8(heat))
+		if c.R < prev.R || c.G < prev.G || c.B < prev.B {
+			t.Errorf("HeatColor(%d): %v is darker than %v", heat, c, prev)
+		}
+		prev = c
+	}
+}
+
+func TestPalette(t *testing.T) {
+	red, blue := color.RGBA{R: 255}, color.RGBA{B: 255}
+	p := NewPalette(64, red, blue)
+	if len(p) != 64 || p[0] != red || p[63] != blue {
+		t.Fatalf("unexpected palette: %v", p)
+	}
+	for i := range p {
+		if c := p.ColorAt(uint16(i * 1024)); c != p[i] {
+			t.Errorf("index %#x: expected %v, got %v", i*1024, p[i], c)
+		}
+	}
+
+	// Halfway between two entries, and between the last and the first entry.
+	if c, expected := p.ColorAt(10*1024+512), Blend(p[10], p[11], 128); c != expected {
+		t.Errorf("expected %v between two entries, got %v", expected, c)
+	}
+	if c := p.ColorAt(0xfe00); c != Blend(blue, red, 128) {
+		t.Errorf("expected the palette to wrap around, got %v", c)
+	}
+
+	// A Palette16 works the same as a Palette of 16 entries.
+	p16 := NewPalette16(red, blue, color.RGBA{G: 255})
+	if p16.Palette()[5] != NewPalette(16, red, blue, color.RGBA{G: 255})[5] {
+		t.Error("NewPalette16 and NewPalette differ")
+	}
+	for i := 0; i < 256; i++ {
+		if a, b := p16.ColorAt(uint8(i)), p16.Palette().ColorAt(uint16(i)<<8); a != b {
+			t.Errorf("index %d: Palette16 returns %v, Palette returns %v", i, a, b)
+		}
+	}
+
+	if c := NewPalette(1, red, blue).ColorAt(0x1234); c != red {
+		t.Errorf("single entry: expected %v, got %v", red, c)
+	}
+	if c := Palette(nil).ColorAt(0x1234); c != (color.RGBA{}) {
+		t.Errorf("empty palette: expected black, got %v", c)
+	}
+}
+
+func TestMirrorPalette(t *testing.T) {
+	var p Palette16
+	for i := range p {
+		p[i] = color.RGBA{R: uint8(i * 17)}
+	}
+	r := ReversePalette(&p)
+	for i, c := range r {
+		if c != p[15-i] {
+			t.Errorf("ReversePalette: entry %d: expected %v, got %v", i, p[15-i], c)
+		}
+	}
+
+	m := MirrorPalette(&p)
+	if m[0] != p[0] || m[8] != p[15] {
+		t.Errorf("MirrorPalette: expected the first and last color at 0 and 8, got %v and %v", m[0], m[8])
+	}
+	for i := 1; i < 8; i++ {
+		if m[i] != m[16-i] {
+			t.Errorf("MirrorPalette: entries %d and %d differ: %v and %v", i, 16-i, m[i], m[16-i])
+		}
+		if m[i].R <= m[i-1].R {
+			t.Errorf("MirrorPalette: entry %d (%v) is not brighter than entry %d (%v)", i, m[i], i-1, m[i-1])
+		}
+	}
+	for i := 0; i < 128; i++ {
+		// (Blend rounds a bit differently depending on the direction.)
+		if a, b := m.ColorAt(uint8(i)), m.ColorAt(uint8(256-i)); abs(int(a.R)-int(b.R)) > 1 {
+			t.Errorf("MirrorPalette: index %d and %d differ: %v and %v", i, 256-i, a, b)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package palettefile
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/aykevl/ledsgo"
+)
+
+var errGradient = errors.New("palettefile: invalid CSS gradient")
+
+// ParseCSSGradient parses the color stops of a CSS linear gradient, such as
+// "red 0%, #ff8800 50%, blue 100%", into a gradient palette. This makes it easy
+// to configure palettes from a web page, as browsers have gradient editors
+// and can show a preview of the palette. The gradient may be wrapped in
+// linear-gradient(...) and may start with a direction (such as "to right" or
+// "90deg"), which is ignored.
+//
+// Colors can be hex colors (#rgb or #rrggbb), rgb(r, g, b) colors and the
+// basic named colors of CSS (such as red, navy and orange). Positions are
+// percentages from 0% to 100%. Like in CSS, a missing position is halfway
+// between the stops around it (where the first and last stop default to 0% and
+// 100%), and a position before that of the previous stop is moved to that of
+// the previous stop.
+func ParseCSSGradient(s string) (ledsgo.GradientPalette, error) {
+	s = strings.TrimSpace(s)
+	if inner, ok := strings.CutPrefix(s, "linear-gradient("); ok {
+		inner, ok = strings.CutSuffix(inner, ")")
+		if !ok {
+			return nil, errGradient
+		}
+		s = inner
+	}
+	parts := splitCSSList(s)
+	if len(parts) != 0 {
+		if first := strings.TrimSpace(parts[0]); strings.HasPrefix(first, "to ") || strings.HasSuffix(first, "deg") || strings.HasSuffix(first, "turn") {
+			parts = parts[1:]
+		}
+	}
+	if len(parts) == 0 {
+		return nil, errGradient
+	}
+
+	// Parse the stops with positions in percent, where a negative position
+	// means that it is missing.
+	colors := make([]color.RGBA, len(parts))
+	positions := make([]float64, len(parts))
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		colorText, posText := part, ""
+		if i := strings.LastIndexAny(part, " )"); i >= 0 && part[i] == ' ' {
+			colorText, posText = strings.TrimSpace(part[:i]), part[i+1:]
+		}
+		c, err := parseCSSColor(colorText)
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = c
+		positions[i] = -1
+		if posText != "" {
+			percent, ok := strings.CutSuffix(posText, "%")
+			pos, err := strconv.ParseFloat(percent, 64)
+			if !ok || err != nil || math.IsNaN(pos) || pos < 0 || pos > 100 {
+				return nil, errGradient
+			}
+			positions[i] = pos
+		}
+	}
+
+	if positions[0] < 0 {
+		positions[0] = 0
+	}
+	if last := len(positions) - 1; positions[last] < 0 {
+		positions[last] = 100
+	}
+	for i := 1; i < len(positions); i++ {
+		if positions[i] < 0 {
+			// Spread the stops without a position evenly up to the next stop
+			// with a position.
+			next := i + 1
+			for positions[next] < 0 {
+				next++
+			}
+			start := positions[i-1]
+			step := (max(positions[next], start) - start) / float64(next-i+1)
+			for j := i; j < next; j++ {
+				positions[j] = start + step*float64(j-i+1)
+			}
+		}
+		positions[i] = max(positions[i], positions[i-1])
+	}
+
+	g := make(ledsgo.GradientPalette, len(colors))
+	for i, c := range colors {
+		g[i] = ledsgo.GradientStop{Pos: uint8(positions[i]*255/100 + 0.5), Color: c}
+	}
+	return g, nil
+}
+
+// splitCSSList splits a comma separated list, skipping commas inside
+// parentheses such as those in rgb(1, 2, 3).
+func splitCSSList(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" || len(parts) != 0 {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+// parseCSSColor parses a CSS color: a hex color, a rgb() color or a named
+// color.
+func parseCSSColor(s string) (color.RGBA, error) {
+	s = strings.ToLower(s)
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if len(hex) != 6 || err != nil {
+			return color.RGBA{}, errGradient
+		}
+		return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+	}
+	if args, ok := strings.CutPrefix(s, "rgb("); ok {
+		args, ok = strings.CutSuffix(args, ")")
+		fields := strings.Split(args, ",")
+		if !ok || len(fields) != 3 {
+			return color.RGBA{}, errGradient
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		c, err := parseRGB(fields)
+		if err != nil {
+			return color.RGBA{}, errGradient
+		}
+		return c, nil
+	}
+	if c, ok := cssColors[s]; ok {
+		return c, nil
+	}
+	return color.RGBA{}, errGradient
+}
+
+// cssColors are the basic named colors of CSS.
+var cssColors = map[string]color.RGBA{
+	"black":   {0x00, 0x00, 0x00, 0},
+	"silver":  {0xc0, 0xc0, 0xc0, 0},
+	"gray":    {0x80, 0x80, 0x80, 0},
+	"grey":    {0x80, 0x80, 0x80, 0},
+	"white":   {0xff, 0xff, 0xff, 0},
+	"maroon":  {0x80, 0x00, 0x00, 0},
+	"red":     {0xff, 0x00, 0x00, 0},
+	"purple":  {0x80, 0x00, 0x80, 0},
+	"fuchsia": {0xff, 0x00, 0xff, 0},
+	"magenta": {0xff, 0x00, 0xff, 0},
+	"green":   {0x00, 0x80, 0x00, 0},
+	"lime":    {0x00, 0xff, 0x00, 0},
+	"olive":   {0x80, 0x80, 0x00, 0},
+	"yellow":  {0xff, 0xff, 0x00, 0},
+	"navy":    {0x00, 0x00, 0x80, 0},
+	"blue":    {0x00, 0x00, 0xff, 0},
+	"teal":    {0x00, 0x80, 0x80, 0},
+	"aqua":    {0x00, 0xff, 0xff, 0},
+	"cyan":    {0x00, 0xff, 0xff, 0},
+	"orange":  {0xff, 0xa5, 0x00, 0},
+}
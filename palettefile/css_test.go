@@ -0,0 +1,59 @@
+package palettefile
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+)
+
+func TestParseCSSGradient(t *testing.T) {
+	red, orange, blue := color.RGBA{R: 255}, color.RGBA{R: 0xff, G: 0x88}, color.RGBA{B: 255}
+	for _, tc := range []struct {
+		css      string
+		expected ledsgo.GradientPalette
+	}{
+		{"red 0%, #ff8800 50%, blue 100%", ledsgo.GradientPalette{{Pos: 0, Color: red}, {Pos: 128, Color: orange}, {Pos: 255, Color: blue}}},
+		{"linear-gradient(to right, Red, #f80, rgb(0, 0, 255))", ledsgo.GradientPalette{{Pos: 0, Color: red}, {Pos: 128, Color: orange}, {Pos: 255, Color: blue}}},
+		{"linear-gradient(90deg, red 20%, lime, aqua, blue 80%)",
+			ledsgo.GradientPalette{{Pos: 51, Color: red}, {Pos: 102, Color: color.RGBA{G: 255}}, {Pos: 153, Color: color.RGBA{G: 255, B: 255}}, {Pos: 204, Color: blue}}},
+		{"red 50%, blue 10%", ledsgo.GradientPalette{{Pos: 128, Color: red}, {Pos: 128, Color: blue}}},
+		{"navy", ledsgo.GradientPalette{{Pos: 0, Color: color.RGBA{B: 0x80}}}},
+	} {
+		g, err := ParseCSSGradient(tc.css)
+		if err != nil {
+			t.Errorf("%q: %v", tc.css, err)
+			continue
+		}
+		if len(g) != len(tc.expected) {
+			t.Errorf("%q: expected %v, got %v", tc.css, tc.expected, g)
+			continue
+		}
+		for i, stop := range g {
+			if stop != tc.expected[i] {
+				t.Errorf("%q: stop %d: expected %v, got %v", tc.css, i, tc.expected[i], stop)
+			}
+		}
+	}
+}
+
+func TestParseCSSGradientInvalid(t *testing.T) {
+	for _, css := range []string{
+		"",
+		"linear-gradient(red, blue",
+		"red, bleu",
+		"red 50px, blue",
+		"#12345, blue",
+		"rgb(1, 2), blue",
+		"rgb(1, 2, 300)",
+		"red 10%, green NaN%, blue 90%",
+		"red 10%, green Inf%, blue 90%",
+		"red -Inf%, blue",
+		"red -10%, blue",
+		"red, blue 150%",
+	} {
+		if _, err := ParseCSSGradient(css); err == nil {
+			t.Errorf("expected an error for %q", css)
+		}
+	}
+}
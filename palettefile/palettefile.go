@@ -0,0 +1,139 @@
+// Package palettefile reads palette files made with desktop tools, so that
+// palettes can be designed in for example GIMP, Inkscape or Paint Shop Pro and
+// used in a ledsgo project. Supported formats are the GIMP palette format
+// (.gpl) and the JASC palette format (.pal). Gradients in CSS syntax, as used
+// by web pages, can be parsed with ParseCSSGradient.
+package palettefile
+
+import (
+	"bufio"
+	"errors"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aykevl/ledsgo"
+)
+
+var (
+	errFormat  = errors.New("palettefile: unknown file format")
+	errInvalid = errors.New("palettefile: invalid palette file")
+)
+
+// Palette is a palette read from a file.
+type Palette struct {
+	Name   string // name of the palette, if the file format stores it
+	Colors []color.RGBA
+}
+
+// Palette16 returns the colors as a gradient over a 16-entry palette. See
+// ledsgo.NewPalette16.
+func (p *Palette) Palette16() ledsgo.Palette16 {
+	return ledsgo.NewPalette16(p.Colors...)
+}
+
+// Read reads a palette file, detecting the format from the first line.
+func Read(r io.Reader) (*Palette, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errFormat
+	}
+	// Some editors on Windows start the file with a byte order mark.
+	switch strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff")) {
+	case "GIMP Palette":
+		return readGPL(scanner)
+	case "JASC-PAL":
+		return readJASC(scanner)
+	default:
+		return nil, errFormat
+	}
+}
+
+// readGPL reads the rest of a GIMP palette file, after the first line.
+func readGPL(scanner *bufio.Scanner) (*Palette, error) {
+	p := &Palette{}
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "Name:"); ok {
+			p.Name = strings.TrimSpace(name)
+			continue
+		}
+		if strings.HasPrefix(line, "Columns:") {
+			continue
+		}
+		// A color line, optionally followed by the name of the color.
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, errInvalid
+		}
+		c, err := parseRGB(fields[:3])
+		if err != nil {
+			return nil, err
+		}
+		p.Colors = append(p.Colors, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// readJASC reads the rest of a JASC palette file, after the first line.
+func readJASC(scanner *bufio.Scanner) (*Palette, error) {
+	var header [2]string // version and number of colors
+	for i := range header {
+		if !scanner.Scan() {
+			return nil, errInvalid
+		}
+		header[i] = strings.TrimSpace(scanner.Text())
+	}
+	if header[0] != "0100" {
+		return nil, errInvalid
+	}
+	n, err := strconv.Atoi(header[1])
+	if err != nil || n < 0 || n > 256 {
+		return nil, errInvalid
+	}
+	p := &Palette{Colors: make([]color.RGBA, 0, n)}
+	for len(p.Colors) < n && scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, errInvalid
+		}
+		c, err := parseRGB(fields)
+		if err != nil {
+			return nil, err
+		}
+		p.Colors = append(p.Colors, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(p.Colors) != n {
+		return nil, errInvalid
+	}
+	return p, nil
+}
+
+// parseRGB parses three decimal color components.
+func parseRGB(fields []string) (color.RGBA, error) {
+	var rgb [3]uint8
+	for i, field := range fields {
+		v, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return color.RGBA{}, errInvalid
+		}
+		rgb[i] = uint8(v)
+	}
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2]}, nil
+}
@@ -0,0 +1,61 @@
+package palettefile
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		file     string
+		expected Palette
+	}{
+		{"gpl", "GIMP Palette\nName: Sunset\nColumns: 3\n#\n255   0   0\tRed\n 255 128   0\n0 0 64 Dark blue\n",
+			Palette{Name: "Sunset", Colors: []color.RGBA{{R: 255}, {R: 255, G: 128}, {B: 64}}}},
+		{"jasc", "JASC-PAL\r\n0100\r\n2\r\n1 2 3\r\n4 5 6\r\n",
+			Palette{Colors: []color.RGBA{{R: 1, G: 2, B: 3}, {R: 4, G: 5, B: 6}}}},
+	} {
+		p, err := Read(strings.NewReader(tc.file))
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if p.Name != tc.expected.Name {
+			t.Errorf("%s: expected name %q, got %q", tc.name, tc.expected.Name, p.Name)
+		}
+		if len(p.Colors) != len(tc.expected.Colors) {
+			t.Errorf("%s: expected %d colors, got %d", tc.name, len(tc.expected.Colors), len(p.Colors))
+			continue
+		}
+		for i, c := range p.Colors {
+			if c != tc.expected.Colors[i] {
+				t.Errorf("%s: color %d: expected %v, got %v", tc.name, i, tc.expected.Colors[i], c)
+			}
+		}
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	for _, file := range []string{
+		"",
+		"RIFF",
+		"GIMP Palette\n255 0\n",
+		"GIMP Palette\n256 0 0\n",
+		"JASC-PAL\n0100\n3\n1 2 3\n",
+		"JASC-PAL\n0200\n0\n",
+	} {
+		if _, err := Read(strings.NewReader(file)); err == nil {
+			t.Errorf("expected an error for %q", file)
+		}
+	}
+}
+
+func TestPalette16(t *testing.T) {
+	p := &Palette{Colors: []color.RGBA{{R: 255}, {B: 255}}}
+	p16 := p.Palette16()
+	if p16[0] != p.Colors[0] || p16[15] != p.Colors[1] {
+		t.Errorf("unexpected palette: %v", p16)
+	}
+}
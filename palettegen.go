@@ -0,0 +1,102 @@
+package ledsgo
+
+// PaletteGenerator generates random palettes that still look good, for
+// example for a "surprise me" button in a controller. Fully random colors
+// rarely go well together, so the palettes are constrained: they use only a
+// few hues close to each other, and the saturation and brightness of all
+// colors are kept within a range.
+type PaletteGenerator struct {
+	// Hues is the number of different hues in a palette. Every hue is one
+	// color of the gradient that makes up the palette. Zero is treated as
+	// one.
+	Hues uint8
+
+	// HueRange is the part of the color wheel the hues are spread over, where
+	// 0x10000 is the full circle. Small ranges give analogous colors (such as
+	// only reds and oranges), larger ranges give more contrast.
+	HueRange uint16
+
+	// MinSaturation and MaxSaturation limit the saturation of the colors.
+	MinSaturation, MaxSaturation uint8
+
+	// MinValue and MaxValue limit the brightness of the colors. A small
+	// range keeps the brightness of the palette even.
+	MinValue, MaxValue uint8
+
+	// Anchor is an optional color that is always part of the palette, at
+	// index 0. The other hues are chosen starting from its hue.
+	Anchor *Color
+}
+
+// NewPaletteGenerator returns a PaletteGenerator with the default settings:
+// 3 hues within a third of the color wheel, with a high saturation and
+// brightness.
+func NewPaletteGenerator() *PaletteGenerator {
+	return &PaletteGenerator{
+		Hues:          3,
+		HueRange:      0x5555,
+		MinSaturation: 180,
+		MaxSaturation: 255,
+		MinValue:      180,
+		MaxValue:      255,
+	}
+}
+
+// Generate returns a new random palette using the random number generator r,
+// so that the same seed results in the same palette. The palette is a
+// gradient through the hues that wraps around smoothly: it starts and ends
+// with the same color.
+func (g *PaletteGenerator) Generate(r *Rand) Palette16 {
+	hues := max(int(g.Hues), 1)
+	var base Color
+	if g.Anchor != nil {
+		base = *g.Anchor
+	} else {
+		base.H = r.Uint16()
+	}
+
+	// The key colors of the gradient. The hues are not wrapped around, so
+	// that the gradient returns from the last hue to the first the same way
+	// it came.
+	var keys [17]struct {
+		h    int32
+		s, v uint8
+	}
+	hues = min(hues, len(keys)-1)
+	for i := 0; i < hues; i++ {
+		key := &keys[i]
+		key.h = int32(base.H)
+		if hues > 1 {
+			key.h += int32(uint32(g.HueRange) * uint32(i) / uint32(hues-1))
+		}
+		key.s = randomInRange(r, g.MinSaturation, g.MaxSaturation)
+		key.v = randomInRange(r, g.MinValue, g.MaxValue)
+	}
+	if g.Anchor != nil {
+		keys[0].s, keys[0].v = g.Anchor.S, g.Anchor.V
+	}
+	keys[hues] = keys[0]
+
+	// Interpolate in HSV instead of RGB, so that the colors between two hues
+	// stay as saturated as the hues themselves.
+	var p Palette16
+	for i := range p {
+		pos := i * hues * 256 / (len(p) - 1) // .8
+		a, b := keys[pos>>8], keys[min(pos>>8+1, hues)]
+		frac := int32(pos & 0xff)
+		p[i] = Color{
+			H: uint16(a.h + (b.h-a.h)*frac/256),
+			S: Lerp8by8(a.s, b.s, uint8(frac)),
+			V: Lerp8by8(a.v, b.v, uint8(frac)),
+		}.Spectrum()
+	}
+	return p
+}
+
+// randomInRange returns a random value in the inclusive range [low, high].
+func randomInRange(r *Rand, low, high uint8) uint8 {
+	if high <= low {
+		return low
+	}
+	return low + uint8(r.Intn(int(high-low)+1))
+}
@@ -0,0 +1,52 @@
+package ledsgo
+
+import (
+	"testing"
+)
+
+func TestPaletteGenerator(t *testing.T) {
+	g := NewPaletteGenerator()
+	r := NewRand(1)
+	for i := 0; i < 100; i++ {
+		p := g.Generate(r)
+		if p[0] != p[15] {
+			t.Errorf("palette %d: does not wrap around smoothly: %v and %v", i, p[0], p[15])
+		}
+		for j, c := range p {
+			hsv := ColorFromRGBA(c)
+			if hsv.V < g.MinValue-8 || hsv.S < g.MinSaturation-24 {
+				t.Errorf("palette %d: entry %d is too dark or gray: %v (%v)", i, j, c, hsv)
+			}
+		}
+	}
+
+	// The same seed gives the same palette.
+	if a, b := g.Generate(NewRand(5)), g.Generate(NewRand(5)); a != b {
+		t.Error("the same seed resulted in different palettes")
+	}
+
+	// With an anchor, the palette starts with it and the hues are close to it.
+	anchor := Color{H: 0x1000, S: 255, V: 200}
+	g = &PaletteGenerator{Hues: 2, HueRange: 0x1000, MinSaturation: 255, MaxSaturation: 255, MinValue: 255, MaxValue: 255, Anchor: &anchor}
+	p := g.Generate(r)
+	if p[0] != anchor.Spectrum() {
+		t.Errorf("anchor: expected %v at the start, got %v", anchor.Spectrum(), p[0])
+	}
+	for i, c := range p {
+		if h := ColorFromRGBA(c).H; h < 0x1000-0x100 || h > 0x2000+0x100 {
+			t.Errorf("anchor: entry %d has a hue that is out of range: %v (hue %#x)", i, c, h)
+		}
+	}
+	if h := ColorFromRGBA(p[8]).H; h < 0x1e00 {
+		t.Errorf("anchor: expected the second hue halfway, got %#x", h)
+	}
+
+	// Zero hues is treated as one hue, which gives a single color.
+	g = &PaletteGenerator{MinValue: 100, MaxValue: 100, MinSaturation: 200, MaxSaturation: 200}
+	p = g.Generate(r)
+	for _, c := range p {
+		if c != p[0] {
+			t.Fatalf("single hue: expected one color, got %v", p)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// PaletteSchedule selects a palette based on the time of day, for example to
+// use warm palettes in the evening and cool palettes during the day. The
+// schedule repeats every day, or every Period if it is set, so the last
+// palette of the day is used until the first palette of the next day starts.
+// Palettes cross-fade into each other over the Fade duration, starting at the
+// start of the next palette.
+//
+// The schedule can also follow an external schedule (such as sunrise and
+// sunset times, or a timeline of a show) by setting the entries and calling At
+// with the time since the start of that schedule.
+type PaletteSchedule struct {
+	// Entries are the palettes and their start times, sorted by start time.
+	Entries []PaletteScheduleEntry
+
+	// Fade is the duration of the cross-fade between two palettes. If it is
+	// zero, palettes change immediately.
+	Fade time.Duration
+
+	// Period is the duration after which the schedule repeats. If it is zero,
+	// the schedule repeats every 24 hours.
+	Period time.Duration
+}
+
+// PaletteScheduleEntry is a palette in a PaletteSchedule.
+type PaletteScheduleEntry struct {
+	Start   time.Duration // time since the start of the day (or period)
+	Palette *Palette16
+}
+
+// At returns the palette at time t since the start of the day (or period). It
+// returns a black palette if there are no entries.
+func (s *PaletteSchedule) At(t time.Duration) Palette16 {
+	if len(s.Entries) == 0 {
+		return Palette16{}
+	}
+	period := s.Period
+	if period <= 0 {
+		period = 24 * time.Hour
+	}
+	t %= period
+	if t < 0 {
+		t += period
+	}
+
+	// Find the current entry. Before the first entry of the day, the last
+	// entry of the previous day is still active.
+	current := len(s.Entries) - 1
+	for i, entry := range s.Entries {
+		if entry.Start > t {
+			break
+		}
+		current = i
+	}
+	started := t - s.Entries[current].Start // time since the current entry started
+	if started < 0 {
+		started += period
+	}
+
+	p := *s.Entries[current].Palette
+	if started >= s.Fade {
+		return p
+	}
+	previous := (current + len(s.Entries) - 1) % len(s.Entries)
+	result := *s.Entries[previous].Palette
+	result.Blend(&p, uint8(started*256/s.Fade))
+	return result
+}
+
+// AtTime returns the palette at the time of day of t, in the location of t.
+// Daylight saving time changes are not taken into account: the time of day is
+// what a clock on the wall would show.
+func (s *PaletteSchedule) AtTime(t time.Time) Palette16 {
+	hour, minute, sec := t.Clock()
+	return s.At(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(sec)*time.Second + time.Duration(t.Nanosecond()))
+}
@@ -0,0 +1,62 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestPaletteSchedule(t *testing.T) {
+	day := NewPalette16(color.RGBA{B: 255})
+	evening := NewPalette16(color.RGBA{R: 255, G: 100})
+	night := NewPalette16(color.RGBA{R: 20})
+	s := &PaletteSchedule{
+		Entries: []PaletteScheduleEntry{
+			{Start: 7 * time.Hour, Palette: &day},
+			{Start: 18 * time.Hour, Palette: &evening},
+			{Start: 23 * time.Hour, Palette: &night},
+		},
+		Fade: time.Hour,
+	}
+	halfway := func(a, b Palette16) Palette16 {
+		a.Blend(&b, 128)
+		return a
+	}
+	for _, tc := range []struct {
+		t time.Duration
+		p Palette16
+	}{
+		{3 * time.Hour, night}, // still the night of the previous day
+		{7 * time.Hour, night},
+		{7*time.Hour + 30*time.Minute, halfway(night, day)},
+		{12 * time.Hour, day},
+		{18*time.Hour + 30*time.Minute, halfway(day, evening)},
+		{23*time.Hour + 30*time.Minute, halfway(evening, night)},
+		{24*time.Hour + 12*time.Hour, day},
+		{-30 * time.Minute, halfway(evening, night)},
+	} {
+		if p := s.At(tc.t); p != tc.p {
+			t.Errorf("At(%v): expected %v, got %v", tc.t, tc.p[0], p[0])
+		}
+	}
+
+	// A fade that spans midnight.
+	s.Entries[0].Start = 15 * time.Minute
+	if p := s.At(45 * time.Minute); p != halfway(night, day) {
+		t.Errorf("fade over midnight: got %v", p[0])
+	}
+	s.Entries[0].Start = 7 * time.Hour
+
+	// The time of day is taken in the location of the time.
+	loc := time.FixedZone("test", 5*3600)
+	if p := s.AtTime(time.Date(2024, 3, 1, 12, 0, 0, 0, loc)); p != day {
+		t.Errorf("AtTime(12:00): got %v", p[0])
+	}
+	if p := s.AtTime(time.Date(2024, 3, 1, 12, 0, 0, 0, loc).In(time.UTC)); p != night {
+		t.Errorf("AtTime(07:00 UTC): got %v", p[0])
+	}
+
+	if p := (&PaletteSchedule{}).At(time.Hour); p != (Palette16{}) {
+		t.Errorf("empty schedule: expected black, got %v", p)
+	}
+}
@@ -0,0 +1,101 @@
+package ledsgo
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// PartEffect is an effect that can render any part of a frame independently
+// of the rest of the frame, such as most noise and palette based effects.
+// Such effects can be rendered in parallel by a ParallelRenderer.
+type PartEffect interface {
+	// RenderPart draws the LEDs of the frame starting at LED start. The part
+	// is a slice of the full frame.
+	RenderPart(part Strip, start int, t time.Duration)
+}
+
+// PartEffectFunc is an adapter that allows the use of an ordinary function as
+// a PartEffect.
+type PartEffectFunc func(part Strip, start int, t time.Duration)
+
+// RenderPart calls f(part, start, t).
+func (f PartEffectFunc) RenderPart(part Strip, start int, t time.Duration) {
+	f(part, start, t)
+}
+
+// parallelJob is a part of a frame to be rendered by a worker.
+type parallelJob struct {
+	part  Strip
+	start int
+	t     time.Duration
+}
+
+// ParallelRenderer is an Effect that splits every frame into parts and renders
+// them on multiple goroutines at the same time. This is useful for large
+// installations with thousands of LEDs, on multi-core microcontrollers such
+// as the RP2040 and on desktop computers that send frames over the network.
+//
+// The worker goroutines are started once, so rendering a frame doesn't
+// allocate memory.
+type ParallelRenderer struct {
+	effect PartEffect
+	align  int
+	jobs   chan parallelJob
+	wg     sync.WaitGroup
+	parts  int
+}
+
+// NewParallelRenderer returns a new ParallelRenderer with the given number of
+// worker goroutines, or one per CPU if workers is 0. Every part (except the
+// last) has a multiple of align LEDs, so that for example a matrix can be
+// split on row boundaries by passing the width of the matrix. Call Close to
+// stop the worker goroutines.
+func NewParallelRenderer(effect PartEffect, workers, align int) *ParallelRenderer {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if align <= 0 {
+		align = 1
+	}
+	r := &ParallelRenderer{
+		effect: effect,
+		align:  align,
+		jobs:   make(chan parallelJob, workers),
+		parts:  workers,
+	}
+	for i := 0; i < workers; i++ {
+		go r.work()
+	}
+	return r
+}
+
+// work renders parts until the renderer is closed.
+func (r *ParallelRenderer) work() {
+	for job := range r.jobs {
+		r.effect.RenderPart(job.part, job.start, job.t)
+		r.wg.Done()
+	}
+}
+
+// Render implements Effect. It returns once all parts have been rendered.
+func (r *ParallelRenderer) Render(frame Strip, t time.Duration) {
+	// Round the part size up to a multiple of align.
+	size := (len(frame) + r.parts - 1) / r.parts
+	size = (size + r.align - 1) / r.align * r.align
+	if size == 0 {
+		return
+	}
+	for start := 0; start < len(frame); start += size {
+		end := min(start+size, len(frame))
+		r.wg.Add(1)
+		r.jobs <- parallelJob{part: frame[start:end], start: start, t: t}
+	}
+	r.wg.Wait()
+}
+
+// Close stops the worker goroutines. The renderer must not be used anymore
+// after calling Close.
+func (r *ParallelRenderer) Close() {
+	close(r.jobs)
+}
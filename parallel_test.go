@@ -0,0 +1,47 @@
+package ledsgo
+
+import (
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallelRenderer(t *testing.T) {
+	var lock sync.Mutex
+	var starts []int
+	effect := PartEffectFunc(func(part Strip, start int, t time.Duration) {
+		lock.Lock()
+		starts = append(starts, start)
+		lock.Unlock()
+		for i := range part {
+			part[i] = color.RGBA{R: uint8(start + i), G: uint8(t)}
+		}
+	})
+	r := NewParallelRenderer(effect, 4, 16)
+	defer r.Close()
+	frame := make(Strip, 100)
+	r.Render(frame, 7)
+	for i, c := range frame {
+		if c != (color.RGBA{R: uint8(i), G: 7}) {
+			t.Errorf("LED %d: unexpected color %v", i, c)
+		}
+	}
+	// 100 LEDs over 4 workers is 25 LEDs, rounded up to 32.
+	if len(starts) != 4 {
+		t.Errorf("expected 4 parts, got %d", len(starts))
+	}
+	for _, start := range starts {
+		if start%32 != 0 {
+			t.Errorf("part starts at %d, which is not aligned", start)
+		}
+	}
+
+	r2 := NewParallelRenderer(PartEffectFunc(func(part Strip, start int, t time.Duration) {
+		part.FillSolid(color.RGBA{R: uint8(start)})
+	}), 4, 1)
+	defer r2.Close()
+	if n := testing.AllocsPerRun(10, func() { r2.Render(frame, 0) }); n != 0 {
+		t.Errorf("expected no allocations per frame, got %v", n)
+	}
+}
@@ -0,0 +1,123 @@
+package ledsgo
+
+// ParamEffect is an Effect with parameters that can be changed while it is
+// running, such as its speed or the number of sparks. User interfaces (such
+// as a web page or an MQTT bridge) can list the parameters and generate a
+// control for every one of them, without knowing the effect.
+type ParamEffect interface {
+	Effect
+
+	// Params returns the parameters of the effect. The Value of every
+	// parameter points into the effect, so that changing it changes the
+	// running effect. The list must be the same every time.
+	Params() []Param
+}
+
+// Param describes a parameter of an effect. This is a small interface instead
+// of struct tags with reflection, so that it works well with TinyGo and costs
+// little code size.
+type Param struct {
+	// Name is the name of the parameter, as shown in user interfaces.
+	Name string
+
+	// Min, Max and Default are the range and the default value of the
+	// parameter.
+	Min, Max, Default int32
+
+	// Value is a pointer to the parameter in the effect. It must be a *uint8,
+	// *int8, *uint16, *int32, *int or *bool (where false is 0 and true is 1).
+	Value any
+}
+
+// EffectParams returns the parameters of the effect, or nil if it has no
+// parameters (it doesn't implement ParamEffect).
+func EffectParams(effect Effect) []Param {
+	if e, ok := effect.(ParamEffect); ok {
+		return e.Params()
+	}
+	return nil
+}
+
+// Get returns the current value of the parameter. Like Set, it doesn't
+// synchronize with the effect.
+func (p *Param) Get() int32 {
+	switch v := p.Value.(type) {
+	case *uint8:
+		return int32(*v)
+	case *int8:
+		return int32(*v)
+	case *uint16:
+		return int32(*v)
+	case *int32:
+		return *v
+	case *int:
+		return int32(*v)
+	case *bool:
+		if *v {
+			return 1
+		}
+		return 0
+	default:
+		panic("ledsgo: unsupported parameter type")
+	}
+}
+
+// Set changes the value of the parameter. The value is limited to the range of
+// the parameter. Set doesn't synchronize with the effect: for an effect that
+// is running in a Runner or SegmentRunner, use their SetParam method instead.
+func (p *Param) Set(value int32) {
+	value = min(max(value, p.Min), p.Max)
+	switch v := p.Value.(type) {
+	case *uint8:
+		*v = uint8(value)
+	case *int8:
+		*v = int8(value)
+	case *uint16:
+		*v = uint16(value)
+	case *int32:
+		*v = value
+	case *int:
+		*v = int(value)
+	case *bool:
+		*v = value != 0
+	default:
+		panic("ledsgo: unsupported parameter type")
+	}
+}
+
+// Reset changes the parameter back to its default value.
+func (p *Param) Reset() {
+	p.Set(p.Default)
+}
+
+// FindParam returns the parameter with the given name, or nil if there is no
+// such parameter.
+func FindParam(params []Param, name string) *Param {
+	for i := range params {
+		if params[i].Name == name {
+			return &params[i]
+		}
+	}
+	return nil
+}
+
+// getParam returns the value of the parameter of the effect with the given
+// name, if there is one.
+func getParam(effect Effect, name string) (int32, bool) {
+	p := FindParam(EffectParams(effect), name)
+	if p == nil {
+		return 0, false
+	}
+	return p.Get(), true
+}
+
+// setParam changes the parameter of the effect with the given name, if there
+// is one.
+func setParam(effect Effect, name string, value int32) bool {
+	p := FindParam(EffectParams(effect), name)
+	if p == nil {
+		return false
+	}
+	p.Set(value)
+	return true
+}
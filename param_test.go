@@ -0,0 +1,106 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+// sparkleEffect is an effect with parameters of all supported types.
+type sparkleEffect struct {
+	speed   uint8
+	hue     uint16
+	offset  int32
+	sparks  int
+	reverse bool
+}
+
+func (e *sparkleEffect) Render(frame Strip, t time.Duration) {
+	frame.FillSolid(color.RGBA{R: e.speed})
+}
+
+func (e *sparkleEffect) Params() []Param {
+	return []Param{
+		{Name: "Speed", Min: 0, Max: 255, Default: 128, Value: &e.speed},
+		{Name: "Hue", Min: 0, Max: 0xffff, Default: 0x1000, Value: &e.hue},
+		{Name: "Offset", Min: -100, Max: 100, Default: -10, Value: &e.offset},
+		{Name: "Sparks", Min: 1, Max: 20, Default: 5, Value: &e.sparks},
+		{Name: "Reverse", Min: 0, Max: 1, Default: 1, Value: &e.reverse},
+	}
+}
+
+func TestParams(t *testing.T) {
+	e := &sparkleEffect{}
+	params := EffectParams(e)
+	if len(params) != 5 {
+		t.Fatalf("expected 5 parameters, got %d", len(params))
+	}
+	for i := range params {
+		params[i].Reset()
+	}
+	if *e != (sparkleEffect{speed: 128, hue: 0x1000, offset: -10, sparks: 5, reverse: true}) {
+		t.Errorf("Reset: unexpected values %+v", *e)
+	}
+	for _, p := range params {
+		if v := p.Get(); v != p.Default {
+			t.Errorf("%s: expected %d, got %d", p.Name, p.Default, v)
+		}
+	}
+
+	// Values are limited to the range of the parameter.
+	FindParam(params, "Speed").Set(300)
+	FindParam(params, "Offset").Set(-1000)
+	FindParam(params, "Sparks").Set(0)
+	FindParam(params, "Reverse").Set(0)
+	if *e != (sparkleEffect{speed: 255, hue: 0x1000, offset: -100, sparks: 1}) {
+		t.Errorf("Set: unexpected values %+v", *e)
+	}
+	frame := make(Strip, 1)
+	e.Render(frame, 0)
+	if frame[0].R != 255 {
+		t.Error("the parameter was not changed in the running effect")
+	}
+
+	if FindParam(params, "Color") != nil {
+		t.Error("FindParam: found a parameter that doesn't exist")
+	}
+	if EffectParams(EffectFunc(func(Strip, time.Duration) {})) != nil {
+		t.Error("EffectParams: expected no parameters for an EffectFunc")
+	}
+}
+
+func TestBuiltinEffectParams(t *testing.T) {
+	// The parameters of the built-in effects are in their range, point into
+	// the effect and can be reset to their default.
+	for _, e := range []ParamEffect{
+		NewNoiseFire(8, 8), NewSpectrumBars(testAudio(0), 8, 8), &Rain{}, &Snow{}, &Sun{},
+		NewStripes(color.RGBA{R: 255}), NewPoliceFlash(), &Progress{}, &DigitalClock{},
+	} {
+		params := e.Params()
+		if len(params) == 0 {
+			t.Errorf("%T: expected parameters", e)
+		}
+		for i := range params {
+			p := &params[i]
+			if p.Default < p.Min || p.Default > p.Max {
+				t.Errorf("%T %s: default %d outside of [%d, %d]", e, p.Name, p.Default, p.Min, p.Max)
+			}
+			p.Set(p.Max)
+			if e.Params()[i].Get() != p.Max {
+				t.Errorf("%T %s: the parameter doesn't point into the effect", e, p.Name)
+			}
+			p.Reset()
+			if v := p.Get(); v != p.Default {
+				t.Errorf("%T %s: expected %d after a reset, got %d", e, p.Name, p.Default, v)
+			}
+		}
+	}
+
+	// Signed 8-bit parameters are clamped to their range.
+	fire := NewNoiseFire(8, 8)
+	wind := FindParam(fire.Params(), "Wind")
+	wind.Set(-1000)
+	if fire.Wind != -128 || wind.Get() != -128 {
+		t.Errorf("Wind: expected -128, got %d", fire.Wind)
+	}
+}
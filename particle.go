@@ -0,0 +1,137 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+// Particle is a single particle of a ParticleSystem.
+type Particle struct {
+	// X and Y are the position as 16.16 fixed-point LED (or pixel) indices.
+	// On a strip, only X is used.
+	X, Y int32
+
+	// VX and VY are the velocity in LEDs per second, as 16.16 fixed-point
+	// values.
+	VX, VY int32
+
+	// Color is the color of the particle. It is only used if the particle
+	// system has no palette.
+	Color color.RGBA
+
+	// Age is the time since the particle was spawned, and Lifetime the age at
+	// which it disappears.
+	Age, Lifetime time.Duration
+}
+
+// ParticleSystem is a reusable particle engine for effects such as fireworks,
+// sparks, rain and comets: particles are spawned with a position, velocity
+// and lifetime, move under the influence of gravity and drag, and disappear
+// at the end of their lifetime. The number of particles is limited to a fixed
+// capacity, so that updating and drawing them doesn't allocate memory.
+type ParticleSystem struct {
+	// GravityX and GravityY are the acceleration of all particles in LEDs per
+	// second squared, as 16.16 fixed-point values. To let sparks fall down a
+	// strip that hangs from its first LED, set a positive GravityX. On a
+	// canvas, a positive GravityY pulls down.
+	GravityX, GravityY int32
+
+	// Drag is the part of their speed that particles lose every second,
+	// where 0xffff stops them nearly immediately.
+	Drag uint16
+
+	// Palette is the color of the particles over their life: they start with
+	// the color at index 0 and end with the color at index 240 (so that the
+	// palette is not wrapped around). If it is nil, particles have their own
+	// color, which fades out over their life.
+	Palette *Palette16
+
+	particles []Particle
+}
+
+// NewParticleSystem returns a new particle system with room for the given
+// number of particles.
+func NewParticleSystem(capacity int) *ParticleSystem {
+	return &ParticleSystem{particles: make([]Particle, 0, capacity)}
+}
+
+// Spawn adds a particle. It returns false if there is no room for another
+// particle or if the particle is already at the end of its lifetime (or its
+// age is negative), in which case the particle is dropped.
+func (s *ParticleSystem) Spawn(p Particle) bool {
+	if len(s.particles) == cap(s.particles) || p.Age < 0 || p.Age >= p.Lifetime {
+		return false
+	}
+	s.particles = append(s.particles, p)
+	return true
+}
+
+// Particles returns the live particles. They may be changed, but their order
+// changes when particles are removed.
+func (s *ParticleSystem) Particles() []Particle {
+	return s.particles
+}
+
+// Clear removes all particles.
+func (s *ParticleSystem) Clear() {
+	s.particles = s.particles[:0]
+}
+
+// Update moves all particles forward in time by dt and removes the particles
+// that have reached the end of their lifetime. Particles don't move back in
+// time: a dt of zero or less does nothing.
+func (s *ParticleSystem) Update(dt time.Duration) {
+	if dt <= 0 {
+		return
+	}
+	step := int64(dt << 16 / time.Second)                                  // .16 seconds
+	drag := int64(0x10000 - uint32(s.Drag)*uint32(min(step, 0x10000))>>16) // .16: speed that is kept
+	for i := 0; i < len(s.particles); i++ {
+		p := &s.particles[i]
+		p.Age += dt
+		if p.Age >= p.Lifetime {
+			// Remove the particle by moving the last one in its place.
+			last := len(s.particles) - 1
+			*p = s.particles[last]
+			s.particles = s.particles[:last]
+			i--
+			continue
+		}
+		p.VX = int32((int64(p.VX) + int64(s.GravityX)*step>>16) * drag >> 16)
+		p.VY = int32((int64(p.VY) + int64(s.GravityY)*step>>16) * drag >> 16)
+		p.X += int32(int64(p.VX) * step >> 16)
+		p.Y += int32(int64(p.VY) * step >> 16)
+	}
+}
+
+// color returns the color of the particle at its current age.
+func (s *ParticleSystem) color(p *Particle) color.RGBA {
+	// Spawn and Update keep Age below Lifetime, but particles may have been
+	// changed through Particles.
+	if p.Lifetime <= 0 {
+		return color.RGBA{}
+	}
+	life := uint8(min(max(p.Age, 0), p.Lifetime) * 255 / p.Lifetime)
+	if s.Palette != nil {
+		return s.Palette.ColorAt(Scale8(life, 240))
+	}
+	return scaleRGBA(p.Color, 255-life)
+}
+
+// Render draws all particles on the strip, adding their colors to the colors
+// that are already there. Particles are drawn at their X position.
+func (s *ParticleSystem) Render(strip Strip) {
+	for i := range s.particles {
+		p := &s.particles[i]
+		strip.DrawDot(p.X>>8, s.color(p))
+	}
+}
+
+// RenderCanvas draws all particles on the canvas, adding their colors to the
+// colors that are already there.
+func (s *ParticleSystem) RenderCanvas(c *Canvas) {
+	for i := range s.particles {
+		p := &s.particles[i]
+		c.DrawDot(p.X>>8, p.Y>>8, s.color(p))
+	}
+}
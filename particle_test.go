@@ -0,0 +1,123 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestParticleSystem(t *testing.T) {
+	s := NewParticleSystem(2)
+	red := color.RGBA{R: 255}
+	if !s.Spawn(Particle{X: 2 << 16, VX: 4 << 16, Color: red, Lifetime: time.Second}) ||
+		!s.Spawn(Particle{X: 0, Y: 1 << 16, VY: -2 << 16, Color: red, Lifetime: 2 * time.Second}) {
+		t.Fatal("could not spawn particles")
+	}
+	if s.Spawn(Particle{Lifetime: time.Second}) {
+		t.Error("spawned more particles than the capacity")
+	}
+
+	// Particles move with their velocity.
+	s.Update(time.Second / 2)
+	if p := s.Particles()[0]; p.X != 4<<16 || p.Y != 0 {
+		t.Errorf("expected the particle at 4, got %v", p)
+	}
+	frame := make(Strip, 8)
+	s.Render(frame)
+	if frame[4] != (color.RGBA{R: 128}) || frame[0] != (color.RGBA{R: 192}) {
+		t.Errorf("unexpected frame %v", frame)
+	}
+
+	// Particles disappear at the end of their lifetime.
+	s.Update(time.Second / 2)
+	if len(s.Particles()) != 1 || s.Particles()[0].Lifetime != 2*time.Second {
+		t.Fatalf("expected the first particle to be removed, got %v", s.Particles())
+	}
+	if p := s.Particles()[0]; p.Y != -1<<16 {
+		t.Errorf("expected the second particle at -1, got %v", p)
+	}
+
+	// Gravity accelerates particles, drag slows them down.
+	s.Clear()
+	s.GravityY = 10 << 16
+	s.Spawn(Particle{Lifetime: time.Hour})
+	for i := 0; i < 100; i++ {
+		s.Update(10 * time.Millisecond)
+	}
+	if p := s.Particles()[0]; p.VY < 10<<16-1000 || p.VY > 10<<16+1000 || p.Y < 5<<16-5000 || p.Y > 5<<16+5000 {
+		t.Errorf("after a second of gravity: expected a speed of 10 and position of 5, got %v", p)
+	}
+	s.GravityY = 0
+	s.Drag = 0x8000
+	s.Update(100 * time.Millisecond)
+	if p := s.Particles()[0]; p.VY > 96*(10<<16)/100 || p.VY < 94*(10<<16)/100 {
+		t.Errorf("expected drag to slow the particle down, got %v", p)
+	}
+
+	// With a palette, the color of a particle changes over its life.
+	s.Clear()
+	s.Palette = &HeatColors
+	s.Spawn(Particle{X: 1 << 16, Y: 1 << 16, Lifetime: time.Second})
+	s.Update(time.Second / 2)
+	canvas := NewCanvas(3, 3)
+	s.RenderCanvas(canvas)
+	if c := canvas.RGBAAt(1, 1); c != HeatColors.ColorAt(Scale8(127, 240)) {
+		t.Errorf("expected a color halfway through the palette, got %v", c)
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() {
+		s.Spawn(Particle{Lifetime: time.Millisecond})
+		s.Update(time.Millisecond)
+		s.Render(frame)
+	}); allocs != 0 {
+		t.Errorf("expected no allocations, got %.0f", allocs)
+	}
+}
+
+func TestParticleSystemInvalidLifetime(t *testing.T) {
+	// Particles that are already dead are dropped, so that rendering them
+	// before the next update doesn't divide by zero.
+	s := NewParticleSystem(4)
+	for _, p := range []Particle{
+		{Lifetime: 0},
+		{Lifetime: -time.Second},
+		{Age: time.Second, Lifetime: time.Second},
+		{Age: -time.Second, Lifetime: time.Second},
+	} {
+		if s.Spawn(p) {
+			t.Errorf("expected %+v to be dropped", p)
+		}
+	}
+	if len(s.Particles()) != 0 {
+		t.Errorf("expected no particles, got %d", len(s.Particles()))
+	}
+
+	// Particles changed through Particles are drawn without panicking.
+	s.Spawn(Particle{X: 1 << 16, Color: color.RGBA{R: 255}, Lifetime: time.Second})
+	s.Spawn(Particle{X: 2 << 16, Color: color.RGBA{R: 255}, Lifetime: time.Second})
+	s.Particles()[0].Age = 2 * time.Second
+	s.Particles()[1].Lifetime = 0
+	frame := make(Strip, 4)
+	s.Render(frame)
+	if !isBlack(frame) {
+		t.Errorf("expected dead particles to be black, got %v", frame)
+	}
+}
+
+func TestParticleSystemBackwards(t *testing.T) {
+	// Updating with a negative time step leaves the particles as they are.
+	s := NewParticleSystem(4)
+	s.Drag = 128
+	s.GravityX = 10 << 16
+	s.Spawn(Particle{X: 1 << 16, VX: 2 << 16, Age: 100 * time.Millisecond, Lifetime: time.Second})
+	before := s.Particles()[0]
+	s.Update(-time.Second)
+	s.Update(0)
+	if len(s.Particles()) != 1 || s.Particles()[0] != before {
+		t.Errorf("expected the particle to be unchanged, got %+v", s.Particles())
+	}
+	s.Update(time.Second)
+	if len(s.Particles()) != 0 {
+		t.Errorf("expected the particle to be removed at the end of its lifetime")
+	}
+}
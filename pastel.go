@@ -0,0 +1,72 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// Pastel returns the color made paler by mixing it with white, where amount is
+// the fraction of white: 0 returns the color unchanged and 255 returns white.
+// This raises the weakest channel the most, so the hue stays the same while
+// the saturation drops. It is the usual trick to get soft pastel colors from a
+// saturated palette.
+func Pastel(c color.RGBA, amount uint8) color.RGBA {
+	k := uint16(amount) + 1
+	channel := func(v uint8) uint8 {
+		return v + uint8(uint16(255-v)*k>>8)
+	}
+	return color.RGBA{channel(c.R), channel(c.G), channel(c.B), c.A}
+}
+
+// Vivid returns the color made more saturated, by removing amount/256 of the
+// weakest channel from all channels and stretching the result so that the
+// strongest channel stays the same. A amount of 255 removes the weakest
+// channel entirely, which results in a fully saturated color of the same hue
+// and (nearly) the same brightness. Gray colors turn black with high amounts,
+// as they have no hue to keep.
+func Vivid(c color.RGBA, amount uint8) color.RGBA {
+	lo := uint32(min(c.R, c.G, c.B))
+	hi := uint32(max(c.R, c.G, c.B))
+	remove := lo * (uint32(amount) + 1) >> 8
+	if remove == 0 {
+		return c
+	}
+	if remove == hi {
+		return color.RGBA{A: c.A}
+	}
+	stretch := hi << 16 / (hi - remove) // .16
+	channel := func(v uint8) uint8 {
+		return uint8(((uint32(v)-remove)*stretch + 0x8000) >> 16)
+	}
+	return color.RGBA{channel(c.R), channel(c.G), channel(c.B), c.A}
+}
+
+// Pastel returns the color with its saturation lowered by amount/256, see the
+// Pastel function. Spectrum keeps the brightness of a color the same for all
+// saturations, so unlike the Pastel function this doesn't make it brighter.
+func (c Color) Pastel(amount uint8) Color {
+	c.S = Scale8(c.S, 255-amount)
+	return c
+}
+
+// Vivid returns the color with the missing saturation reduced by amount/256,
+// see the Vivid function: 0 returns the color unchanged and 255 returns it
+// fully saturated.
+func (c Color) Vivid(amount uint8) Color {
+	c.S = Lerp8by8(c.S, 255, amount)
+	return c
+}
+
+// Pastel makes all colors of the palette paler, see the Pastel function.
+func (p *Palette16) Pastel(amount uint8) {
+	for i, c := range p {
+		p[i] = Pastel(c, amount)
+	}
+}
+
+// Vivid makes all colors of the palette more saturated, see the Vivid
+// function.
+func (p *Palette16) Vivid(amount uint8) {
+	for i, c := range p {
+		p[i] = Vivid(c, amount)
+	}
+}
@@ -0,0 +1,55 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestPastel(t *testing.T) {
+	for _, tc := range []struct {
+		c      color.RGBA
+		amount uint8
+		pastel color.RGBA
+		vivid  color.RGBA
+	}{
+		{color.RGBA{255, 0, 0, 0}, 0, color.RGBA{255, 0, 0, 0}, color.RGBA{255, 0, 0, 0}},
+		{color.RGBA{255, 0, 0, 0}, 128, color.RGBA{255, 128, 128, 0}, color.RGBA{255, 0, 0, 0}},
+		{color.RGBA{200, 100, 50, 7}, 255, color.RGBA{255, 255, 255, 7}, color.RGBA{200, 67, 0, 7}},
+		{color.RGBA{200, 100, 50, 0}, 128, color.RGBA{227, 178, 153, 0}, color.RGBA{200, 86, 29, 0}},
+		{color.RGBA{128, 128, 128, 0}, 255, color.RGBA{255, 255, 255, 0}, color.RGBA{}},
+		{color.RGBA{}, 100, color.RGBA{100, 100, 100, 0}, color.RGBA{}},
+	} {
+		if c := Pastel(tc.c, tc.amount); c != tc.pastel {
+			t.Errorf("Pastel(%v, %d): expected %v, got %v", tc.c, tc.amount, tc.pastel, c)
+		}
+		if c := Vivid(tc.c, tc.amount); c != tc.vivid {
+			t.Errorf("Vivid(%v, %d): expected %v, got %v", tc.c, tc.amount, tc.vivid, c)
+		}
+	}
+
+	// Pastel and vivid colors keep the hue.
+	for h := 0; h < 0x10000; h += 0x500 {
+		c := Color{H: uint16(h), S: 200, V: 255}
+		if got := c.Pastel(100); got.H != c.H || got.S >= c.S || got.V != c.V {
+			t.Errorf("%v.Pastel(100): got %v", c, got)
+		}
+		if got := c.Vivid(255); got.H != c.H || got.S != 255 || got.V != c.V {
+			t.Errorf("%v.Vivid(255): got %v", c, got)
+		}
+		rgb := Color{H: uint16(h), S: 180, V: 255}.Spectrum()
+		if hue, vivid := ColorFromRGBA(rgb).H, ColorFromRGBA(Vivid(rgb, 255)); int16(vivid.H-hue) > 0x100 || int16(vivid.H-hue) < -0x100 || vivid.S < 250 {
+			t.Errorf("Vivid(%v, 255): expected a saturated color of hue %d, got %v", rgb, hue, vivid)
+		}
+	}
+
+	p := NewPalette16(color.RGBA{255, 0, 0, 0}, color.RGBA{0, 0, 255, 0})
+	p.Pastel(255)
+	if p[0] != (color.RGBA{255, 255, 255, 0}) || p[15] != (color.RGBA{255, 255, 255, 0}) {
+		t.Errorf("Palette16.Pastel: got %v", p)
+	}
+	p = NewPalette16(color.RGBA{255, 100, 100, 0}, color.RGBA{50, 50, 100, 0})
+	p.Vivid(255)
+	if p[0] != (color.RGBA{255, 0, 0, 0}) || p[15] != (color.RGBA{0, 0, 100, 0}) {
+		t.Errorf("Palette16.Vivid: got %v", p)
+	}
+}
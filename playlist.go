@@ -0,0 +1,203 @@
+package ledsgo
+
+import (
+	"image/color"
+	"sync"
+	"time"
+)
+
+// PlaylistMode determines the order in which a Playlist plays its entries.
+type PlaylistMode uint8
+
+const (
+	// PlaylistLoop plays all entries in order and starts again at the first
+	// entry after the last.
+	PlaylistLoop PlaylistMode = iota
+
+	// PlaylistOnce plays all entries in order and keeps running the last
+	// entry once it is reached.
+	PlaylistOnce
+
+	// PlaylistShuffle plays all entries in a random order, in a new order
+	// every round.
+	PlaylistShuffle
+)
+
+// PlaylistEntry is an effect in a Playlist.
+type PlaylistEntry struct {
+	// New returns a new instance of the effect. A new instance is created
+	// every time the entry starts, so that it starts from the beginning.
+	New func() Effect
+
+	// Palette is the palette of the effect, for effects that are a
+	// StandardEffect. If it is nil, the palette of the playlist is used.
+	Palette *Palette16
+
+	// Duration is how long the entry plays, including the transition to it.
+	// Entries with a zero duration play until Next is called.
+	Duration time.Duration
+
+	// Transition is the duration of the cross-fade from the previous entry to
+	// this one. If it is zero, the effect changes immediately.
+	Transition time.Duration
+}
+
+// Playlist is an Effect that plays a list of effects one after another, so
+// that an installation can run a show without a controller. Every effect gets
+// the time since it started, so effects always start from the beginning.
+//
+// A Playlist is a StandardEffect: once they have been set, the standard
+// parameters are passed on to all entries, with the palette replaced by that
+// of the entry if it has one. Entries with a palette always get the standard
+// parameters.
+// All methods are safe for concurrent use.
+type Playlist struct {
+	lock    sync.Mutex
+	entries []PlaylistEntry
+	mode    PlaylistMode
+	rand    Rand
+	params  StandardParams
+	set     bool  // whether the standard parameters have been set
+	order   []int // order of the entries in this round
+	pos     int   // position in order of the current entry
+	started bool  // whether the first entry has been started
+
+	current, previous       Effect
+	currentBuf, previousBuf Strip
+	start, previousStart    time.Duration // start times of the current and previous entry
+	advance                 bool          // go to the next entry in the next frame
+}
+
+// NewPlaylist returns a new playlist with the given entries. The seed is used
+// for the random order in the shuffle mode.
+func NewPlaylist(mode PlaylistMode, seed uint32, entries ...PlaylistEntry) *Playlist {
+	p := &Playlist{
+		entries: entries,
+		mode:    mode,
+		params:  DefaultStandardParams(),
+		order:   make([]int, len(entries)),
+	}
+	p.rand.Seed(seed)
+	p.newRound()
+	return p
+}
+
+// Current returns the index of the entry that is playing, or transitioning in.
+func (p *Playlist) Current() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.order) == 0 {
+		return -1
+	}
+	return p.order[p.pos]
+}
+
+// Next goes to the next entry at the next frame, like when the current entry
+// ends.
+func (p *Playlist) Next() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.advance = true
+}
+
+// SetStandardParams implements StandardEffect.
+func (p *Playlist) SetStandardParams(params StandardParams) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.params = params
+	p.set = true
+	if len(p.order) != 0 {
+		p.applyParams(p.current, &p.entries[p.order[p.pos]])
+	}
+}
+
+// applyParams passes the standard parameters to the effect of the entry, if it
+// uses them.
+func (p *Playlist) applyParams(effect Effect, entry *PlaylistEntry) {
+	if e, ok := effect.(StandardEffect); ok && (p.set || entry.Palette != nil) {
+		params := p.params
+		if entry.Palette != nil {
+			params.Palette = entry.Palette
+		}
+		e.SetStandardParams(params)
+	}
+}
+
+// newRound fills the order of the entries for the next round.
+func (p *Playlist) newRound() {
+	last := -1
+	if p.started {
+		last = p.order[len(p.order)-1]
+	}
+	for i := range p.order {
+		p.order[i] = i
+	}
+	if p.mode == PlaylistShuffle {
+		for i := len(p.order) - 1; i > 0; i-- {
+			j := p.rand.Intn(i + 1)
+			p.order[i], p.order[j] = p.order[j], p.order[i]
+		}
+		// Don't play the same entry twice in a row.
+		if len(p.order) > 1 && p.order[0] == last {
+			p.order[0], p.order[1] = p.order[1], p.order[0]
+		}
+	}
+	p.pos = 0
+}
+
+// startEntry starts the current entry at time t.
+func (p *Playlist) startEntry(t time.Duration) {
+	entry := &p.entries[p.order[p.pos]]
+	p.previous, p.current = p.current, entry.New()
+	p.previousBuf, p.currentBuf = p.currentBuf, p.previousBuf
+	p.currentBuf.FillSolid(color.RGBA{})
+	p.applyParams(p.current, entry)
+	p.previousStart, p.start = p.start, t
+	if entry.Transition <= 0 {
+		p.previous = nil
+	}
+}
+
+// Render implements Effect.
+func (p *Playlist) Render(frame Strip, t time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.entries) == 0 {
+		frame.FillSolid(color.RGBA{})
+		return
+	}
+	if len(p.currentBuf) != len(frame) {
+		p.currentBuf = make(Strip, len(frame))
+		p.previousBuf = make(Strip, len(frame))
+	}
+
+	if !p.started {
+		p.startEntry(t)
+		p.started = true
+	}
+	if duration := p.entries[p.order[p.pos]].Duration; p.advance || (duration > 0 && t-p.start >= duration) {
+		p.advance = false
+		switch {
+		case p.pos+1 < len(p.order):
+			p.pos++
+			p.startEntry(t)
+		case p.mode != PlaylistOnce:
+			p.newRound()
+			p.startEntry(t)
+		}
+	}
+
+	entry := &p.entries[p.order[p.pos]]
+	p.current.Render(p.currentBuf, t-p.start)
+	elapsed := t - p.start
+	if p.previous == nil || elapsed >= entry.Transition {
+		p.previous = nil
+		copy(frame, p.currentBuf)
+		return
+	}
+	p.previous.Render(p.previousBuf, t-p.previousStart)
+	frac := uint8(elapsed * 256 / entry.Transition)
+	for i := range frame {
+		frame[i] = Blend(p.previousBuf[i], p.currentBuf[i], frac)
+	}
+}
@@ -0,0 +1,100 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+// timeEffect is an effect that renders its index and the time since it
+// started.
+func timeEffect(index uint8) func() Effect {
+	return func() Effect {
+		return EffectFunc(func(frame Strip, t time.Duration) {
+			frame.FillSolid(color.RGBA{R: index * 100, G: uint8(t / time.Second)})
+		})
+	}
+}
+
+func TestPlaylist(t *testing.T) {
+	p := NewPlaylist(PlaylistLoop, 1,
+		PlaylistEntry{New: timeEffect(0), Duration: 10 * time.Second},
+		PlaylistEntry{New: timeEffect(1), Duration: 10 * time.Second, Transition: 4 * time.Second},
+		PlaylistEntry{New: timeEffect(2), Duration: 5 * time.Second},
+	)
+	frame := make(Strip, 2)
+	for _, tc := range []struct {
+		t       time.Duration
+		current int
+		c       color.RGBA
+	}{
+		{100 * time.Second, 0, color.RGBA{R: 0, G: 0}}, // starts at the first frame
+		{105 * time.Second, 0, color.RGBA{R: 0, G: 5}},
+		{110 * time.Second, 1, color.RGBA{R: 0, G: 10}}, // transition starts
+		{112 * time.Second, 1, Blend(color.RGBA{R: 0, G: 12}, color.RGBA{R: 100, G: 2}, 128)},
+		{114 * time.Second, 1, color.RGBA{R: 100, G: 4}},
+		{120 * time.Second, 2, color.RGBA{R: 200, G: 0}},
+		{125 * time.Second, 0, color.RGBA{R: 0, G: 0}}, // loops
+	} {
+		p.Render(frame, tc.t)
+		if current := p.Current(); current != tc.current {
+			t.Errorf("%v: expected entry %d, got %d", tc.t, tc.current, current)
+		}
+		if frame[1] != tc.c {
+			t.Errorf("%v: expected %v, got %v", tc.t, tc.c, frame[1])
+		}
+	}
+
+	// Next skips to the next entry.
+	p.Next()
+	p.Render(frame, 126*time.Second)
+	if p.Current() != 1 {
+		t.Errorf("Next: expected entry 1, got %d", p.Current())
+	}
+
+	// The once mode stays at the last entry.
+	p = NewPlaylist(PlaylistOnce, 1,
+		PlaylistEntry{New: timeEffect(0), Duration: time.Second},
+		PlaylistEntry{New: timeEffect(1), Duration: time.Second},
+	)
+	for i := 0; i < 5; i++ {
+		p.Render(frame, time.Duration(i)*time.Second)
+	}
+	if p.Current() != 1 || frame[0] != (color.RGBA{R: 100, G: 3}) {
+		t.Errorf("once: expected to stay at the last entry, got entry %d and %v", p.Current(), frame[0])
+	}
+
+	// The shuffle mode plays every entry once per round, and never the same
+	// entry twice in a row.
+	var entries []PlaylistEntry
+	for i := 0; i < 5; i++ {
+		entries = append(entries, PlaylistEntry{New: timeEffect(uint8(i)), Duration: time.Second})
+	}
+	p = NewPlaylist(PlaylistShuffle, 3, entries...)
+	last := -1
+	for round := 0; round < 10; round++ {
+		var seen [5]bool
+		for i := 0; i < 5; i++ {
+			p.Render(frame, time.Duration(round*5+i)*time.Second)
+			current := p.Current()
+			if seen[current] || current == last {
+				t.Errorf("shuffle: entry %d played twice", current)
+			}
+			seen[current] = true
+			last = current
+		}
+	}
+
+	// The palette of an entry replaces the palette of the playlist.
+	blue := NewPalette16(color.RGBA{B: 77})
+	effect := &speedEffect{}
+	p = NewPlaylist(PlaylistLoop, 1, PlaylistEntry{New: func() Effect { return effect }, Palette: &blue})
+	p.SetStandardParams(StandardParams{Speed: 9, Palette: &RainbowColors})
+	p.Render(frame, 0)
+	if frame[0] != (color.RGBA{R: 9, B: 77}) {
+		t.Errorf("palette: unexpected color %v", frame[0])
+	}
+	if allocs := testing.AllocsPerRun(10, func() { p.Render(frame, time.Second) }); allocs != 0 {
+		t.Errorf("expected no allocations, got %.0f", allocs)
+	}
+}
@@ -0,0 +1,163 @@
+package ledsgo
+
+// PowerModel describes the electrical characteristics of a single LED, so that
+// the current and power of a whole frame can be estimated. Currents are in
+// milliamps. Different types of LEDs (and even different batches of the same
+// type) vary a lot, so for accurate budgets it is best to measure the current
+// of a strip with all LEDs fully red, green and blue.
+type PowerModel struct {
+	Red     uint16 // current of the red channel at full brightness
+	Green   uint16 // current of the green channel at full brightness
+	Blue    uint16 // current of the blue channel at full brightness
+	Idle    uint16 // quiescent current of a LED, even when it is black
+	Voltage uint16 // supply voltage in millivolts
+}
+
+// WS2812Power is the power model of a typical 5V WS2812B LED, with the same
+// values as FastLED uses.
+var WS2812Power = PowerModel{Red: 16, Green: 11, Blue: 15, Idle: 1, Voltage: 5000}
+
+// Current returns the estimated current in milliamps of the frame. The
+// brightness of the LEDs is assumed to be linear in the color values, which
+// is true for the PWM used by almost all addressable LEDs.
+func (m PowerModel) Current(frame Strip) uint32 {
+	idle, dynamic := m.current(frame)
+	return idle + dynamic
+}
+
+// Power returns the estimated power in milliwatts of the frame.
+func (m PowerModel) Power(frame Strip) uint32 {
+	return uint32(uint64(m.Current(frame)) * uint64(m.Voltage) / 1000)
+}
+
+// current returns the quiescent and the dynamic (color dependent) current of
+// the frame in milliamps.
+func (m PowerModel) current(frame Strip) (idle, dynamic uint32) {
+	var r, g, b uint64
+	for _, c := range frame {
+		r += uint64(c.R)
+		g += uint64(c.G)
+		b += uint64(c.B)
+	}
+	dynamic = uint32((r*uint64(m.Red) + g*uint64(m.Green) + b*uint64(m.Blue) + 127) / 255)
+	return uint32(len(frame)) * uint32(m.Idle), dynamic
+}
+
+// PowerSegment assigns a power model to a range of LEDs in a frame.
+type PowerSegment struct {
+	Start int // index of the first LED
+	Count int // number of LEDs
+	Model PowerModel
+}
+
+// PowerLayout describes the electrical characteristics of an installation with
+// different kinds of LEDs, for example a 5V WS2812 strip and a 12V strip
+// connected to the same controller. LEDs that are not part of any segment are
+// assumed to draw no power.
+type PowerLayout []PowerSegment
+
+// Power returns the estimated total power in milliwatts of the frame.
+func (l PowerLayout) Power(frame Strip) uint32 {
+	idle, dynamic := l.power(frame)
+	return idle + dynamic
+}
+
+// power returns the quiescent and the dynamic power of the frame in
+// milliwatts.
+func (l PowerLayout) power(frame Strip) (idle, dynamic uint32) {
+	for _, seg := range l {
+		start := min(max(seg.Start, 0), len(frame))
+		end := min(max(seg.Start+seg.Count, start), len(frame))
+		i, d := seg.Model.current(frame[start:end])
+		idle += uint32(uint64(i) * uint64(seg.Model.Voltage) / 1000)
+		dynamic += uint32(uint64(d) * uint64(seg.Model.Voltage) / 1000)
+	}
+	return idle, dynamic
+}
+
+// PowerLimiter is a Displayer that limits the current drawn by the LEDs to a
+// maximum, like setMaxPowerInMilliWatts in FastLED. Frames that would draw
+// more current are dimmed just enough to stay within the budget before they
+// are sent to the underlying displayer. This protects USB power supplies and
+// thin wiring, without limiting the brightness of effects that don't need
+// much power.
+//
+// By default, the whole frame uses Model and MaxCurrent is the budget. When
+// Layout is set, it is used instead and MaxPower is the budget.
+type PowerLimiter struct {
+	Model      PowerModel
+	MaxCurrent uint32 // maximum current in milliamps
+
+	Layout   PowerLayout
+	MaxPower uint32 // maximum power in milliwatts, when using Layout
+
+	displayer Displayer
+	buf       Strip
+	scale     uint8
+}
+
+// NewPowerLimiter returns a new PowerLimiter that sends frames to the given
+// displayer.
+func NewPowerLimiter(displayer Displayer, model PowerModel, maxCurrent uint32) *PowerLimiter {
+	return &PowerLimiter{
+		Model:      model,
+		MaxCurrent: maxCurrent,
+		displayer:  displayer,
+		scale:      255,
+	}
+}
+
+// NewLayoutPowerLimiter returns a new PowerLimiter for an installation with
+// different kinds of LEDs, with a budget in milliwatts.
+func NewLayoutPowerLimiter(displayer Displayer, layout PowerLayout, maxPower uint32) *PowerLimiter {
+	return &PowerLimiter{
+		Layout:    layout,
+		MaxPower:  maxPower,
+		displayer: displayer,
+		scale:     255,
+	}
+}
+
+// Display sends the frame to the underlying displayer, dimmed if needed.
+func (l *PowerLimiter) Display(frame Strip) error {
+	l.scale = l.limit(frame)
+	if l.scale == 255 {
+		return l.displayer.Display(frame)
+	}
+	if len(l.buf) != len(frame) {
+		l.buf = make(Strip, len(frame))
+	}
+	scaleStrip(l.buf, frame, l.scale)
+	return l.displayer.Display(l.buf)
+}
+
+// Scale returns the brightness scale that was applied to the last frame, where
+// 255 means the frame was not dimmed.
+func (l *PowerLimiter) Scale() uint8 {
+	return l.scale
+}
+
+// limit calculates the brightness scale needed to stay within the budget.
+func (l *PowerLimiter) limit(frame Strip) uint8 {
+	var idle, dynamic, budget uint32
+	if l.Layout != nil {
+		idle, dynamic = l.Layout.power(frame)
+		budget = l.MaxPower
+	} else {
+		idle, dynamic = l.Model.current(frame)
+		budget = l.MaxCurrent
+	}
+	if idle+dynamic <= budget {
+		return 255
+	}
+	if budget <= idle {
+		return 0 // not even enough for black LEDs
+	}
+	// Scale8 multiplies by (scale+1)/256, so find the largest scale for which
+	// the dynamic part times (scale+1)/256 stays within the budget.
+	scale := uint64(budget-idle) * 256 / uint64(dynamic)
+	if scale == 0 {
+		return 0 // even the lowest scale would exceed the budget
+	}
+	return uint8(min(scale-1, 254))
+}
@@ -0,0 +1,103 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestPowerModelCurrent(t *testing.T) {
+	frame := make(Strip, 60)
+	if current := WS2812Power.Current(frame); current != 60 {
+		t.Errorf("black frame: expected 60mA, got %dmA", current)
+	}
+	frame.FillSolid(color.RGBA{R: 255, G: 255, B: 255})
+	if current := WS2812Power.Current(frame); current != 60*(16+11+15+1) {
+		t.Errorf("white frame: expected %dmA, got %dmA", 60*(16+11+15+1), current)
+	}
+	frame.FillSolid(color.RGBA{R: 128})
+	if current := WS2812Power.Current(frame); current != 60+482 {
+		t.Errorf("half red frame: expected %dmA, got %dmA", 60+482, current)
+	}
+}
+
+func TestPowerLimiter(t *testing.T) {
+	rec := &frameRecorder{}
+	l := NewPowerLimiter(rec, WS2812Power, 1000)
+	frame := make(Strip, 60)
+
+	// A dim frame isn't changed.
+	frame.FillSolid(color.RGBA{R: 20, G: 20, B: 20})
+	l.Display(frame)
+	if l.Scale() != 255 || rec.frames[0][0] != frame[0] {
+		t.Errorf("dim frame: expected no change, got scale %d and color %v", l.Scale(), rec.frames[0][0])
+	}
+
+	// A white frame would draw 2580mA and must be dimmed.
+	frame.FillSolid(color.RGBA{R: 255, G: 255, B: 255})
+	l.Display(frame)
+	limited := rec.frames[1]
+	current := WS2812Power.Current(limited)
+	if current > 1000 || current < 950 {
+		t.Errorf("white frame: expected just below 1000mA, got %dmA (scale %d)", current, l.Scale())
+	}
+	if frame[0].R != 255 {
+		t.Error("the original frame was modified")
+	}
+
+	// A budget below the idle current results in black.
+	l.MaxCurrent = 10
+	l.Display(frame)
+	if l.Scale() != 0 || !isBlack(rec.frames[2]) {
+		t.Errorf("tiny budget: expected a black frame, got scale %d", l.Scale())
+	}
+
+	// A budget just above the idle current leaves no room for any light.
+	frame = make(Strip, 100)
+	frame.FillSolid(color.RGBA{R: 255, G: 255, B: 255})
+	l.MaxCurrent = 101
+	l.Display(frame)
+	if current := WS2812Power.Current(rec.frames[3]); l.Scale() != 0 || current > 101 {
+		t.Errorf("budget just above idle: expected scale 0 within 101mA, got scale %d and %dmA", l.Scale(), current)
+	}
+}
+
+func TestPowerLayout(t *testing.T) {
+	strip12V := PowerModel{Red: 5, Green: 5, Blue: 5, Idle: 2, Voltage: 12000}
+	layout := PowerLayout{
+		{Start: 0, Count: 10, Model: WS2812Power},
+		{Start: 10, Count: 10, Model: strip12V},
+	}
+	frame := make(Strip, 20)
+	if power := layout.Power(frame); power != 10*5+10*24 {
+		t.Errorf("black frame: expected %dmW, got %dmW", 10*5+10*24, power)
+	}
+	frame.FillSolid(color.RGBA{R: 255, G: 255, B: 255})
+	if power := layout.Power(frame); power != 10*43*5+10*17*12 {
+		t.Errorf("white frame: expected %dmW, got %dmW", 10*43*5+10*17*12, power)
+	}
+	if power := WS2812Power.Power(frame[:10]); power != 10*43*5 {
+		t.Errorf("WS2812 segment: expected %dmW, got %dmW", 10*43*5, power)
+	}
+
+	// A layout that is longer than the frame only counts LEDs in the frame.
+	if power := layout.Power(frame[:15]); power != 10*43*5+5*17*12 {
+		t.Errorf("short frame: expected %dmW, got %dmW", 10*43*5+5*17*12, power)
+	}
+
+	// Segments that start before the frame or have a negative length only
+	// count LEDs in the frame.
+	invalid := PowerLayout{
+		{Start: -5, Count: 10, Model: WS2812Power},
+		{Start: 15, Count: -3, Model: strip12V},
+	}
+	if power := invalid.Power(frame); power != 5*43*5 {
+		t.Errorf("invalid segments: expected %dmW, got %dmW", 5*43*5, power)
+	}
+
+	rec := &frameRecorder{}
+	l := NewLayoutPowerLimiter(rec, layout, 2000)
+	l.Display(frame)
+	if power := layout.Power(rec.frames[0]); power > 2000 || power < 1900 {
+		t.Errorf("limited frame: expected just below 2000mW, got %dmW (scale %d)", power, l.Scale())
+	}
+}
@@ -0,0 +1,68 @@
+// Package preview renders effects to image files, so that they can be
+// reviewed visually (or compared against earlier renders) without any LED
+// hardware.
+package preview
+
+import (
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// StripImage renders the given number of frames of the effect, starting at
+// time zero and advancing by interval for every frame. The result is an image
+// with one column per LED and one row per frame, which shows the whole
+// animation of a strip at a glance.
+func StripImage(effect ledsgo.Effect, numLEDs, frames int, interval time.Duration) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, numLEDs, frames))
+	frame := make(ledsgo.Strip, numLEDs)
+	for y := 0; y < frames; y++ {
+		effect.Render(frame, time.Duration(y)*interval)
+		for x, c := range frame {
+			c.A = 0xff
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// WritePNG renders the effect like StripImage and writes the result as a PNG
+// image.
+func WritePNG(w io.Writer, effect ledsgo.Effect, numLEDs, frames int, interval time.Duration) error {
+	return png.Encode(w, StripImage(effect, numLEDs, frames, interval))
+}
+
+// WriteGIF renders the given number of frames of the effect and writes them as
+// an animated GIF. The LEDs are laid out as a matrix of the given size, row by
+// row (use a height of 1 for a strip). Every LED is drawn as a square of scale
+// by scale pixels. Colors are reduced to the standard Plan 9 palette, as GIF
+// images are limited to 256 colors.
+func WriteGIF(w io.Writer, effect ledsgo.Effect, width, height, frames int, interval time.Duration, scale int) error {
+	if scale < 1 {
+		scale = 1
+	}
+	frame := make(ledsgo.Strip, width*height)
+	canvas := &ledsgo.Canvas{Width: width, Height: height, Pix: frame}
+	bounds := image.Rect(0, 0, width*scale, height*scale)
+	delay := int(interval / (10 * time.Millisecond)) // in 100ths of a second
+	anim := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		effect.Render(frame, time.Duration(i)*interval)
+		img := image.NewPaletted(bounds, palette.Plan9)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				r := image.Rect(x*scale, y*scale, (x+1)*scale, (y+1)*scale)
+				draw.Draw(img, r, image.NewUniform(canvas.At(x, y)), image.Point{}, draw.Src)
+			}
+		}
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
@@ -0,0 +1,57 @@
+package preview
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// scanner lights up a single LED that moves one LED per 10ms.
+var scanner = ledsgo.EffectFunc(func(frame ledsgo.Strip, t time.Duration) {
+	frame.FillSolid(color.RGBA{})
+	frame[int(t/(10*time.Millisecond))%len(frame)] = color.RGBA{R: 255}
+})
+
+func TestWritePNG(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WritePNG(buf, scanner, 5, 3, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != 5 || size.Y != 3 {
+		t.Fatalf("unexpected image size: %v", size)
+	}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 5; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			if lit := r != 0; lit != (x == y) {
+				t.Errorf("pixel (%d, %d): unexpected color %v", x, y, img.At(x, y))
+			}
+		}
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WriteGIF(buf, scanner, 4, 2, 6, 50*time.Millisecond, 4); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 6 || anim.Delay[0] != 5 {
+		t.Errorf("unexpected animation: %d frames with a delay of %d", len(anim.Image), anim.Delay[0])
+	}
+	if size := anim.Image[0].Bounds().Size(); size.X != 16 || size.Y != 8 {
+		t.Errorf("unexpected image size: %v", size)
+	}
+}
@@ -0,0 +1,131 @@
+package ledsgo
+
+import (
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// Profiler measures how long effects take to render, to find out which layer
+// of a composed show uses up the frame budget. Wrap every effect (or layer)
+// that should be measured with Wrap, and read the results with Stats, for
+// example from a web server or at the end of a preview. All methods are safe
+// for concurrent use.
+//
+// Wrapped effects may be nested, in which case the render time of the outer
+// effect includes that of the inner effects.
+type Profiler struct {
+	lock    sync.Mutex
+	source  func() time.Duration
+	effects []*profiledEffect
+}
+
+// RenderStats contains the render times of an effect wrapped by a Profiler.
+type RenderStats struct {
+	Name   string
+	Frames uint64        // number of rendered frames
+	Pixels uint64        // total number of rendered pixels
+	Total  time.Duration // total render time
+	Last   time.Duration // render time of the last frame
+	Max    time.Duration // longest render time
+}
+
+// Average returns the average render time of a frame.
+func (s RenderStats) Average() time.Duration {
+	if s.Frames == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Frames)
+}
+
+// PixelsPerSecond returns the number of pixels the effect renders per second
+// of render time. This is the maximum speed of the effect if nothing else had
+// to be done, which makes it easy to compare effects and layers.
+func (s RenderStats) PixelsPerSecond() uint64 {
+	if s.Total <= 0 {
+		return 0
+	}
+	// Calculate with 128 bits, as pixels * 1e9 easily overflows 64 bits.
+	hi, lo := bits.Mul64(s.Pixels, uint64(time.Second))
+	if hi >= uint64(s.Total) {
+		return 1<<64 - 1 // doesn't fit in 64 bits
+	}
+	pps, _ := bits.Div64(hi, lo, uint64(s.Total))
+	return pps
+}
+
+// NewProfiler returns a new profiler. The source returns the current time and
+// must be monotonic, like the source of a Clock. If it is nil, the time since
+// the creation of the profiler is used.
+func NewProfiler(source func() time.Duration) *Profiler {
+	if source == nil {
+		start := time.Now()
+		source = func() time.Duration {
+			return time.Since(start)
+		}
+	}
+	return &Profiler{
+		source: source,
+	}
+}
+
+// profiledEffect is an Effect returned by Profiler.Wrap.
+type profiledEffect struct {
+	profiler *Profiler
+	effect   Effect
+	stats    RenderStats
+}
+
+// Wrap returns an effect that renders the given effect and records how long it
+// took under the given name.
+func (p *Profiler) Wrap(name string, effect Effect) Effect {
+	e := &profiledEffect{
+		profiler: p,
+		effect:   effect,
+		stats:    RenderStats{Name: name},
+	}
+	p.lock.Lock()
+	p.effects = append(p.effects, e)
+	p.lock.Unlock()
+	return e
+}
+
+// Render implements Effect.
+func (e *profiledEffect) Render(frame Strip, t time.Duration) {
+	p := e.profiler
+	start := p.source()
+	e.effect.Render(frame, t)
+	duration := p.source() - start
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	e.stats.Frames++
+	e.stats.Pixels += uint64(len(frame))
+	e.stats.Total += duration
+	e.stats.Last = duration
+	if duration > e.stats.Max {
+		e.stats.Max = duration
+	}
+}
+
+// Stats appends the statistics of all wrapped effects to stats, in the order
+// in which they were wrapped, and returns the result. It doesn't allocate
+// memory if stats has enough capacity.
+func (p *Profiler) Stats(stats []RenderStats) []RenderStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, e := range p.effects {
+		stats = append(stats, e.stats)
+	}
+	return stats
+}
+
+// Reset clears the statistics of all wrapped effects, for example to measure
+// a different part of a show.
+func (p *Profiler) Reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, e := range p.effects {
+		e.stats = RenderStats{Name: e.stats.Name}
+	}
+}
@@ -0,0 +1,54 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProfiler(t *testing.T) {
+	var now time.Duration
+	p := NewProfiler(func() time.Duration { return now })
+	slow := p.Wrap("slow", EffectFunc(func(frame Strip, t time.Duration) {
+		now += 3 * time.Millisecond
+	}))
+	fast := p.Wrap("fast", EffectFunc(func(frame Strip, t time.Duration) {
+		now += time.Millisecond
+	}))
+	outer := p.Wrap("outer", EffectFunc(func(frame Strip, t time.Duration) {
+		slow.Render(frame, t)
+		fast.Render(frame, t)
+	}))
+
+	frame := make(Strip, 100)
+	for i := 0; i < 4; i++ {
+		outer.Render(frame, 0)
+	}
+	stats := p.Stats(nil)
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 stats, got %d", len(stats))
+	}
+	for i, expected := range []RenderStats{
+		{Name: "slow", Frames: 4, Pixels: 400, Total: 12 * time.Millisecond, Last: 3 * time.Millisecond, Max: 3 * time.Millisecond},
+		{Name: "fast", Frames: 4, Pixels: 400, Total: 4 * time.Millisecond, Last: time.Millisecond, Max: time.Millisecond},
+		{Name: "outer", Frames: 4, Pixels: 400, Total: 16 * time.Millisecond, Last: 4 * time.Millisecond, Max: 4 * time.Millisecond},
+	} {
+		if stats[i] != expected {
+			t.Errorf("stats %d: expected %+v, got %+v", i, expected, stats[i])
+		}
+	}
+	if avg := stats[0].Average(); avg != 3*time.Millisecond {
+		t.Errorf("average: expected 3ms, got %v", avg)
+	}
+	if pps := stats[1].PixelsPerSecond(); pps != 100000 {
+		t.Errorf("pixels per second: expected 100000, got %d", pps)
+	}
+
+	p.Reset()
+	stats = p.Stats(stats[:0])
+	if stats[2] != (RenderStats{Name: "outer"}) {
+		t.Errorf("after reset: expected empty stats, got %+v", stats[2])
+	}
+	if stats[2].Average() != 0 || stats[2].PixelsPerSecond() != 0 {
+		t.Errorf("after reset: expected zero average and speed")
+	}
+}
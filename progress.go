@@ -0,0 +1,146 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+// ProgressThreshold sets the color of a Progress bar once a part of its
+// duration has passed.
+type ProgressThreshold struct {
+	At    uint16 // part of the duration that has passed, .16
+	Color color.RGBA
+}
+
+// DefaultProgressThresholds are the colors of a Progress bar without its own
+// thresholds: green for the first half, amber until 80% and red for the rest.
+var DefaultProgressThresholds = []ProgressThreshold{
+	{0, color.RGBA{0, 255, 0, 0}},
+	{0x8000, color.RGBA{255, 128, 0, 0}},
+	{0xcccd, color.RGBA{255, 0, 0, 0}},
+}
+
+// Progress is an Effect that shows a progress bar that fills the strip over
+// the configured duration, or a countdown that empties it, for example for a
+// kitchen timer or a build that usually takes a known time. The color of the
+// bar changes as the time runs out, and the LED at the end of the bar is
+// partly lit so that it moves smoothly.
+//
+// The duration starts at the first call to Render, or at the first call after
+// Reset.
+type Progress struct {
+	Duration time.Duration
+
+	// Countdown makes the bar start full and empty over the duration, instead
+	// of starting empty and filling the strip.
+	Countdown bool
+
+	// Reverse makes the bar grow (or shrink) towards the first LED instead of
+	// the last LED.
+	Reverse bool
+
+	// Thresholds are the colors of the bar, sorted by the part of the
+	// duration at which they start. If it is nil, DefaultProgressThresholds
+	// is used.
+	Thresholds []ProgressThreshold
+
+	// Background is the color of the LEDs not covered by the bar.
+	Background color.RGBA
+
+	// Flash makes the whole strip flash in the last color once the time is up.
+	Flash bool
+
+	start   time.Duration
+	started bool
+}
+
+// Reset restarts the duration at the next call to Render.
+func (p *Progress) Reset() {
+	p.started = false
+}
+
+// Elapsed returns the part of the duration that has passed at time t as a .16
+// fixed-point value, where 0xffff means the time is up.
+func (p *Progress) Elapsed(t time.Duration) uint16 {
+	if !p.started {
+		return 0
+	}
+	elapsed := t - p.start
+	if elapsed >= p.Duration {
+		return 0xffff
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+	return durationFraction(elapsed, p.Duration)
+}
+
+// Color returns the color of the bar when the given part of the duration has
+// passed.
+func (p *Progress) Color(elapsed uint16) color.RGBA {
+	thresholds := p.Thresholds
+	if thresholds == nil {
+		thresholds = DefaultProgressThresholds
+	}
+	var c color.RGBA
+	for _, threshold := range thresholds {
+		if elapsed < threshold.At {
+			break
+		}
+		c = threshold.Color
+	}
+	return c
+}
+
+// Params implements ParamEffect. The duration and colors aren't parameters,
+// as they are usually set by the program that starts the countdown.
+func (p *Progress) Params() []Param {
+	return []Param{
+		{Name: "Countdown", Min: 0, Max: 1, Default: 0, Value: &p.Countdown},
+		{Name: "Reverse", Min: 0, Max: 1, Default: 0, Value: &p.Reverse},
+		{Name: "Flash", Min: 0, Max: 1, Default: 0, Value: &p.Flash},
+	}
+}
+
+// Render implements Effect.
+func (p *Progress) Render(frame Strip, t time.Duration) {
+	if !p.started {
+		p.start = t
+		p.started = true
+	}
+	elapsed := p.Elapsed(t)
+	c := p.Color(elapsed)
+	if elapsed == 0xffff && p.Flash {
+		// Flash twice a second, counting from the moment the time was up.
+		if (t-p.start-p.Duration)%(500*time.Millisecond) < 250*time.Millisecond {
+			frame.FillSolid(c)
+		} else {
+			frame.FillSolid(p.Background)
+		}
+		return
+	}
+
+	filled := uint32(elapsed)
+	if elapsed == 0xffff {
+		filled = 0x10000
+	}
+	if p.Countdown {
+		filled = 0x10000 - filled
+	}
+	length := (filled*uint32(len(frame)) + 0x80) >> 8 // .8
+	for i := range frame {
+		covered := min(max(int32(length)-int32(i)<<8, 0), 0x100) // .8
+		led := i
+		if p.Reverse {
+			led = len(frame) - 1 - i
+		}
+		switch covered {
+		case 0:
+			frame[led] = p.Background
+		case 0x100:
+			frame[led] = c
+		default:
+			frame[led] = Blend(p.Background, c, uint8(covered))
+		}
+	}
+}
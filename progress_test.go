@@ -0,0 +1,68 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	green, red := color.RGBA{G: 255}, color.RGBA{R: 255}
+	p := &Progress{Duration: 10 * time.Second}
+	frame := make(Strip, 10)
+
+	// The duration starts at the first render.
+	p.Render(frame, 5*time.Second)
+	if !isBlack(frame) {
+		t.Errorf("start: expected an empty bar, got %v", frame)
+	}
+	p.Render(frame, 8*time.Second+500*time.Millisecond)
+	if frame[0] != green || frame[2] != green || frame[3].G < 120 || frame[3].G > 135 || !isBlack(frame[4:]) {
+		t.Errorf("35%%: unexpected bar %v", frame)
+	}
+	p.Render(frame, 14*time.Second)
+	if frame[8] != red || !isBlack(frame[9:]) {
+		t.Errorf("90%%: expected a red bar of 9 LEDs, got %v", frame)
+	}
+	p.Render(frame, time.Minute)
+	if frame[9] != red {
+		t.Errorf("done: expected a full bar, got %v", frame)
+	}
+
+	// A countdown empties the strip, here towards the first LED.
+	p = &Progress{Duration: 10 * time.Second, Countdown: true, Reverse: true, Flash: true}
+	p.Render(frame, 0)
+	for _, c := range frame {
+		if c != green {
+			t.Fatalf("start: expected a full bar, got %v", frame)
+		}
+	}
+	p.Render(frame, 6*time.Second)
+	if !isBlack(frame[:6]) || frame[6] != (color.RGBA{255, 128, 0, 0}) {
+		t.Errorf("60%%: unexpected bar %v", frame)
+	}
+	p.Render(frame, 10*time.Second+100*time.Millisecond)
+	if frame[0] != red || frame[9] != red {
+		t.Errorf("done: expected the strip to flash, got %v", frame)
+	}
+	p.Render(frame, 10*time.Second+300*time.Millisecond)
+	if !isBlack(frame) {
+		t.Errorf("done: expected the strip to flash, got %v", frame)
+	}
+
+	// Restarting the countdown.
+	p.Reset()
+	p.Render(frame, 20*time.Second)
+	if p.Elapsed(25*time.Second) != 0x8000 || frame[0] != green {
+		t.Errorf("reset: unexpected state %v", frame)
+	}
+}
+
+func TestProgressLongDuration(t *testing.T) {
+	// A countdown of weeks doesn't overflow.
+	p := &Progress{Duration: 28 * 24 * time.Hour}
+	p.Render(make(Strip, 4), 0)
+	if elapsed := p.Elapsed(14 * 24 * time.Hour); elapsed != 0x8000 {
+		t.Errorf("expected half of the duration to have passed, got %#x", elapsed)
+	}
+}
@@ -0,0 +1,52 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// ColorDistance returns the squared perceptual distance between the colors a
+// and b, ignoring the alpha channel. It uses the "redmean" approximation: the
+// differences of the channels are weighted depending on how red the colors
+// are, which is much closer to how humans see color differences than the
+// plain (Euclidean) RGB distance while being just as cheap to calculate.
+func ColorDistance(a, b color.RGBA) uint32 {
+	rmean := (int32(a.R) + int32(b.R)) / 2
+	dr := int32(a.R) - int32(b.R)
+	dg := int32(a.G) - int32(b.G)
+	db := int32(a.B) - int32(b.B)
+	return uint32((512+rmean)*dr*dr>>8 + 4*dg*dg + (767-rmean)*db*db>>8)
+}
+
+// NearestColor returns the index of the color in the palette that is closest
+// to c according to ColorDistance, or -1 if the palette is empty. If multiple
+// colors are equally close, the first is returned. This can be used to play
+// back images on outputs with a limited palette, or as a posterize effect.
+// Use Color.Spectrum to find the nearest color for an HSV color.
+func NearestColor(palette []color.RGBA, c color.RGBA) int {
+	nearest := -1
+	var best uint32
+	for i, p := range palette {
+		if d := ColorDistance(c, p); nearest < 0 || d < best {
+			nearest, best = i, d
+		}
+	}
+	return nearest
+}
+
+// Nearest returns the index of the palette entry that is closest to c, see
+// NearestColor.
+func (p *Palette16) Nearest(c color.RGBA) int {
+	return NearestColor(p[:], c)
+}
+
+// Quantize replaces every color in the strip with the nearest color in the
+// palette, see NearestColor. The strip is left unchanged if the palette is
+// empty.
+func (s Strip) Quantize(palette []color.RGBA) {
+	if len(palette) == 0 {
+		return
+	}
+	for i, c := range s {
+		s[i] = palette[NearestColor(palette, c)]
+	}
+}
@@ -0,0 +1,52 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestColorDistance(t *testing.T) {
+	black := color.RGBA{}
+	white := color.RGBA{255, 255, 255, 0}
+	if d := ColorDistance(black, black); d != 0 {
+		t.Errorf("distance to itself: expected 0, got %d", d)
+	}
+	if d1, d2 := ColorDistance(black, white), ColorDistance(white, black); d1 != d2 || d1 != 584970 {
+		t.Errorf("black to white: expected 584970 both ways, got %d and %d", d1, d2)
+	}
+	// Green differences are more visible than blue differences.
+	if ColorDistance(black, color.RGBA{G: 50}) <= ColorDistance(black, color.RGBA{B: 50}) {
+		t.Errorf("expected green to weigh more than blue")
+	}
+}
+
+func TestNearestColor(t *testing.T) {
+	palette := []color.RGBA{{}, {255, 0, 0, 0}, {0, 255, 0, 0}, {0, 0, 255, 0}, {255, 255, 255, 0}}
+	for _, tc := range []struct {
+		c     color.RGBA
+		index int
+	}{
+		{color.RGBA{10, 10, 10, 0}, 0},
+		{color.RGBA{200, 30, 40, 0}, 1},
+		{color.RGBA{100, 180, 90, 0}, 2},
+		{color.RGBA{20, 0, 160, 0}, 3},
+		{color.RGBA{200, 210, 220, 0}, 4},
+	} {
+		if index := NearestColor(palette, tc.c); index != tc.index {
+			t.Errorf("NearestColor(%v): expected %d, got %d", tc.c, tc.index, index)
+		}
+	}
+	if index := NearestColor(nil, color.RGBA{}); index != -1 {
+		t.Errorf("empty palette: expected -1, got %d", index)
+	}
+
+	if index := HeatColors.Nearest(HeatColors[9]); index != 9 {
+		t.Errorf("Palette16.Nearest: expected 9, got %d", index)
+	}
+
+	s := Strip{{10, 10, 10, 0}, {200, 30, 40, 0}}
+	s.Quantize(palette)
+	if s[0] != palette[0] || s[1] != palette[1] {
+		t.Errorf("Quantize: got %v", s)
+	}
+}
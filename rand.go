@@ -0,0 +1,107 @@
+package ledsgo
+
+import (
+	"math"
+)
+
+// Rand is a small and fast pseudo-random number generator, based on xorshift32.
+// It is not suitable for cryptographic purposes, but it is more than good
+// enough for visual effects. Because it is explicitly seeded, effects using it
+// are reproducible, which is useful in tests.
+//
+// The zero value is ready to use. A Rand is not safe for concurrent use.
+type Rand struct {
+	state uint32
+}
+
+// Default seed, used when the state would otherwise be zero (which is not a
+// valid state for xorshift).
+const defaultSeed = 0x9e3779b9
+
+// NewRand returns a new random number generator with the given seed.
+func NewRand(seed uint32) *Rand {
+	r := &Rand{}
+	r.Seed(seed)
+	return r
+}
+
+// Seed resets the generator to the state given by seed. The same seed always
+// results in the same sequence of numbers.
+func (r *Rand) Seed(seed uint32) {
+	if seed == 0 {
+		seed = defaultSeed
+	}
+	r.state = seed
+}
+
+// Uint32 returns a pseudo-random 32-bit value.
+func (r *Rand) Uint32() uint32 {
+	x := r.state
+	if x == 0 {
+		x = defaultSeed
+	}
+	x ^= x << 13
+	x ^= x >> 17
+	x ^= x << 5
+	r.state = x
+	return x
+}
+
+// Uint16 returns a pseudo-random 16-bit value.
+func (r *Rand) Uint16() uint16 {
+	return uint16(r.Uint32() >> 16) // the upper bits are the most random
+}
+
+// Uint8 returns a pseudo-random 8-bit value.
+func (r *Rand) Uint8() uint8 {
+	return uint8(r.Uint32() >> 24)
+}
+
+// Intn returns a pseudo-random number in the range [0, n). It panics if n <= 0
+// or if n doesn't fit in a uint32.
+// It uses a multiplication instead of a division, which is much faster on
+// small microcontrollers. The result is very slightly biased for large n.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 || uint64(n) > math.MaxUint32 {
+		panic("ledsgo: invalid argument to Intn")
+	}
+	return int(uint64(r.Uint32()) * uint64(uint32(n)) >> 32)
+}
+
+// Range8 returns a pseudo-random number in the range [low, high). If high is
+// not above low, it returns low.
+func (r *Rand) Range8(low, high uint8) uint8 {
+	if high <= low {
+		return low
+	}
+	return low + uint8(uint16(r.Uint8())*uint16(high-low)>>8)
+}
+
+// Range16 returns a pseudo-random number in the range [low, high). If high is
+// not above low, it returns low.
+func (r *Rand) Range16(low, high uint16) uint16 {
+	if high <= low {
+		return low
+	}
+	return low + uint16(uint32(r.Uint16())*uint32(high-low)>>16)
+}
+
+// The generator used by Random8 and Random16.
+var defaultRand Rand
+
+// Random8 returns a pseudo-random 8-bit value from a shared generator. It is
+// not safe for concurrent use: use a separate Rand for each goroutine instead.
+func Random8() uint8 {
+	return defaultRand.Uint8()
+}
+
+// Random16 returns a pseudo-random 16-bit value from a shared generator. It is
+// not safe for concurrent use: use a separate Rand for each goroutine instead.
+func Random16() uint16 {
+	return defaultRand.Uint16()
+}
+
+// RandomSeed seeds the generator used by Random8 and Random16.
+func RandomSeed(seed uint32) {
+	defaultRand.Seed(seed)
+}
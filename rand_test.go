@@ -0,0 +1,118 @@
+package ledsgo
+
+import (
+	"testing"
+)
+
+func TestRandRange(t *testing.T) {
+	r := NewRand(1)
+	var seen8 [256]bool
+	var seen16 [100]bool
+	for i := 0; i < 10000; i++ {
+		if v := r.Intn(7); v < 0 || v >= 7 {
+			t.Fatalf("Intn(7) returned %d", v)
+		}
+		v8 := r.Range8(10, 20)
+		if v8 < 10 || v8 >= 20 {
+			t.Fatalf("Range8(10, 20) returned %d", v8)
+		}
+		seen8[v8] = true
+		v16 := r.Range16(1000, 1100)
+		if v16 < 1000 || v16 >= 1100 {
+			t.Fatalf("Range16(1000, 1100) returned %d", v16)
+		}
+		seen16[v16-1000] = true
+		if v := r.Range8(0, 255); v == 255 {
+			t.Fatal("Range8(0, 255) returned 255")
+		}
+	}
+	for v := 10; v < 20; v++ {
+		if !seen8[v] {
+			t.Errorf("Range8(10, 20) never returned %d", v)
+		}
+	}
+	for v, seen := range seen16 {
+		if !seen {
+			t.Errorf("Range16(1000, 1100) never returned %d", v+1000)
+		}
+	}
+
+	// An empty range returns the lower bound.
+	if v := r.Range8(50, 50); v != 50 {
+		t.Errorf("Range8(50, 50): expected 50, got %d", v)
+	}
+	if v := r.Range8(50, 40); v != 50 {
+		t.Errorf("Range8(50, 40): expected 50, got %d", v)
+	}
+	if v := r.Range16(5000, 5000); v != 5000 {
+		t.Errorf("Range16(5000, 5000): expected 5000, got %d", v)
+	}
+	if v := r.Range16(5000, 4000); v != 5000 {
+		t.Errorf("Range16(5000, 4000): expected 5000, got %d", v)
+	}
+}
+
+func TestRandIntnPanics(t *testing.T) {
+	tooLarge := uint64(1) << 32 // 0 on 32-bit platforms, which panics too
+	for _, n := range []int{0, -1, int(tooLarge)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Intn(%d): expected a panic", n)
+				}
+			}()
+			NewRand(1).Intn(n)
+		}()
+	}
+}
+
+func TestRandSeed(t *testing.T) {
+	// Seed 0 and the zero value must not get stuck at zero, which is a fixed
+	// point of xorshift.
+	var zero Rand
+	for name, r := range map[string]*Rand{"seed 0": NewRand(0), "zero value": &zero} {
+		var prev uint32
+		for i := 0; i < 100; i++ {
+			v := r.Uint32()
+			if v == 0 || v == prev {
+				t.Fatalf("%s: stuck sequence at %#x", name, v)
+			}
+			prev = v
+		}
+	}
+
+	// The same seed gives the same sequence, and a different seed a
+	// different sequence.
+	a, b, c := NewRand(1234), NewRand(1234), NewRand(1235)
+	different := false
+	for i := 0; i < 100; i++ {
+		va, vb, vc := a.Uint32(), b.Uint32(), c.Uint32()
+		if va != vb {
+			t.Fatalf("value %d: expected the same value for the same seed, got %#x and %#x", i, va, vb)
+		}
+		if va != vc {
+			different = true
+		}
+	}
+	if !different {
+		t.Error("expected a different sequence for a different seed")
+	}
+
+	// Seed restarts the sequence, also of the shared generator.
+	first := a.Uint32()
+	a.Seed(1234)
+	for i := 0; i < 100; i++ {
+		a.Uint32()
+	}
+	if v := a.Uint32(); v != first {
+		t.Errorf("expected the sequence to restart after Seed, got %#x instead of %#x", v, first)
+	}
+	RandomSeed(99)
+	r := NewRand(99)
+	if v1, v2 := Random16(), r.Uint16(); v1 != v2 {
+		t.Errorf("Random16: expected %#x, got %#x", v2, v1)
+	}
+	if v1, v2 := Random8(), r.Uint8(); v1 != v2 {
+		t.Errorf("Random8: expected %#x, got %#x", v2, v1)
+	}
+}
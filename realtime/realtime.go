@@ -0,0 +1,126 @@
+// Package realtime implements the simple UDP realtime protocols supported by
+// WLED (WARLS, DRGB, DRGBW and DNRGB) and the raw UDP protocol of Hyperion.
+// These are used by ambient lighting software such as Hyperion, Prismatik and
+// LedFx, so that ledsgo devices can act as receivers for them.
+//
+// Every packet except for Hyperion packets starts with a protocol byte and a
+// timeout byte: the number of seconds to stay in realtime mode after the last
+// packet, or 255 to stay in realtime mode forever.
+package realtime
+
+import (
+	"encoding/binary"
+	"errors"
+	"image/color"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Port is the UDP port used by WLED for the realtime protocols.
+const Port = 21324
+
+// Protocol is the type of a realtime packet, as indicated by its first byte.
+type Protocol uint8
+
+// Protocols, as used by WLED.
+const (
+	// WARLS sends index, red, green, blue for each changed LED. It can
+	// only address the first 255 LEDs.
+	WARLS Protocol = 1
+
+	// DRGB sends red, green, blue for each LED starting at LED 0, up to 490
+	// LEDs.
+	DRGB Protocol = 2
+
+	// DRGBW sends red, green, blue, white for each LED starting at LED 0, up
+	// to 367 LEDs.
+	DRGBW Protocol = 3
+
+	// DNRGB sends a 16-bit start index followed by red, green, blue for each
+	// LED, up to 489 LEDs per packet.
+	DNRGB Protocol = 4
+
+	// Hyperion is the raw protocol used by Hyperion: red, green, blue for each
+	// LED starting at LED 0, without any header.
+	Hyperion Protocol = 0xff
+)
+
+// TimeoutForever is the timeout value to stay in realtime mode forever.
+const TimeoutForever = 255
+
+// Maximum number of LEDs per packet for each protocol, to stay within a single
+// Ethernet frame.
+const (
+	MaxWARLS = 255
+	MaxDRGB  = 490
+	MaxDRGBW = 367
+	MaxDNRGB = 489
+)
+
+var (
+	errInvalidPacket = errors.New("realtime: invalid packet")
+	errUnknown       = errors.New("realtime: unknown protocol")
+)
+
+// AppendPacket appends a packet of the given protocol to buf and returns the
+// resulting slice. The start index is only used by WARLS and DNRGB; the other
+// protocols always start at LED 0. The caller is responsible for keeping the
+// number of LEDs below the maximum for the protocol.
+func AppendPacket(buf []byte, protocol Protocol, timeout uint8, start int, frame ledsgo.Strip) []byte {
+	if protocol != Hyperion {
+		buf = append(buf, byte(protocol), timeout)
+	}
+	switch protocol {
+	case WARLS:
+		for i, c := range frame {
+			buf = append(buf, uint8(start+i), c.R, c.G, c.B)
+		}
+	case DRGB, Hyperion:
+		buf = ledsgo.OrderRGB.AppendStrip(buf, frame)
+	case DRGBW:
+		buf = ledsgo.OrderRGBW.AppendStrip(buf, frame)
+	case DNRGB:
+		buf = binary.BigEndian.AppendUint16(buf, uint16(start))
+		buf = ledsgo.OrderRGB.AppendStrip(buf, frame)
+	}
+	return buf
+}
+
+// Decode updates the frame with the pixel data in the packet, and returns the
+// protocol and timeout of the packet. Use Hyperion as the protocol for packets
+// received on a Hyperion port, or 0 to detect the protocol from the packet
+// header. LEDs outside of the frame are ignored.
+func Decode(frame ledsgo.Strip, protocol Protocol, packet []byte) (Protocol, uint8, error) {
+	var timeout uint8 = TimeoutForever
+	if protocol != Hyperion {
+		if len(packet) < 2 {
+			return 0, 0, errInvalidPacket
+		}
+		protocol = Protocol(packet[0])
+		timeout = packet[1]
+		packet = packet[2:]
+	}
+	switch protocol {
+	case WARLS:
+		for ; len(packet) >= 4; packet = packet[4:] {
+			if i := int(packet[0]); i < len(frame) {
+				frame[i] = color.RGBA{R: packet[1], G: packet[2], B: packet[3]}
+			}
+		}
+	case DRGB, Hyperion:
+		ledsgo.OrderRGB.DecodeStrip(frame, packet)
+	case DRGBW:
+		ledsgo.OrderRGBW.DecodeStrip(frame, packet)
+	case DNRGB:
+		if len(packet) < 2 {
+			return 0, 0, errInvalidPacket
+		}
+		start := int(binary.BigEndian.Uint16(packet))
+		if start < len(frame) {
+			ledsgo.OrderRGB.DecodeStrip(frame[start:], packet[2:])
+		}
+	default:
+		return 0, 0, errUnknown
+	}
+	return protocol, timeout, nil
+}
@@ -0,0 +1,93 @@
+package realtime
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+	"github.com/aykevl/ledsgo/internal/ledstest"
+)
+
+func TestRoundTrip(t *testing.T) {
+	frame := ledsgo.Strip{{R: 1, G: 2, B: 3}, {R: 4, G: 5, B: 6}, {R: 7, G: 8, B: 9}}
+	for _, tc := range []struct {
+		protocol Protocol
+		start    int
+		length   int
+	}{
+		{WARLS, 2, 2 + 4*3},
+		{DRGB, 0, 2 + 3*3},
+		{DRGBW, 0, 2 + 4*3},
+		{DNRGB, 2, 4 + 3*3},
+		{Hyperion, 0, 3 * 3},
+	} {
+		packet := AppendPacket(nil, tc.protocol, 5, tc.start, frame)
+		if len(packet) != tc.length {
+			t.Errorf("protocol %d: expected %d bytes, got %d", tc.protocol, tc.length, len(packet))
+		}
+		decoded := make(ledsgo.Strip, 6)
+		var detect Protocol
+		if tc.protocol == Hyperion {
+			detect = Hyperion
+		}
+		protocol, timeout, err := Decode(decoded, detect, packet)
+		if err != nil {
+			t.Errorf("protocol %d: %v", tc.protocol, err)
+			continue
+		}
+		if protocol != tc.protocol {
+			t.Errorf("protocol %d: decoded as protocol %d", tc.protocol, protocol)
+		}
+		if tc.protocol == Hyperion {
+			if timeout != TimeoutForever {
+				t.Errorf("protocol %d: expected no timeout, got %d", tc.protocol, timeout)
+			}
+		} else if timeout != 5 {
+			t.Errorf("protocol %d: expected a timeout of 5, got %d", tc.protocol, timeout)
+		}
+		for i, c := range decoded {
+			var expected color.RGBA
+			if i >= tc.start && i < tc.start+len(frame) {
+				expected = frame[i-tc.start]
+			}
+			if c != expected {
+				t.Errorf("protocol %d: LED %d: expected %v, got %v", tc.protocol, i, expected, c)
+			}
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	frame := make(ledsgo.Strip, 3)
+	for _, packet := range [][]byte{nil, {1}, {9, 1, 0, 0, 0}, {byte(DNRGB), 1, 0}} {
+		if _, _, err := Decode(frame, 0, packet); err == nil {
+			t.Errorf("expected an error for packet %v", packet)
+		}
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	var frames ledstest.FrameRecorder
+	r := NewReceiver(nil, 2, &frames)
+	if r.Active() {
+		t.Error("expected receiver to be inactive before the first packet")
+	}
+	if err := r.Handle(AppendPacket(nil, DRGB, TimeoutForever, 0, ledsgo.Strip{{R: 255}, {B: 255}})); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Active() {
+		t.Error("expected receiver to be active")
+	}
+	if err := r.Handle(AppendPacket(nil, WARLS, 0, 1, ledsgo.Strip{{G: 255}})); err != nil {
+		t.Fatal(err)
+	}
+	if r.Active() {
+		t.Error("expected receiver to be inactive after a timeout of 0")
+	}
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(frames))
+	}
+	if expected := (ledsgo.Strip{{R: 255}, {G: 255}}); frames[1][0] != expected[0] || frames[1][1] != expected[1] {
+		t.Errorf("expected %v, got %v", expected, frames[1])
+	}
+}
@@ -0,0 +1,98 @@
+package realtime
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Receiver receives realtime packets and shows the resulting frames on a
+// Displayer. Every packet is displayed immediately, like WLED does.
+//
+// While realtime packets are coming in, the Runner (if any) that normally
+// drives the Displayer should be paused. Use Active to find out whether this
+// is the case.
+type Receiver struct {
+	// Conn is the connection on which packets are received.
+	Conn net.PacketConn
+
+	// Protocol is Hyperion to receive raw Hyperion packets, or 0 to receive
+	// the WLED protocols.
+	Protocol Protocol
+
+	// Displayer is where the received frames are sent.
+	Displayer ledsgo.Displayer
+
+	frame    ledsgo.Strip
+	buf      []byte
+	lock     sync.Mutex
+	deadline time.Time // end of realtime mode, zero if forever
+	active   bool
+}
+
+// NewReceiver returns a new receiver for a frame of the given number of LEDs.
+func NewReceiver(conn net.PacketConn, numLEDs int, displayer ledsgo.Displayer) *Receiver {
+	return &Receiver{
+		Conn:      conn,
+		Displayer: displayer,
+		frame:     make(ledsgo.Strip, numLEDs),
+	}
+}
+
+// Listen starts listening for WLED realtime packets on the default port and
+// returns a new receiver.
+func Listen(numLEDs int, displayer ledsgo.Displayer) (*Receiver, error) {
+	conn, err := net.ListenPacket("udp", ":"+strconv.Itoa(Port))
+	if err != nil {
+		return nil, err
+	}
+	return NewReceiver(conn, numLEDs, displayer), nil
+}
+
+// Serve receives and handles packets until reading from the connection fails.
+// Invalid packets are ignored.
+func (r *Receiver) Serve() error {
+	if r.buf == nil {
+		r.buf = make([]byte, 1500)
+	}
+	for {
+		n, _, err := r.Conn.ReadFrom(r.buf)
+		if err != nil {
+			return err
+		}
+		r.Handle(r.buf[:n])
+	}
+}
+
+// Handle processes a single packet. It returns an error if the packet is
+// invalid or if the frame could not be displayed.
+func (r *Receiver) Handle(packet []byte) error {
+	_, timeout, err := Decode(r.frame, r.Protocol, packet)
+	if err != nil {
+		return err
+	}
+	r.lock.Lock()
+	r.active = true
+	if timeout == TimeoutForever {
+		r.deadline = time.Time{}
+	} else {
+		r.deadline = time.Now().Add(time.Duration(timeout) * time.Second)
+	}
+	r.lock.Unlock()
+	return r.Displayer.Display(r.frame)
+}
+
+// Active returns whether the receiver is in realtime mode: a packet was
+// received recently enough that its timeout hasn't expired yet. It is safe to
+// call while Serve is running in a different goroutine.
+func (r *Receiver) Active() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.active && !r.deadline.IsZero() && !time.Now().Before(r.deadline) {
+		r.active = false
+	}
+	return r.active
+}
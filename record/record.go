@@ -0,0 +1,243 @@
+// Package record saves rendered frames to a compact binary stream and plays
+// them back on any Displayer. This can be used for canned shows on devices
+// with little storage or processing power: the effects are rendered in advance
+// and only the (mostly tiny) differences between frames are stored.
+//
+// The stream starts with a header: the magic bytes "LEDR", a version byte (1)
+// and the number of LEDs as an unsigned varint. Every frame then starts with
+// the time since the previous frame in milliseconds (unsigned varint),
+// followed by a list of operations that together cover all LEDs of the frame.
+// Every operation starts with an unsigned varint where the lower 2 bits are
+// the operation and the upper bits are the number of LEDs:
+//
+//	0: keep the LEDs from the previous frame
+//	1: repeat a single RGB color (followed by 3 bytes)
+//	2: literal RGB colors (followed by 3 bytes per LED)
+package record
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"io"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+const (
+	magic   = "LEDR"
+	version = 1
+)
+
+// Frame operations.
+const (
+	opKeep    = 0
+	opRepeat  = 1
+	opLiteral = 2
+)
+
+var errInvalid = errors.New("record: invalid data")
+
+// Recorder writes frames to a stream. It can also be used directly as a
+// Displayer.
+type Recorder struct {
+	w     io.Writer
+	prev  ledsgo.Strip
+	buf   []byte
+	last  time.Duration
+	start time.Time
+}
+
+// NewRecorder writes the stream header to w and returns a recorder for frames
+// of the given number of LEDs.
+func NewRecorder(w io.Writer, numLEDs int) (*Recorder, error) {
+	buf := append([]byte(magic), version)
+	buf = binary.AppendUvarint(buf, uint64(numLEDs))
+	if _, err := w.Write(buf); err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		w:     w,
+		prev:  make(ledsgo.Strip, numLEDs),
+		buf:   buf[:0],
+		start: time.Now(),
+	}, nil
+}
+
+// Record writes a frame that was rendered at time t. The time must not go
+// backwards. Frames with more LEDs than the recorder was created with are
+// truncated.
+func (r *Recorder) Record(frame ledsgo.Strip, t time.Duration) error {
+	delta := t - r.last
+	if delta < 0 {
+		delta = 0
+	}
+	// Only advance by whole milliseconds, so that rounding errors don't add
+	// up over a long recording.
+	ms := delta / time.Millisecond
+	r.last += ms * time.Millisecond
+	buf := binary.AppendUvarint(r.buf[:0], uint64(ms))
+
+	n := len(r.prev)
+	if len(frame) < n {
+		n = len(frame)
+	}
+	i := 0
+	for i < len(r.prev) {
+		// Count the LEDs that stay the same. LEDs beyond the end of the frame
+		// are treated as unchanged.
+		j := i
+		for j < len(r.prev) && (j >= n || sameRGB(frame[j], r.prev[j])) {
+			j++
+		}
+		if j > i {
+			buf = binary.AppendUvarint(buf, uint64(j-i)<<2|opKeep)
+			i = j
+			continue
+		}
+
+		// Count the LEDs with the same color.
+		for j < n && sameRGB(frame[j], frame[i]) {
+			j++
+		}
+		if j-i >= 2 {
+			c := frame[i]
+			buf = binary.AppendUvarint(buf, uint64(j-i)<<2|opRepeat)
+			buf = append(buf, c.R, c.G, c.B)
+			copy(r.prev[i:j], frame[i:j])
+			i = j
+			continue
+		}
+
+		// Literal colors, until an unchanged LED or a repeated color.
+		j = i + 1
+		for j < n && !sameRGB(frame[j], r.prev[j]) && (j+1 >= n || !sameRGB(frame[j], frame[j+1])) {
+			j++
+		}
+		buf = binary.AppendUvarint(buf, uint64(j-i)<<2|opLiteral)
+		buf = ledsgo.OrderRGB.AppendStrip(buf, frame[i:j])
+		copy(r.prev[i:j], frame[i:j])
+		i = j
+	}
+	r.buf = buf
+	_, err := r.w.Write(buf)
+	return err
+}
+
+// Display implements ledsgo.Displayer. It records the frame with the time
+// since the recorder was created.
+func (r *Recorder) Display(frame ledsgo.Strip) error {
+	return r.Record(frame, time.Since(r.start))
+}
+
+// sameRGB returns whether both colors are the same, ignoring the alpha
+// channel.
+func sameRGB(a, b color.RGBA) bool {
+	return a.R == b.R && a.G == b.G && a.B == b.B
+}
+
+// Player reads frames from a stream written by a Recorder.
+type Player struct {
+	r     *bufio.Reader
+	frame ledsgo.Strip
+}
+
+// NewPlayer reads the stream header from r and returns a new player.
+func NewPlayer(r io.Reader) (*Player, error) {
+	br := bufio.NewReader(r)
+	var header [len(magic) + 1]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:len(magic)]) != magic || header[len(magic)] != version {
+		return nil, errInvalid
+	}
+	numLEDs, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	if numLEDs > 1<<20 {
+		return nil, errInvalid // unreasonably large
+	}
+	return &Player{
+		r:     br,
+		frame: make(ledsgo.Strip, numLEDs),
+	}, nil
+}
+
+// Len returns the number of LEDs in every frame.
+func (p *Player) Len() int {
+	return len(p.frame)
+}
+
+// Next reads the next frame and returns it together with the time since the
+// previous frame. The returned frame is reused by the next call to Next. At
+// the end of the stream, it returns io.EOF.
+func (p *Player) Next() (ledsgo.Strip, time.Duration, error) {
+	ms, err := binary.ReadUvarint(p.r)
+	if err != nil {
+		return nil, 0, err
+	}
+	var rgb [3]byte
+	for i := 0; i < len(p.frame); {
+		op, err := binary.ReadUvarint(p.r)
+		if err != nil {
+			return nil, 0, unexpectedEOF(err)
+		}
+		count := op >> 2
+		if count == 0 || count > uint64(len(p.frame)-i) {
+			return nil, 0, errInvalid
+		}
+		leds := p.frame[i : i+int(count)]
+		switch op & 3 {
+		case opKeep:
+		case opRepeat:
+			if _, err := io.ReadFull(p.r, rgb[:]); err != nil {
+				return nil, 0, unexpectedEOF(err)
+			}
+			leds.FillSolid(color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2]})
+		case opLiteral:
+			for j := range leds {
+				if _, err := io.ReadFull(p.r, rgb[:]); err != nil {
+					return nil, 0, unexpectedEOF(err)
+				}
+				leds[j] = color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2]}
+			}
+		default:
+			return nil, 0, errInvalid
+		}
+		i += int(count)
+	}
+	return p.frame, time.Duration(ms) * time.Millisecond, nil
+}
+
+// Play shows all remaining frames on the displayer at the recorded speed. It
+// returns nil at the end of the stream.
+func (p *Player) Play(displayer ledsgo.Displayer) error {
+	next := time.Now()
+	for {
+		frame, delay, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		next = next.Add(delay)
+		time.Sleep(time.Until(next))
+		if err := displayer.Display(frame); err != nil {
+			return err
+		}
+	}
+}
+
+// unexpectedEOF converts io.EOF into io.ErrUnexpectedEOF, for errors in the
+// middle of a frame.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package record
+
+import (
+	"bytes"
+	"image/color"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+func TestRoundTrip(t *testing.T) {
+	const numLEDs = 100
+	var frames []ledsgo.Strip
+	for f := 0; f < 20; f++ {
+		frame := make(ledsgo.Strip, numLEDs)
+		for i := range frame {
+			switch {
+			case i < 30:
+				frame[i] = color.RGBA{R: 10, G: 20, B: 30} // static
+			case i < 60:
+				frame[i] = color.RGBA{R: uint8(f), G: 5} // solid but changing
+			default:
+				frame[i] = color.RGBA{R: uint8(i * f), G: uint8(i), B: uint8(f * 7)} // noisy
+			}
+		}
+		frames = append(frames, frame)
+	}
+
+	var buf bytes.Buffer
+	r, err := NewRecorder(&buf, numLEDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, frame := range frames {
+		if err := r.Record(frame, time.Duration(i)*20*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if raw := len(frames) * numLEDs * 3; buf.Len() >= raw*3/4 {
+		t.Errorf("expected the recording to be compact: %d bytes for %d bytes of raw data", buf.Len(), raw)
+	}
+
+	p, err := NewPlayer(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Len() != numLEDs {
+		t.Errorf("expected %d LEDs, got %d", numLEDs, p.Len())
+	}
+	for i, expected := range frames {
+		frame, delay, err := p.Next()
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		if i > 0 && delay != 20*time.Millisecond {
+			t.Errorf("frame %d: expected a delay of 20ms, got %v", i, delay)
+		}
+		for j, c := range frame {
+			if c != expected[j] {
+				t.Errorf("frame %d, LED %d: expected %v, got %v", i, j, expected[j], c)
+			}
+		}
+	}
+	if _, _, err := p.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestStaticFrames(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := NewRecorder(&buf, 300)
+	frame := make(ledsgo.Strip, 300)
+	frame.FillSolid(color.RGBA{R: 255})
+	r.Record(frame, 0)
+	size := buf.Len()
+	r.Record(frame, 10*time.Millisecond)
+	if n := buf.Len() - size; n != 3 {
+		t.Errorf("expected an unchanged frame to take 3 bytes, got %d", n)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	if _, err := NewPlayer(bytes.NewReader([]byte("LEDX\x01\x03"))); err == nil {
+		t.Error("expected an error for an invalid header")
+	}
+	p, err := NewPlayer(bytes.NewReader([]byte("LEDR\x01\x03\x00\x11")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := p.Next(); err != errInvalid {
+		t.Errorf("expected errInvalid for a too long run, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package ledsgo
+
+import (
+	"sort"
+	"sync"
+)
+
+// EffectInfo describes an effect in the effect registry.
+type EffectInfo struct {
+	// Name is the unique name of the effect, as shown in user interfaces.
+	Name string
+
+	// Description is an optional longer description of the effect.
+	Description string
+
+	// Category is an optional group of similar effects, such as "noise" or
+	// "matrix", so that user interfaces can group long lists of effects.
+	Category string
+
+	// New returns a new instance of the effect. Every instance must be
+	// independent of the others, so that the same effect can run on multiple
+	// strips at once.
+	New func() Effect
+}
+
+// The registry of all effects that have been registered with RegisterEffect.
+var effectRegistry struct {
+	lock    sync.RWMutex
+	effects map[string]EffectInfo
+}
+
+// RegisterEffect adds an effect to the effect registry, so that remote control
+// frontends (such as a web interface) can list and select it by name without
+// a list of effects in the frontend itself. Effects usually register
+// themselves in an init function:
+//
+//	func init() {
+//		ledsgo.RegisterEffect(ledsgo.EffectInfo{
+//			Name: "Lava",
+//			New:  func() ledsgo.Effect { return &lavaEffect{} },
+//		})
+//	}
+//
+// It panics if the name is empty, if New is nil, or if an effect with the same
+// name has already been registered.
+func RegisterEffect(info EffectInfo) {
+	if info.Name == "" || info.New == nil {
+		panic("ledsgo: invalid effect registration")
+	}
+	effectRegistry.lock.Lock()
+	defer effectRegistry.lock.Unlock()
+	if _, ok := effectRegistry.effects[info.Name]; ok {
+		panic("ledsgo: effect registered twice: " + info.Name)
+	}
+	if effectRegistry.effects == nil {
+		effectRegistry.effects = make(map[string]EffectInfo)
+	}
+	effectRegistry.effects[info.Name] = info
+}
+
+// LookupEffect returns the registered effect with the given name, and whether
+// it exists.
+func LookupEffect(name string) (EffectInfo, bool) {
+	effectRegistry.lock.RLock()
+	defer effectRegistry.lock.RUnlock()
+	info, ok := effectRegistry.effects[name]
+	return info, ok
+}
+
+// RegisteredEffects returns all registered effects, sorted by name. This
+// includes the effects of this package that work on any strip, such as Rain
+// and Strobe. Effects that need the size of a matrix aren't registered.
+func RegisteredEffects() []EffectInfo {
+	effectRegistry.lock.RLock()
+	defer effectRegistry.lock.RUnlock()
+	effects := make([]EffectInfo, 0, len(effectRegistry.effects))
+	for _, info := range effectRegistry.effects {
+		effects = append(effects, info)
+	}
+	sort.Slice(effects, func(i, j int) bool {
+		return effects[i].Name < effects[j].Name
+	})
+	return effects
+}
+
+// NewEffect returns a new instance of the registered effect with the given
+// name, or nil if there is no such effect.
+func NewEffect(name string) Effect {
+	info, ok := LookupEffect(name)
+	if !ok {
+		return nil
+	}
+	return info.New()
+}
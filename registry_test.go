@@ -0,0 +1,93 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestEffectRegistry(t *testing.T) {
+	solid := func(c color.RGBA) func() Effect {
+		return func() Effect {
+			return EffectFunc(func(frame Strip, t time.Duration) {
+				frame.FillSolid(c)
+			})
+		}
+	}
+	if _, ok := LookupEffect("test/red"); !ok { // not yet registered by a previous run (-count)
+		RegisterEffect(EffectInfo{Name: "test/red", Category: "test", New: solid(color.RGBA{R: 255})})
+		RegisterEffect(EffectInfo{Name: "test/blue", Description: "All blue", New: solid(color.RGBA{B: 255})})
+	}
+
+	info, ok := LookupEffect("test/blue")
+	if !ok || info.Name != "test/blue" || info.Description != "All blue" {
+		t.Errorf("LookupEffect: got %v, %v", info, ok)
+	}
+	if _, ok := LookupEffect("test/green"); ok {
+		t.Error("LookupEffect: found an effect that was never registered")
+	}
+
+	effect := NewEffect("test/red")
+	if effect == nil {
+		t.Fatal("NewEffect: expected an effect")
+	}
+	frame := make(Strip, 2)
+	effect.Render(frame, 0)
+	if frame[1] != (color.RGBA{R: 255}) {
+		t.Errorf("NewEffect: the wrong effect was created, it rendered %v", frame[1])
+	}
+	if NewEffect("test/green") != nil {
+		t.Error("NewEffect: expected nil for an unknown effect")
+	}
+
+	// The list is sorted by name.
+	var names []string
+	for _, info := range RegisteredEffects() {
+		if info.Name == "test/red" || info.Name == "test/blue" {
+			names = append(names, info.Name)
+		}
+	}
+	if len(names) != 2 || names[0] != "test/blue" || names[1] != "test/red" {
+		t.Errorf("RegisteredEffects: unexpected list %v", names)
+	}
+
+	for _, info := range []EffectInfo{
+		{Name: "test/red", New: solid(color.RGBA{})}, // duplicate
+		{Name: "", New: solid(color.RGBA{})},
+		{Name: "test/nil"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterEffect(%q): expected a panic", info.Name)
+				}
+			}()
+			RegisterEffect(info)
+		}()
+	}
+}
+
+func TestBuiltinEffectRegistry(t *testing.T) {
+	// The built-in effects that don't need a matrix size register
+	// themselves, and are visible without being configured first.
+	for _, name := range []string{"Rain", "Snow", "Sun", "Strobe", "Police"} {
+		info, ok := LookupEffect(name)
+		if !ok || info.Category == "" || info.Description == "" {
+			t.Errorf("%s: expected a registered effect with a category and description, got %+v", name, info)
+			continue
+		}
+		e := info.New()
+		frame := make(Strip, 30)
+		lit := false
+		for ts := time.Duration(0); ts < 5*time.Second && !lit; ts += 20 * time.Millisecond {
+			e.Render(frame, ts)
+			lit = !isBlack(frame)
+		}
+		if !lit {
+			t.Errorf("%s: expected the effect to light up LEDs", name)
+		}
+		if info.New() == e {
+			t.Errorf("%s: expected a new instance every time", name)
+		}
+	}
+}
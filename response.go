@@ -0,0 +1,88 @@
+package ledsgo
+
+// ResponseCurve describes the brightness response of a type of LED, as the
+// value that must be sent to each channel to get the brightness of an ideal
+// LED. LED models can differ at the low end, so that two types of LEDs next
+// to each other look uneven at low values even though they match at full
+// brightness. Measuring a few points of the response of every type of LED in
+// an installation and correcting for it at the output makes them match. No
+// curves for specific models are provided, because the response also varies
+// between batches: only LinearResponse is predefined.
+//
+// The points are the output values for the inputs 0, 16, 32, ..., 240 and 255.
+// Values in between are linearly interpolated.
+type ResponseCurve struct {
+	Red, Green, Blue [17]uint8
+}
+
+// LinearResponse is the response curve of an ideal LED, which doesn't change
+// any values.
+var LinearResponse = ResponseCurve{
+	Red:   linearResponsePoints,
+	Green: linearResponsePoints,
+	Blue:  linearResponsePoints,
+}
+
+var linearResponsePoints = [17]uint8{0, 16, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224, 240, 255}
+
+// responseTable calculates the lookup table for a channel with the given
+// points.
+func responseTable(points *[17]uint8) (t [256]uint8) {
+	for i := range t {
+		p := min(i>>4, 15)
+		length := uint16(16)
+		if p == 15 {
+			length = 15 // the last segment is 240..255
+		}
+		frac := uint16(i - p*16)
+		a, b := uint16(points[p]), uint16(points[p+1])
+		t[i] = uint8((a*(length-frac) + b*frac + length/2) / length)
+	}
+	return t
+}
+
+// ResponseSegment assigns a response curve to a range of LEDs in a frame.
+type ResponseSegment struct {
+	Start int // index of the first LED
+	Count int // number of LEDs
+	Curve ResponseCurve
+}
+
+// LUT returns the lookup table that corrects for this response curve, for
+// use with a LUTCorrector.
+func (c *ResponseCurve) LUT() LUT {
+	return LUT{
+		Red:   responseTable(&c.Red),
+		Green: responseTable(&c.Green),
+		Blue:  responseTable(&c.Blue),
+	}
+}
+
+// ResponseCorrector is a Displayer that corrects every frame for the response
+// curves of the LEDs before it is sent to the underlying displayer, so that
+// installations mixing different types of LEDs look even. LEDs that are not
+// part of any segment are sent unchanged.
+type ResponseCorrector struct {
+	displayer Displayer
+	segments  []LUTSegment
+	buf       Strip
+}
+
+// NewResponseCorrector returns a new ResponseCorrector that sends frames to
+// the given displayer.
+func NewResponseCorrector(displayer Displayer, segments ...ResponseSegment) *ResponseCorrector {
+	c := &ResponseCorrector{displayer: displayer}
+	for _, seg := range segments {
+		c.segments = append(c.segments, LUTSegment{
+			Start: seg.Start,
+			Count: seg.Count,
+			LUT:   seg.Curve.LUT(),
+		})
+	}
+	return c
+}
+
+// Display sends the corrected frame to the underlying displayer.
+func (c *ResponseCorrector) Display(frame Strip) error {
+	return c.displayer.Display(applyLUTs(&c.buf, frame, c.segments))
+}
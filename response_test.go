@@ -0,0 +1,53 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestResponseCurve(t *testing.T) {
+	linear := responseTable(&linearResponsePoints)
+	for i, v := range linear {
+		if int(v) != i {
+			t.Errorf("linear response: expected %d for %d, got %d", i, i, v)
+		}
+	}
+
+	// A LED that is too bright at the low end.
+	points := [17]uint8{0, 10, 24, 40, 56, 72, 90, 108, 126, 144, 160, 176, 192, 208, 224, 240, 255}
+	table := responseTable(&points)
+	for _, tc := range [][2]uint8{{0, 0}, {8, 5}, {16, 10}, {20, 14}, {24, 17}, {128, 126}, {248, 248}, {255, 255}} {
+		if v := table[tc[0]]; v != tc[1] {
+			t.Errorf("response for %d: expected %d, got %d", tc[0], tc[1], v)
+		}
+	}
+}
+
+func TestResponseCorrector(t *testing.T) {
+	dim := ResponseCurve{Red: [17]uint8{}, Green: linearResponsePoints, Blue: linearResponsePoints}
+	for i := range dim.Red {
+		dim.Red[i] = linearResponsePoints[i] / 2
+	}
+	rec := &frameRecorder{}
+	c := NewResponseCorrector(rec,
+		ResponseSegment{Start: 0, Count: 2, Curve: LinearResponse},
+		ResponseSegment{Start: 2, Count: 10, Curve: dim},
+	)
+	frame := make(Strip, 5)
+	frame.FillSolid(color.RGBA{200, 100, 50, 0})
+	if err := c.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	for i, col := range rec.frames[0] {
+		expected := color.RGBA{200, 100, 50, 0}
+		if i >= 2 {
+			expected.R = 100
+		}
+		if col != expected {
+			t.Errorf("LED %d: expected %v, got %v", i, expected, col)
+		}
+	}
+	if frame[4].R != 200 {
+		t.Error("the original frame was modified")
+	}
+}
@@ -0,0 +1,124 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// Ring describes a circle (or part of a circle) of LEDs, such as a LED ring,
+// one circle of a concentric-ring clock layout, or a fan. The LEDs are wired in
+// order of increasing angle.
+//
+// Angles are 16-bit values, where 0x10000 would be a full circle. Using
+// unsigned integers means angles wrap around for free.
+type Ring struct {
+	Offset int    // index of the first LED of this ring in the strip
+	Count  int    // number of LEDs in this ring
+	Start  uint16 // angle of the first LED
+	Arc    uint16 // angle between the first and the last LED of a fan, 0 for a full circle
+}
+
+// Rings describes a layout of concentric rings, ordered from the innermost
+// ring (radius 0) to the outermost ring.
+type Rings []Ring
+
+// position returns the position of the given angle along the ring as a 16.16
+// fixed-point LED index. The second return value is false if the angle falls
+// outside of a fan.
+func (r Ring) position(angle uint16) (uint32, bool) {
+	angle -= r.Start
+	if r.Arc == 0 {
+		return uint32(angle) * uint32(r.Count), true // .16
+	}
+	if angle > r.Arc {
+		return 0, false
+	}
+	return uint32(uint64(angle) * uint64(r.Count-1) << 16 / uint64(r.Arc)), true // .16
+}
+
+// Index returns the strip index of the LED closest to the given angle, or -1
+// if the angle falls outside of a fan.
+func (r Ring) Index(angle uint16) int {
+	if r.Count == 0 {
+		return -1
+	}
+	pos, ok := r.position(angle)
+	if !ok {
+		return -1
+	}
+	i := int((pos + 0x8000) >> 16) // round to the nearest LED
+	if i >= r.Count {
+		i -= r.Count // only possible for full circles: wrap around
+	}
+	return r.Offset + i
+}
+
+// Pixels returns the two LEDs surrounding the given angle together with the
+// fraction of the way from the first to the second LED (0..255). This can be
+// used to spread a color over both LEDs so that it moves smoothly around the
+// ring. It returns -1 for both indices if the angle falls outside of a fan.
+func (r Ring) Pixels(angle uint16) (i0, i1 int, frac uint8) {
+	if r.Count == 0 {
+		return -1, -1, 0
+	}
+	pos, ok := r.position(angle)
+	if !ok {
+		return -1, -1, 0
+	}
+	i0 = int(pos >> 16)
+	i1 = i0 + 1
+	if i1 >= r.Count {
+		if r.Arc != 0 {
+			i1 = i0 // last LED of a fan
+		} else {
+			i1 = 0 // wrap around
+		}
+	}
+	return r.Offset + i0, r.Offset + i1, uint8(pos >> 8)
+}
+
+// Set draws a color at the given angle, spreading it over the two closest LEDs
+// depending on how close the angle is to each. The color is added to the
+// existing colors of these LEDs. LEDs that fall outside of the strip are
+// skipped.
+func (r Ring) Set(s Strip, angle uint16, c color.RGBA) {
+	i0, i1, frac := r.Pixels(angle)
+	if i1 == i0 {
+		if i0 >= 0 && i0 < len(s) {
+			s[i0] = addRGBA(s[i0], c)
+		}
+		return
+	}
+	if i0 >= 0 && i0 < len(s) {
+		s[i0] = addWeighted(s[i0], c, 256-uint16(frac))
+	}
+	if i1 >= 0 && i1 < len(s) {
+		s[i1] = addWeighted(s[i1], c, uint16(frac))
+	}
+}
+
+// Index returns the strip index of the LED closest to the given angle on the
+// ring with the given radius, or -1 if there is no such LED.
+func (rs Rings) Index(angle uint16, radius int) int {
+	if radius < 0 || radius >= len(rs) {
+		return -1
+	}
+	return rs[radius].Index(angle)
+}
+
+// Set draws a color at the given angle on the ring with the given radius. See
+// Ring.Set for details.
+func (rs Rings) Set(s Strip, angle uint16, radius int, c color.RGBA) {
+	if radius < 0 || radius >= len(rs) {
+		return
+	}
+	rs[radius].Set(s, angle, c)
+}
+
+// Len returns the total number of LEDs in all rings.
+func (rs Rings) Len() int {
+	n := 0
+	for _, r := range rs {
+		n += r.Count
+	}
+	return n
+}
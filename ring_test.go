@@ -0,0 +1,53 @@
+package ledsgo
+
+import (
+	"testing"
+)
+
+func TestRingIndex(t *testing.T) {
+	ring := Ring{Offset: 10, Count: 4}
+	for _, tc := range []struct {
+		angle uint16
+		index int
+	}{
+		{0x0000, 10},
+		{0x1fff, 10},
+		{0x2000, 11},
+		{0x4000, 11},
+		{0xc000, 13},
+		{0xf000, 10}, // wraps around to the first LED
+	} {
+		if index := ring.Index(tc.angle); index != tc.index {
+			t.Errorf("Index(%#04x): expected %d, got %d", tc.angle, tc.index, index)
+		}
+	}
+
+	fan := Ring{Count: 5, Start: 0x8000, Arc: 0x4000}
+	for _, tc := range []struct {
+		angle uint16
+		index int
+	}{
+		{0x7fff, -1},
+		{0x8000, 0},
+		{0xa000, 2},
+		{0xc000, 4},
+		{0xc001, -1},
+	} {
+		if index := fan.Index(tc.angle); index != tc.index {
+			t.Errorf("fan Index(%#04x): expected %d, got %d", tc.angle, tc.index, index)
+		}
+	}
+}
+
+func TestRingPixels(t *testing.T) {
+	ring := Ring{Count: 4}
+	i0, i1, frac := ring.Pixels(0xe000)
+	if i0 != 3 || i1 != 0 || frac != 0x80 {
+		t.Errorf("Pixels(0xe000): expected 3, 0, 0x80, got %d, %d, %#02x", i0, i1, frac)
+	}
+	fan := Ring{Count: 3, Arc: 0x8000}
+	i0, i1, frac = fan.Pixels(0x8000)
+	if i0 != 2 || i1 != 2 || frac != 0 {
+		t.Errorf("fan Pixels(0x8000): expected 2, 2, 0, got %d, %d, %#02x", i0, i1, frac)
+	}
+}
@@ -0,0 +1,170 @@
+package ledsgo
+
+import (
+	"image/color"
+	"sync"
+)
+
+// Runner is the effect engine: it renders the current effect, applies the
+// global brightness and sends the result to a Displayer. All methods are safe
+// for concurrent use, so that for example a web server can change the effect
+// while the main loop keeps rendering frames.
+//
+// Rendering a frame doesn't allocate memory (as long as the effect and the
+// Displayer don't), so there are no garbage collection pauses while running.
+type Runner struct {
+	lock       sync.Mutex
+	displayer  Displayer
+	clock      *Clock
+	frame      Strip // the frame as rendered by the effect
+	output     Strip // the frame after applying the brightness
+	effect     Effect
+	params     StandardParams
+	paramsSet  bool // whether SetStandardParams has been called
+	brightness uint8
+	on         bool
+}
+
+// NewRunner returns a new runner for the given number of LEDs. It starts at
+// full brightness, switched on, without an effect.
+func NewRunner(displayer Displayer, numLEDs int) *Runner {
+	return &Runner{
+		displayer:  displayer,
+		clock:      NewClock(nil),
+		frame:      make(Strip, numLEDs),
+		output:     make(Strip, numLEDs),
+		params:     DefaultStandardParams(),
+		brightness: 255,
+		on:         true,
+	}
+}
+
+// Len returns the number of LEDs.
+func (r *Runner) Len() int {
+	return len(r.frame)
+}
+
+// Clock returns the animation clock used for rendering. The clock itself is
+// not safe for concurrent use and must only be used from the goroutine that
+// calls Frame.
+func (r *Runner) Clock() *Clock {
+	return r.clock
+}
+
+// Effect returns the current effect, or nil if there is none.
+func (r *Runner) Effect() Effect {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.effect
+}
+
+// SetEffect changes the current effect. The frame is cleared, so that the new
+// effect starts from a black frame. If the effect is a StandardEffect, it
+// gets the current standard parameters once they have been set with
+// SetStandardParams. Until then, the effect keeps its own settings.
+func (r *Runner) SetEffect(effect Effect) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.effect = effect
+	r.frame.FillSolid(color.RGBA{})
+	r.applyParams()
+}
+
+// StandardParams returns the standard parameters.
+func (r *Runner) StandardParams() StandardParams {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.params
+}
+
+// SetStandardParams changes the standard parameters of the current effect and
+// of all effects that are started afterwards. With a nil palette, every
+// effect uses its own default colors.
+func (r *Runner) SetStandardParams(params StandardParams) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.params = params
+	r.paramsSet = true
+	r.applyParams()
+}
+
+// applyParams passes the standard parameters to the current effect, if it uses
+// them. The lock must be held.
+func (r *Runner) applyParams() {
+	if !r.paramsSet {
+		return
+	}
+	if e, ok := r.effect.(StandardEffect); ok {
+		e.SetStandardParams(r.params)
+	}
+}
+
+// Params returns the parameters of the current effect, or nil if it has none
+// (see ParamEffect). Use them for the names and ranges of the parameters, but
+// use Param and SetParam to read and change them: Param.Get and Param.Set are
+// not synchronized with rendering.
+func (r *Runner) Params() []Param {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return EffectParams(r.effect)
+}
+
+// Param returns the value of the parameter of the current effect with the
+// given name. It returns false if there is no such parameter.
+func (r *Runner) Param(name string) (int32, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return getParam(r.effect, name)
+}
+
+// SetParam changes the parameter of the current effect with the given name,
+// limited to the range of the parameter. It returns false if there is no such
+// parameter.
+func (r *Runner) SetParam(name string, value int32) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return setParam(r.effect, name, value)
+}
+
+// Brightness returns the global brightness.
+func (r *Runner) Brightness() uint8 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.brightness
+}
+
+// SetBrightness changes the global brightness, where 255 is full brightness.
+func (r *Runner) SetBrightness(brightness uint8) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.brightness = brightness
+}
+
+// On returns whether the LEDs are switched on.
+func (r *Runner) On() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.on
+}
+
+// SetOn switches the LEDs on or off. While switched off, black frames are sent
+// to the Displayer.
+func (r *Runner) SetOn(on bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.on = on
+}
+
+// Frame renders a single frame and sends it to the Displayer. It should be
+// called in a loop, for example paced by a FrameLimiter.
+func (r *Runner) Frame() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.on || r.effect == nil {
+		r.output.FillSolid(color.RGBA{})
+	} else {
+		r.effect.Render(r.frame, r.clock.Now())
+		scaleStrip(r.output, r.frame, r.brightness)
+	}
+	return r.displayer.Display(r.output)
+}
@@ -0,0 +1,238 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+// speedEffect is a StandardEffect that renders its parameters. Its default
+// palette is RainbowColors.
+type speedEffect struct {
+	params StandardParams
+}
+
+func (e *speedEffect) Render(frame Strip, t time.Duration) {
+	palette := e.params.Palette
+	if palette == nil {
+		palette = &RainbowColors
+	}
+	frame.FillSolid(color.RGBA{R: e.params.Speed, G: e.params.Intensity, B: palette[0].B})
+}
+
+func (e *speedEffect) SetStandardParams(params StandardParams) {
+	e.params = params
+}
+
+func TestRunner(t *testing.T) {
+	rec := &frameRecorder{}
+	r := NewRunner(rec, 4)
+	if r.Len() != 4 || r.Brightness() != 255 || !r.On() || r.Effect() != nil {
+		t.Errorf("unexpected initial state: %d LEDs, brightness %d, on %v", r.Len(), r.Brightness(), r.On())
+	}
+
+	// Without an effect, the frame is black.
+	if err := r.Frame(); err != nil {
+		t.Fatal(err)
+	}
+	if !isBlack(rec.frames[0]) {
+		t.Errorf("expected a black frame without an effect, got %v", rec.frames[0])
+	}
+
+	// The effect gets the animation time and its output is scaled by the
+	// brightness.
+	var times []time.Duration
+	effect := EffectFunc(func(frame Strip, t time.Duration) {
+		times = append(times, t)
+		frame[0] = color.RGBA{200, 100, 50, 0}
+		frame[1] = addRGBA(frame[1], color.RGBA{G: 1}) // keeps state between frames
+	})
+	r.SetEffect(effect)
+	r.SetBrightness(128)
+	r.Frame()
+	r.Frame()
+	if c := rec.frames[2][0]; c != (color.RGBA{100, 50, 25, 0}) {
+		t.Errorf("expected the color at half brightness, got %v", c)
+	}
+	if len(times) != 2 || times[1] < times[0] {
+		t.Errorf("unexpected animation times %v", times)
+	}
+
+	// Switching off sends black frames, but keeps the effect.
+	r.SetOn(false)
+	r.Frame()
+	if !isBlack(rec.frames[3]) || r.Effect() == nil {
+		t.Errorf("expected a black frame while switched off, got %v", rec.frames[3])
+	}
+
+	// A new effect starts from a black frame.
+	r.SetOn(true)
+	r.SetBrightness(255)
+	r.SetEffect(effect)
+	r.Frame()
+	if c := rec.frames[4][1]; c != (color.RGBA{G: 1}) {
+		t.Errorf("expected the frame to be cleared when changing effects, got %v", c)
+	}
+
+	// Rendering doesn't allocate.
+	r = NewRunner(discardDisplayer{}, 4)
+	r.SetEffect(EffectFunc(func(frame Strip, t time.Duration) { frame.FillSolid(color.RGBA{R: 1}) }))
+	if allocs := testing.AllocsPerRun(10, func() { r.Frame() }); allocs != 0 {
+		t.Errorf("expected no allocations, got %.0f", allocs)
+	}
+}
+
+func TestRunnerStandardParams(t *testing.T) {
+	rec := &frameRecorder{}
+	r := NewRunner(rec, 3)
+	if p := r.StandardParams(); p != DefaultStandardParams() {
+		t.Errorf("expected the default parameters, got %+v", p)
+	}
+
+	// The parameters are applied to the current effect, and kept when
+	// switching effects.
+	blue := NewPalette16(color.RGBA{B: 200})
+	r.SetEffect(&speedEffect{})
+	r.SetStandardParams(StandardParams{Speed: 10, Intensity: 20, Palette: &blue})
+	r.SetEffect(&speedEffect{})
+	if err := r.Frame(); err != nil {
+		t.Fatal(err)
+	}
+	if c := rec.frames[0][2]; c != (color.RGBA{10, 20, 200, 0}) {
+		t.Errorf("expected the parameters to be kept, got %v", c)
+	}
+
+	r.SetStandardParams(StandardParams{Speed: 30})
+	if err := r.Frame(); err != nil {
+		t.Fatal(err)
+	}
+	if c := rec.frames[1][0]; c != (color.RGBA{30, 0, RainbowColors[0].B, 0}) {
+		t.Errorf("expected the new parameters with the default palette, got %v", c)
+	}
+
+	// Effects without standard parameters still work.
+	r.SetEffect(EffectFunc(func(frame Strip, t time.Duration) {}))
+	if err := r.Frame(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunnerConfiguredEffect(t *testing.T) {
+	// Effects keep the settings they were configured with until the standard
+	// parameters are set.
+	r := NewRunner(&frameRecorder{}, 16*16)
+	strobe := &Strobe{Interval: 500 * time.Millisecond}
+	r.SetEffect(strobe)
+	ocean := OceanColors
+	fire := NewNoiseFire(16, 16)
+	fire.Intensity = 30
+	fire.Palette = &ocean
+	r.SetEffect(fire)
+	if strobe.Interval != 500*time.Millisecond || fire.Intensity != 30 || fire.Palette != &ocean {
+		t.Errorf("expected the configured settings to be kept, got interval %v, intensity %d and palette %p", strobe.Interval, fire.Intensity, fire.Palette)
+	}
+
+	segments := NewSegmentRunner(Segment{0, 4})
+	defer segments.Close()
+	rain := &Rain{Intensity: 40}
+	segments.SetEffect(0, rain)
+	if rain.Intensity != 40 {
+		t.Errorf("SegmentRunner: expected the configured intensity to be kept, got %d", rain.Intensity)
+	}
+
+	// Once set, the standard parameters override them.
+	r.SetStandardParams(StandardParams{Speed: 128, Intensity: 200})
+	if fire.Intensity != 200 || fire.Palette != nil {
+		t.Errorf("expected the standard parameters, got intensity %d and palette %p", fire.Intensity, fire.Palette)
+	}
+}
+
+func TestRunnerBuiltinEffects(t *testing.T) {
+	// Built-in effects with an intensity or palette take them from the
+	// runner, also when switching effects.
+	rec := &frameRecorder{}
+	r := NewRunner(rec, 16*16)
+
+	// Without a palette, effects keep their own default colors.
+	fire := NewNoiseFire(16, 16)
+	r.SetEffect(fire)
+	bars := NewSpectrumBars(nil, 16, 16)
+	bars.SetStandardParams(r.StandardParams())
+	if fire.Palette != nil || bars.Palette != nil {
+		t.Errorf("expected the default palettes, got %p and %p", fire.Palette, bars.Palette)
+	}
+	if err := r.Frame(); err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range rec.frames[0] {
+		if c.B > c.R {
+			t.Fatalf("NoiseFire: expected the heat colors, got %v at LED %d", c, i)
+		}
+	}
+	rec.frames = rec.frames[:0]
+
+	ocean := OceanColors
+	r.SetStandardParams(StandardParams{Speed: 128, Intensity: 0, Palette: &ocean})
+	fire = NewNoiseFire(16, 16)
+	r.SetEffect(fire)
+	if fire.Intensity != 0 || fire.Palette != &ocean {
+		t.Errorf("NoiseFire: expected the standard parameters, got intensity %d and palette %p", fire.Intensity, fire.Palette)
+	}
+	if err := r.Frame(); err != nil {
+		t.Fatal(err)
+	}
+	frame := rec.frames[0]
+	for _, c := range frame[:16*8] {
+		if c != frame[0] {
+			t.Fatal("NoiseFire: expected low flames at intensity 0")
+		}
+	}
+	if c := frame[len(frame)-1]; c.B < c.R {
+		t.Errorf("NoiseFire: expected the ocean palette at the bottom, got %v", c)
+	}
+
+	r.SetStandardParams(StandardParams{Speed: 0, Intensity: 200, Palette: &ocean})
+	if fire.Intensity != 200 {
+		t.Errorf("NoiseFire: expected the new intensity, got %d", fire.Intensity)
+	}
+	rain := &Rain{}
+	r.SetEffect(rain)
+	strobe := &Strobe{}
+	r.SetEffect(strobe)
+	if rain.Intensity != 200 || strobe.Interval != 2*time.Second {
+		t.Errorf("expected the parameters to be kept when switching effects, got intensity %d and interval %v", rain.Intensity, strobe.Interval)
+	}
+}
+
+func TestRunnerParams(t *testing.T) {
+	r := NewRunner(discardDisplayer{}, 8*8)
+	if r.Params() != nil || r.SetParam("Wind", 10) {
+		t.Error("expected no parameters without an effect")
+	}
+	fire := NewNoiseFire(8, 8)
+	r.SetEffect(fire)
+	if params := r.Params(); len(params) != 2 || params[1].Name != "Wind" {
+		t.Errorf("unexpected parameters: %+v", params)
+	}
+
+	// Parameters can be changed while another goroutine renders frames.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			r.Frame()
+		}
+		close(done)
+	}()
+	for i := int32(0); i < 100; i++ {
+		if !r.SetParam("Wind", i-300) {
+			t.Fatal("Wind parameter not found")
+		}
+	}
+	<-done
+	if value, ok := r.Param("Wind"); !ok || value != -128 {
+		t.Errorf("expected the wind to be limited to -128, got %d", value)
+	}
+	if _, ok := r.Param("Sparks"); ok {
+		t.Error("expected no Sparks parameter")
+	}
+}
@@ -0,0 +1,236 @@
+package sacn
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Time after which a source is considered lost, as defined in the standard.
+const sourceTimeout = 2500 * time.Millisecond
+
+// Time after the last synchronization packet after which a receiver goes back
+// to displaying frames as soon as they are complete, as defined in the
+// standard.
+const syncTimeout = 2500 * time.Millisecond
+
+// Receiver receives sACN data packets for a range of universes and shows the
+// resulting frames on a Displayer.
+//
+// For every universe, only the data of the source with the highest priority is
+// used. Lower priority sources take over when the higher priority source stops
+// sending. Packets that arrive out of order are dropped. Merging of sources
+// with the same priority is not supported: the last packet wins.
+//
+// A frame is displayed when all universes of the frame have been received.
+// When the source uses synchronization, frames are displayed on every
+// synchronization packet instead, until no synchronization packet has been
+// received for a while.
+type Receiver struct {
+	// Universe is the first universe.
+	Universe uint16
+
+	// Order is the channel order used by the sender.
+	Order ledsgo.ColorOrder
+
+	// Displayer is where the received frames are sent.
+	Displayer ledsgo.Displayer
+
+	lock        sync.Mutex
+	frame       ledsgo.Strip
+	universes   []universeState
+	syncAddress uint16    // sync universe of the last data packet, 0 if unsynchronized
+	lastSync    time.Time // last synchronization packet (or start of waiting for one)
+	syncJoin    chan uint16
+}
+
+// Per-universe receive state.
+type universeState struct {
+	cid      [16]byte
+	priority uint8
+	sequence uint8
+	lastSeen time.Time
+	received bool // received since the last displayed frame
+}
+
+// NewReceiver returns a new receiver for a frame of the given number of LEDs.
+func NewReceiver(universe uint16, numLEDs int, displayer ledsgo.Displayer) *Receiver {
+	r := &Receiver{
+		Universe:  universe,
+		Displayer: displayer,
+		frame:     make(ledsgo.Strip, numLEDs),
+	}
+	return r
+}
+
+// NumUniverses returns the number of universes needed for the frame.
+func (r *Receiver) NumUniverses() int {
+	perUniverse := MaxChannels / r.Order.Channels()
+	return (len(r.frame) + perUniverse - 1) / perUniverse
+}
+
+// ListenAndServe joins the multicast groups of all universes of the frame on
+// the given network interface (nil for the system default) and handles
+// incoming packets until an error occurs. The multicast group of the
+// synchronization universe is joined as soon as a source announces it.
+func (r *Receiver) ListenAndServe(ifi *net.Interface) error {
+	n := r.NumUniverses()
+	errs := make(chan error, 1)
+	joined := make(map[uint16]bool)
+	var conns []*net.UDPConn
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	join := func(universe uint16) error {
+		if joined[universe] {
+			return nil
+		}
+		conn, err := net.ListenMulticastUDP("udp4", ifi, MulticastAddr(universe))
+		if err != nil {
+			return err
+		}
+		joined[universe] = true
+		conns = append(conns, conn)
+		go func() {
+			err := r.Serve(conn)
+			select {
+			case errs <- err:
+			default: // another connection already failed
+			}
+		}()
+		return nil
+	}
+
+	syncJoin := make(chan uint16, 1)
+	r.lock.Lock()
+	r.syncJoin = syncJoin
+	if r.syncAddress != 0 {
+		syncJoin <- r.syncAddress
+	}
+	r.lock.Unlock()
+	defer func() {
+		r.lock.Lock()
+		r.syncJoin = nil
+		r.lock.Unlock()
+	}()
+
+	for i := 0; i < n; i++ {
+		if err := join(r.Universe + uint16(i)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case err := <-errs:
+			return err
+		case universe := <-syncJoin:
+			if err := join(universe); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Serve receives and handles packets from the connection until reading from
+// it fails. Invalid packets are ignored. It is safe to call Serve for multiple
+// connections at the same time.
+func (r *Receiver) Serve(conn net.PacketConn) error {
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		r.Handle(buf[:n])
+	}
+}
+
+// Handle processes a single packet. It returns an error if the packet is
+// invalid or if the frame could not be displayed.
+func (r *Receiver) Handle(packet []byte) error {
+	p, err := Parse(packet)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	n := r.NumUniverses()
+	if len(r.universes) != n {
+		r.universes = make([]universeState, n)
+	}
+
+	if p.Sync {
+		if r.syncAddress == 0 || p.SyncAddress != r.syncAddress {
+			return nil // not the synchronization universe of our data
+		}
+		r.lastSync = time.Now()
+		return r.display()
+	}
+
+	index := int(p.Universe) - int(r.Universe)
+	if index < 0 || index >= n || p.Data == nil || p.Options&OptionPreview != 0 {
+		return nil // not for us
+	}
+	state := &r.universes[index]
+	now := time.Now()
+	if p.CID != state.cid {
+		// Different source: only switch if it has a higher priority or the
+		// current source is gone.
+		if !state.lastSeen.IsZero() && p.Priority < state.priority && now.Sub(state.lastSeen) < sourceTimeout {
+			return nil
+		}
+	} else if !state.lastSeen.IsZero() {
+		// Drop packets that arrive out of order, as defined in the standard.
+		if diff := int8(p.Sequence - state.sequence); diff <= 0 && diff > -20 {
+			return nil
+		}
+	}
+	if p.Options&OptionTerminated != 0 {
+		*state = universeState{} // let other sources take over immediately
+		return nil
+	}
+	state.cid = p.CID
+	state.priority = p.Priority
+	state.sequence = p.Sequence
+	state.lastSeen = now
+	state.received = true
+
+	perUniverse := MaxChannels / r.Order.Channels()
+	r.Order.DecodeStrip(r.frame[index*perUniverse:], p.Data)
+
+	if p.SyncAddress != r.syncAddress {
+		r.syncAddress = p.SyncAddress
+		r.lastSync = now // give the synchronization packets time to arrive
+		if r.syncAddress != 0 && r.syncJoin != nil {
+			// Replace a pending address that hasn't been joined yet.
+			select {
+			case <-r.syncJoin:
+			default:
+			}
+			r.syncJoin <- r.syncAddress
+		}
+	}
+	if r.syncAddress != 0 && now.Sub(r.lastSync) < syncTimeout {
+		return nil // wait for the synchronization packet
+	}
+	for _, u := range r.universes {
+		if !u.received {
+			return nil // wait for the rest of the frame
+		}
+	}
+	return r.display()
+}
+
+// display sends the current frame to the displayer.
+func (r *Receiver) display() error {
+	for i := range r.universes {
+		r.universes[i].received = false
+	}
+	return r.Displayer.Display(r.frame)
+}
@@ -0,0 +1,194 @@
+// Package sacn implements Streaming ACN (ANSI E1.31), a protocol to send DMX
+// data over UDP that is commonly used in venue installations. Both data and
+// synchronization packets are supported, as well as priorities and sequence
+// numbers. Universe discovery is not implemented.
+package sacn
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+// Port is the UDP port used by sACN.
+const Port = 5568
+
+// MaxChannels is the maximum number of DMX channels in a single universe.
+const MaxChannels = 512
+
+// DefaultPriority is the priority used when none is set.
+const DefaultPriority = 100
+
+// Option bits in a data packet.
+const (
+	OptionPreview    = 1 << 7 // data is meant for visualizers only
+	OptionTerminated = 1 << 6 // the source stops sending this universe
+	OptionForceSync  = 1 << 5
+)
+
+// Vectors used in the protocol.
+const (
+	vectorRootData       = 0x00000004
+	vectorRootExtended   = 0x00000008
+	vectorFramingData    = 0x00000002
+	vectorFramingSync    = 0x00000001
+	vectorDMPSetProperty = 0x02
+)
+
+// Fixed values and offsets in the packet layout.
+const (
+	dataHeaderSize        = 126 // data packet up to and including the start code
+	syncPacketSize        = 49
+	sourceNameSize        = 64
+	addressAndDataType    = 0xa1
+	flagsMask             = 0x7000
+	firstPropertyAddress  = 0
+	addressIncrement      = 1
+	defaultDMXStartCode   = 0
+	rootLayerLengthOffset = 16
+	framingLayerOffset    = 38
+	dmpLayerOffset        = 115
+)
+
+var packetIdentifier = [12]byte{'A', 'S', 'C', '-', 'E', '1', '.', '1', '7', 0, 0, 0}
+
+var (
+	errInvalidPacket = errors.New("sacn: invalid packet")
+	errUnknownVector = errors.New("sacn: unknown vector")
+)
+
+// MulticastAddr returns the multicast address for the given universe.
+func MulticastAddr(universe uint16) *net.UDPAddr {
+	return &net.UDPAddr{
+		IP:   net.IPv4(239, 255, byte(universe>>8), byte(universe)),
+		Port: Port,
+	}
+}
+
+// Packet is a decoded data or synchronization packet.
+type Packet struct {
+	CID         [16]byte // identifies the source
+	SourceName  string   // only for data packets
+	Priority    uint8    // only for data packets
+	SyncAddress uint16   // universe used for synchronization, 0 if unsynchronized
+	Sequence    uint8
+	Options     uint8  // only for data packets
+	Universe    uint16 // only for data packets
+	Sync        bool   // whether this is a synchronization packet
+	Data        []byte // DMX channels (without start code), refers to the original buffer
+}
+
+// appendRootLayer appends the root layer with the given vector. The length
+// fields are filled in by setLength.
+func appendRootLayer(buf []byte, vector uint32, cid [16]byte) []byte {
+	buf = append(buf, 0x00, 0x10, 0x00, 0x00) // preamble and postamble size
+	buf = append(buf, packetIdentifier[:]...)
+	buf = append(buf, 0, 0) // flags and length
+	buf = binary.BigEndian.AppendUint32(buf, vector)
+	return append(buf, cid[:]...)
+}
+
+// setLength fills in the flags and length field of the layer that starts at the
+// given offset in the packet. All layers extend to the end of the packet.
+func setLength(packet []byte, offset int) {
+	binary.BigEndian.PutUint16(packet[offset:], flagsMask|uint16(len(packet)-offset))
+}
+
+// AppendData appends a data packet to buf and returns the resulting slice. The
+// data is the DMX channel data (without start code) and must not be longer
+// than 512 bytes.
+func AppendData(buf []byte, cid [16]byte, sourceName string, priority uint8, syncAddress uint16, sequence, options uint8, universe uint16, data []byte) []byte {
+	start := len(buf)
+	buf = appendRootLayer(buf, vectorRootData, cid)
+
+	// Framing layer.
+	buf = append(buf, 0, 0) // flags and length
+	buf = binary.BigEndian.AppendUint32(buf, vectorFramingData)
+	var name [sourceNameSize]byte
+	copy(name[:sourceNameSize-1], sourceName) // keep a terminating zero
+	buf = append(buf, name[:]...)
+	buf = append(buf, priority)
+	buf = binary.BigEndian.AppendUint16(buf, syncAddress)
+	buf = append(buf, sequence, options)
+	buf = binary.BigEndian.AppendUint16(buf, universe)
+
+	// DMP layer.
+	buf = append(buf, 0, 0) // flags and length
+	buf = append(buf, vectorDMPSetProperty, addressAndDataType)
+	buf = binary.BigEndian.AppendUint16(buf, firstPropertyAddress)
+	buf = binary.BigEndian.AppendUint16(buf, addressIncrement)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(data)+1))
+	buf = append(buf, defaultDMXStartCode)
+	buf = append(buf, data...)
+
+	packet := buf[start:]
+	setLength(packet, rootLayerLengthOffset)
+	setLength(packet, framingLayerOffset)
+	setLength(packet, dmpLayerOffset)
+	return buf
+}
+
+// AppendSync appends a synchronization packet to buf and returns the
+// resulting slice.
+func AppendSync(buf []byte, cid [16]byte, sequence uint8, syncAddress uint16) []byte {
+	start := len(buf)
+	buf = appendRootLayer(buf, vectorRootExtended, cid)
+	buf = append(buf, 0, 0) // flags and length
+	buf = binary.BigEndian.AppendUint32(buf, vectorFramingSync)
+	buf = append(buf, sequence)
+	buf = binary.BigEndian.AppendUint16(buf, syncAddress)
+	buf = append(buf, 0, 0) // reserved
+
+	packet := buf[start:]
+	setLength(packet, rootLayerLengthOffset)
+	setLength(packet, framingLayerOffset)
+	return buf
+}
+
+// Parse decodes a data or synchronization packet.
+func Parse(buf []byte) (Packet, error) {
+	if len(buf) < syncPacketSize || [12]byte(buf[4:16]) != packetIdentifier {
+		return Packet{}, errInvalidPacket
+	}
+	var p Packet
+	copy(p.CID[:], buf[22:38])
+	switch binary.BigEndian.Uint32(buf[18:]) {
+	case vectorRootData:
+		if len(buf) < dataHeaderSize || binary.BigEndian.Uint32(buf[40:]) != vectorFramingData {
+			return Packet{}, errInvalidPacket
+		}
+		if buf[117] != vectorDMPSetProperty || buf[118] != addressAndDataType {
+			return Packet{}, errInvalidPacket
+		}
+		count := int(binary.BigEndian.Uint16(buf[123:]))
+		if count == 0 || count > MaxChannels+1 || len(buf) < dataHeaderSize-1+count {
+			return Packet{}, errInvalidPacket
+		}
+		name := buf[44 : 44+sourceNameSize]
+		for i, c := range name {
+			if c == 0 {
+				name = name[:i]
+				break
+			}
+		}
+		p.SourceName = string(name)
+		p.Priority = buf[108]
+		p.SyncAddress = binary.BigEndian.Uint16(buf[109:])
+		p.Sequence = buf[111]
+		p.Options = buf[112]
+		p.Universe = binary.BigEndian.Uint16(buf[113:])
+		if buf[125] == defaultDMXStartCode {
+			p.Data = buf[dataHeaderSize : dataHeaderSize-1+count]
+		}
+	case vectorRootExtended:
+		if binary.BigEndian.Uint32(buf[40:]) != vectorFramingSync {
+			return Packet{}, errUnknownVector
+		}
+		p.Sync = true
+		p.Sequence = buf[44]
+		p.SyncAddress = binary.BigEndian.Uint16(buf[45:])
+	default:
+		return Packet{}, errUnknownVector
+	}
+	return p, nil
+}
@@ -0,0 +1,204 @@
+package sacn
+
+import (
+	"image/color"
+	"net"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+	"github.com/aykevl/ledsgo/internal/ledstest"
+)
+
+// packetConn records every written packet.
+type packetConn struct {
+	net.PacketConn
+	packets [][]byte
+	addrs   []string
+}
+
+func (c *packetConn) WriteTo(buf []byte, addr net.Addr) (int, error) {
+	c.packets = append(c.packets, append([]byte(nil), buf...))
+	c.addrs = append(c.addrs, addr.String()) // addr is reused by the sender
+	return len(buf), nil
+}
+
+func TestSendReceive(t *testing.T) {
+	conn := &packetConn{}
+	cid := [16]byte{1, 2, 3}
+	s := &Sender{Conn: conn, Universe: 7, CID: cid, SourceName: "test", SyncAddress: 7}
+	frame := make(ledsgo.Strip, 200)
+	for i := range frame {
+		frame[i] = color.RGBA{R: uint8(i), G: 1, B: 2}
+	}
+	if err := s.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.packets) != 3 {
+		t.Fatalf("expected 3 packets, got %d", len(conn.packets))
+	}
+	if addr := conn.addrs[1]; addr != "239.255.0.8:5568" {
+		t.Errorf("unexpected address for the second universe: %s", addr)
+	}
+	p, err := Parse(conn.packets[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.CID != cid || p.SourceName != "test" || p.Priority != DefaultPriority || p.Universe != 7 || len(p.Data) != 510 {
+		t.Errorf("unexpected data packet: %+v", p)
+	}
+
+	var frames ledstest.FrameRecorder
+	r := NewReceiver(7, len(frame), &frames)
+	for _, packet := range conn.packets[:2] {
+		if err := r.Handle(packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(frames) != 0 {
+		t.Fatal("frame displayed before the synchronization packet")
+	}
+	if err := r.Handle(conn.packets[2]); err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame, got %d", len(frames))
+	}
+	for i, c := range frames[0] {
+		if c != frame[i] {
+			t.Errorf("LED %d: expected %v, got %v", i, frame[i], c)
+		}
+	}
+
+	// A lower priority source must be ignored.
+	low := AppendData(nil, [16]byte{9}, "low", 50, 0, 0, 0, 7, make([]byte, 510))
+	r.Handle(low)
+	if r.frame[0] != frame[0] {
+		t.Error("lower priority source overwrote the frame")
+	}
+
+	// Old packets from the same source must be dropped.
+	old := AppendData(nil, cid, "test", DefaultPriority, 0, 0, 0, 7, make([]byte, 510))
+	r.Handle(old)
+	if r.frame[0] != frame[0] {
+		t.Error("out of order packet overwrote the frame")
+	}
+}
+
+func TestSendReceiveManyUniverses(t *testing.T) {
+	// Every universe has its own sequence numbers, so that receivers don't
+	// drop packets however many universes there are.
+	const universes = 240
+	conn := &packetConn{}
+	s := &Sender{Conn: conn, Universe: 1, CID: [16]byte{1}}
+	frame := make(ledsgo.Strip, universes*170)
+	var frames ledstest.FrameRecorder
+	r := NewReceiver(1, len(frame), &frames)
+	for n := 0; n < 3; n++ {
+		frame.FillSolid(color.RGBA{R: uint8(n + 1)})
+		conn.packets = conn.packets[:0]
+		if err := s.Display(frame); err != nil {
+			t.Fatal(err)
+		}
+		if len(conn.packets) != universes {
+			t.Fatalf("expected %d packets, got %d", universes, len(conn.packets))
+		}
+		for _, packet := range conn.packets {
+			if err := r.Handle(packet); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(frames) != n+1 {
+			t.Fatalf("frame %d: expected %d frames, got %d", n, n+1, len(frames))
+		}
+		if c := frames[n][len(frame)-1]; c != (color.RGBA{R: uint8(n + 1)}) {
+			t.Errorf("frame %d: unexpected color %v", n, c)
+		}
+	}
+	p, err := Parse(conn.packets[universes-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Universe != universes || p.Sequence != 2 {
+		t.Errorf("expected sequence 2 of universe %d, got %d of universe %d", universes, p.Sequence, p.Universe)
+	}
+}
+
+func TestSenderAllocs(t *testing.T) {
+	s := &Sender{Conn: discardConn{}, Universe: 1, SyncAddress: 1}
+	frame := make(ledsgo.Strip, 600)
+	s.Display(frame) // allocate the buffers
+	if n := testing.AllocsPerRun(10, func() { s.Display(frame) }); n != 0 {
+		t.Errorf("expected no allocations per frame, got %v", n)
+	}
+}
+
+// discardConn discards all written packets.
+type discardConn struct {
+	net.PacketConn
+}
+
+func (discardConn) WriteTo(buf []byte, addr net.Addr) (int, error) {
+	return len(buf), nil
+}
+
+func TestReceiveSeparateSyncUniverse(t *testing.T) {
+	conn := &packetConn{}
+	s := &Sender{Conn: conn, Universe: 1, CID: [16]byte{1}, SyncAddress: 1000}
+	frame := make(ledsgo.Strip, 300)
+	frame.FillSolid(color.RGBA{R: 10})
+	if err := s.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.packets) != 3 || conn.addrs[2] != "239.255.3.232:5568" {
+		t.Fatalf("unexpected packets: %d, sync sent to %s", len(conn.packets), conn.addrs[len(conn.addrs)-1])
+	}
+
+	var frames ledstest.FrameRecorder
+	r := NewReceiver(1, len(frame), &frames)
+	r.syncJoin = make(chan uint16, 1) // as set by ListenAndServe
+	for _, packet := range conn.packets[:2] {
+		if err := r.Handle(packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(frames) != 0 {
+		t.Fatal("frame displayed before the synchronization packet")
+	}
+	select {
+	case universe := <-r.syncJoin:
+		if universe != 1000 {
+			t.Errorf("expected to join sync universe 1000, got %d", universe)
+		}
+	default:
+		t.Error("sync universe was not joined")
+	}
+
+	// Synchronization packets for other universes must be ignored.
+	r.Handle(AppendSync(nil, [16]byte{1}, 0, 999))
+	if len(frames) != 0 {
+		t.Fatal("frame displayed on a synchronization packet for another universe")
+	}
+	if err := r.Handle(conn.packets[2]); err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame, got %d", len(frames))
+	}
+
+	// Without synchronization packets, the receiver must fall back to
+	// displaying complete frames.
+	frame.FillSolid(color.RGBA{G: 20})
+	conn.packets = conn.packets[:0]
+	if err := s.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	r.lastSync = r.lastSync.Add(-syncTimeout)
+	for _, packet := range conn.packets[:2] {
+		if err := r.Handle(packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(frames) != 2 || frames[1][0] != (color.RGBA{G: 20}) {
+		t.Fatalf("expected the frame to be displayed after the sync timeout, got %d frames", len(frames))
+	}
+}
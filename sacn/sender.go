@@ -0,0 +1,114 @@
+package sacn
+
+import (
+	"net"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Sender is a ledsgo.Displayer that sends frames as sACN data packets. Frames
+// that don't fit in a single universe are split across consecutive universes,
+// without splitting a single LED across two universes.
+type Sender struct {
+	// Conn is used to send the packets.
+	Conn net.PacketConn
+
+	// Addr is the address of the receiver. If it is nil, every universe is
+	// sent to its multicast address.
+	Addr net.Addr
+
+	// Universe is the first universe, starting at 1.
+	Universe uint16
+
+	// Order is the channel order expected by the receiver.
+	Order ledsgo.ColorOrder
+
+	// CID is the unique identifier of this source. It should be the same
+	// every time the program runs.
+	CID [16]byte
+
+	// SourceName is a human-readable name of this source.
+	SourceName string
+
+	// Priority of this source (0-200). Receivers use the data of the source
+	// with the highest priority. 0 means DefaultPriority.
+	Priority uint8
+
+	// SyncAddress is the universe on which synchronization packets are sent
+	// after every frame, so that receivers update all universes at the same
+	// time. 0 disables synchronization.
+	SyncAddress uint16
+
+	sequences    []uint8 // sequence number of every universe of the frame
+	syncSequence uint8
+	data         []byte
+	packet       []byte
+	mcast        net.UDPAddr // reused to avoid allocating an address per packet
+	mcastIP      [4]byte
+}
+
+// NewSender returns a sender that sends to the multicast addresses of the
+// universes, starting at the given universe.
+func NewSender(universe uint16, cid [16]byte, sourceName string) (*Sender, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{
+		Conn:       conn,
+		Universe:   universe,
+		CID:        cid,
+		SourceName: sourceName,
+	}, nil
+}
+
+// PixelsPerUniverse returns the number of LEDs that fit in a single universe
+// with the current channel order: 170 for RGB and 128 for RGBW.
+func (s *Sender) PixelsPerUniverse() int {
+	return MaxChannels / s.Order.Channels()
+}
+
+// Display sends the frame to the receivers.
+func (s *Sender) Display(frame ledsgo.Strip) error {
+	priority := s.Priority
+	if priority == 0 {
+		priority = DefaultPriority
+	}
+	perUniverse := s.PixelsPerUniverse()
+	if n := (len(frame) + perUniverse - 1) / perUniverse; len(s.sequences) < n {
+		// Every universe has its own sequence numbers, as required by the
+		// standard.
+		s.sequences = append(s.sequences, make([]uint8, n-len(s.sequences))...)
+	}
+	for i := 0; len(frame) != 0; i++ {
+		n := min(len(frame), perUniverse)
+		universe := s.Universe + uint16(i)
+		s.data = s.Order.AppendStrip(s.data[:0], frame[:n])
+		s.packet = AppendData(s.packet[:0], s.CID, s.SourceName, priority, s.SyncAddress, s.sequences[i], 0, universe, s.data)
+		s.sequences[i]++
+		if err := s.send(universe); err != nil {
+			return err
+		}
+		frame = frame[n:]
+	}
+	if s.SyncAddress != 0 {
+		s.packet = AppendSync(s.packet[:0], s.CID, s.syncSequence, s.SyncAddress)
+		s.syncSequence++
+		if err := s.send(s.SyncAddress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send sends the current packet for the given universe.
+func (s *Sender) send(universe uint16) error {
+	addr := s.Addr
+	if addr == nil {
+		s.mcastIP = [4]byte{239, 255, byte(universe >> 8), byte(universe)}
+		s.mcast = net.UDPAddr{IP: s.mcastIP[:], Port: Port}
+		addr = &s.mcast
+	}
+	_, err := s.Conn.WriteTo(s.packet, addr)
+	return err
+}
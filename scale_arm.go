@@ -0,0 +1,10 @@
+//go:build arm && !tinygo && !purego
+
+package ledsgo
+
+// scaleStrip scales the red, green and blue channels of all colors in src by
+// scale/256 (like scaleRGBA) and stores the result in dst, which may be the
+// same slice as src. It is implemented in assembly, see scale_arm.s.
+//
+//go:noescape
+func scaleStrip(dst, src Strip, scale uint8)
@@ -0,0 +1,40 @@
+//go:build arm && !tinygo && !purego
+
+#include "textflag.h"
+
+// func scaleStrip(dst, src Strip, scale uint8)
+//
+// Every color is loaded as a single 32-bit word (0xaabbggrr). Red and blue are
+// scaled together with a single multiplication, because they are 16 bits apart
+// and can't overflow into each other.
+TEXT ·scaleStrip(SB), NOSPLIT, $0-25
+	MOVW  dst_base+0(FP), R0
+	MOVW  dst_len+4(FP), R1
+	MOVW  src_base+12(FP), R2
+	MOVW  src_len+16(FP), R3
+	MOVBU scale+24(FP), R4
+	CMP   R1, R3
+	MOVW.LT R3, R1         // n = min(len(dst), len(src))
+	ADD   $1, R4           // k = scale + 1
+	MOVW  $0x00ff00ff, R8
+	CMP   $0, R1
+	B.EQ  done
+
+loop:
+	MOVW.P 4(R2), R5       // c = *src++
+	AND   R8, R5, R6       // rb = c & 0x00ff00ff
+	MUL   R4, R6           // rb *= k
+	AND   R6>>8, R8, R6    // rb = (rb >> 8) & 0x00ff00ff
+	MOVW  R5>>8, R7
+	AND   $0xff, R7        // g = (c >> 8) & 0xff
+	MUL   R4, R7           // g *= k
+	AND   $0xff00, R7      // g = (g >> 8) << 8
+	ORR   R7, R6
+	AND   $0xff000000, R5  // keep alpha
+	ORR   R5, R6
+	MOVW.P R6, 4(R0)       // *dst++ = result
+	SUB.S $1, R1
+	B.NE  loop
+
+done:
+	RET
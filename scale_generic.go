@@ -0,0 +1,26 @@
+//go:build !arm || tinygo || purego
+
+package ledsgo
+
+// scaleStrip scales the red, green and blue channels of all colors in src by
+// scale/256 (like scaleRGBA) and stores the result in dst, which may be the
+// same slice as src.
+//
+// This is the portable version. Some architectures have an assembly version,
+// which can be disabled using the purego build tag.
+func scaleStrip(dst, src Strip, scale uint8) {
+	n := min(len(dst), len(src))
+	dst = dst[:n]
+	src = src[:n]
+	k := uint32(scale) + 1
+	for i, c := range src {
+		// Scale red and blue with a single multiplication: they are 16 bits
+		// apart, so they can't overflow into each other.
+		rb := (uint32(c.R) | uint32(c.B)<<16) * k >> 8
+		g := uint32(c.G) * k >> 8
+		dst[i].R = uint8(rb)
+		dst[i].G = uint8(g)
+		dst[i].B = uint8(rb >> 16)
+		dst[i].A = c.A
+	}
+}
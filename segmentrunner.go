@@ -0,0 +1,172 @@
+package ledsgo
+
+import (
+	"image/color"
+	"sync"
+	"time"
+)
+
+// Segment is a range of LEDs in an installation.
+type Segment struct {
+	Start int // index of the first LED
+	Count int // number of LEDs
+}
+
+// SegmentRunner is an Effect that runs a different effect on every segment of
+// an installation, for example one effect on the shelves and another on the
+// ceiling of a room, each with its own standard parameters. The effects of
+// all segments are rendered at the same time on separate goroutines. Use it
+// as the effect of a Runner, which sends all segments to the Displayer at
+// once and applies the global brightness.
+//
+// All methods are safe for concurrent use. Like with ParallelRenderer, the
+// worker goroutines are started once, so rendering a frame doesn't allocate
+// memory.
+type SegmentRunner struct {
+	lock     sync.Mutex
+	segments []runnerSegment
+	jobs     chan *runnerSegment
+	wg       sync.WaitGroup
+}
+
+// runnerSegment is the state of a single segment of a SegmentRunner.
+type runnerSegment struct {
+	Segment
+	effect    Effect
+	params    StandardParams
+	paramsSet bool          // whether the standard parameters have been set
+	clear     bool          // clear the segment before rendering it
+	part      Strip         // part of the frame that is being rendered
+	t         time.Duration // time of the frame that is being rendered
+}
+
+// NewSegmentRunner returns a new SegmentRunner for the given segments, which
+// must not overlap. LEDs that are not part of any segment are left unchanged.
+// All segments start without an effect and with the default standard
+// parameters. Call Close to stop the worker goroutines.
+func NewSegmentRunner(segments ...Segment) *SegmentRunner {
+	r := &SegmentRunner{
+		segments: make([]runnerSegment, len(segments)),
+		jobs:     make(chan *runnerSegment, len(segments)),
+	}
+	for i, seg := range segments {
+		r.segments[i] = runnerSegment{Segment: seg, params: DefaultStandardParams()}
+		go r.work()
+	}
+	return r
+}
+
+// work renders segments until the runner is closed.
+func (r *SegmentRunner) work() {
+	for seg := range r.jobs {
+		seg.effect.Render(seg.part, seg.t)
+		r.wg.Done()
+	}
+}
+
+// Len returns the number of segments.
+func (r *SegmentRunner) Len() int {
+	return len(r.segments)
+}
+
+// Effect returns the effect of segment i, or nil if there is none.
+func (r *SegmentRunner) Effect(i int) Effect {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.segments[i].effect
+}
+
+// SetEffect changes the effect of segment i. The segment is cleared, so that
+// the new effect starts from black, and the effect gets the standard
+// parameters of the segment if it is a StandardEffect and they have been set
+// (see Runner.SetEffect). A nil effect makes the segment black.
+func (r *SegmentRunner) SetEffect(i int, effect Effect) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	seg := &r.segments[i]
+	seg.effect = effect
+	seg.clear = true
+	seg.applyParams()
+}
+
+// StandardParams returns the standard parameters of segment i.
+func (r *SegmentRunner) StandardParams(i int) StandardParams {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.segments[i].params
+}
+
+// SetStandardParams changes the standard parameters of segment i, see
+// Runner.SetStandardParams.
+func (r *SegmentRunner) SetStandardParams(i int, params StandardParams) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	seg := &r.segments[i]
+	seg.params = params
+	seg.paramsSet = true
+	seg.applyParams()
+}
+
+// Params returns the parameters of the effect of segment i, see
+// Runner.Params.
+func (r *SegmentRunner) Params(i int) []Param {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return EffectParams(r.segments[i].effect)
+}
+
+// Param returns the value of the parameter of the effect of segment i with
+// the given name. It returns false if there is no such parameter.
+func (r *SegmentRunner) Param(i int, name string) (int32, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return getParam(r.segments[i].effect, name)
+}
+
+// SetParam changes the parameter of the effect of segment i with the given
+// name, see Runner.SetParam.
+func (r *SegmentRunner) SetParam(i int, name string, value int32) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return setParam(r.segments[i].effect, name, value)
+}
+
+// applyParams passes the standard parameters to the effect of the segment, if
+// it uses them.
+func (seg *runnerSegment) applyParams() {
+	if !seg.paramsSet {
+		return
+	}
+	if e, ok := seg.effect.(StandardEffect); ok {
+		e.SetStandardParams(seg.params)
+	}
+}
+
+// Render implements Effect. It returns once all segments have been rendered.
+func (r *SegmentRunner) Render(frame Strip, t time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i := range r.segments {
+		seg := &r.segments[i]
+		start := min(max(seg.Start, 0), len(frame))
+		end := min(max(seg.Start+seg.Count, start), len(frame))
+		seg.part = frame[start:end]
+		if seg.clear || seg.effect == nil {
+			seg.part.FillSolid(color.RGBA{})
+			seg.clear = false
+		}
+		if seg.effect == nil || len(seg.part) == 0 {
+			continue
+		}
+		seg.t = t
+		r.wg.Add(1)
+		r.jobs <- seg
+	}
+	r.wg.Wait()
+}
+
+// Close stops the worker goroutines. The runner must not be used anymore after
+// calling Close.
+func (r *SegmentRunner) Close() {
+	close(r.jobs)
+}
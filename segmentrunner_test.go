@@ -0,0 +1,88 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestSegmentRunner(t *testing.T) {
+	r := NewSegmentRunner(Segment{Start: 0, Count: 3}, Segment{Start: 3, Count: 2}, Segment{Start: 6, Count: 10})
+	defer r.Close()
+	if r.Len() != 3 {
+		t.Errorf("expected 3 segments, got %d", r.Len())
+	}
+
+	var lengths [2]int
+	r.SetEffect(0, EffectFunc(func(frame Strip, t time.Duration) {
+		lengths[0] = len(frame)
+		frame.FillSolid(color.RGBA{R: uint8(t)})
+	}))
+	r.SetEffect(1, &speedEffect{})
+	r.SetStandardParams(1, StandardParams{Speed: 50, Intensity: 60})
+	if p := r.StandardParams(2); p != DefaultStandardParams() {
+		t.Errorf("expected the default parameters for segment 2, got %+v", p)
+	}
+	r.SetEffect(2, EffectFunc(func(frame Strip, t time.Duration) {
+		lengths[1] = len(frame)
+		frame.FillSolid(color.RGBA{B: 1})
+	}))
+
+	frame := make(Strip, 8)
+	frame[5] = color.RGBA{G: 99} // not part of any segment
+	r.Render(frame, 7)
+	expected := Strip{
+		{R: 7}, {R: 7}, {R: 7},
+		{R: 50, G: 60, B: RainbowColors[0].B}, {R: 50, G: 60, B: RainbowColors[0].B},
+		{G: 99},
+		{B: 1}, {B: 1},
+	}
+	for i, c := range frame {
+		if c != expected[i] {
+			t.Errorf("LED %d: expected %v, got %v", i, expected[i], c)
+		}
+	}
+	if lengths != [2]int{3, 2} {
+		t.Errorf("unexpected segment lengths %v", lengths)
+	}
+
+	// Removing the effect of a segment makes it black.
+	r.SetEffect(0, nil)
+	if r.Effect(0) != nil {
+		t.Error("expected no effect")
+	}
+	r.Render(frame, 8)
+	if !isBlack(frame[:3]) || frame[3].R != 50 {
+		t.Errorf("unexpected frame after removing an effect: %v", frame)
+	}
+
+	// Segments are clipped to the frame.
+	clipped := NewSegmentRunner(Segment{Start: -2, Count: 4}, Segment{Start: 5, Count: -3})
+	defer clipped.Close()
+	for i := 0; i < clipped.Len(); i++ {
+		clipped.SetEffect(i, EffectFunc(func(frame Strip, t time.Duration) {
+			frame.FillSolid(color.RGBA{R: 1})
+		}))
+	}
+	frame.FillSolid(color.RGBA{})
+	clipped.Render(frame, 0)
+	if frame[0].R != 1 || frame[1].R != 1 || !isBlack(frame[2:]) {
+		t.Errorf("unexpected frame with clipped segments: %v", frame)
+	}
+
+	// The parameters of the effect of a segment can be changed.
+	r.SetEffect(2, &Rain{})
+	if !r.SetParam(2, "Intensity", 300) || r.SetParam(0, "Intensity", 1) {
+		t.Error("expected an Intensity parameter for segment 2 only")
+	}
+	if value, _ := r.Param(2, "Intensity"); value != 255 || len(r.Params(2)) != 1 {
+		t.Errorf("expected intensity 255, got %d", value)
+	}
+
+	// A SegmentRunner is used in a Runner, and doesn't allocate.
+	runner := NewRunner(discardDisplayer{}, 8)
+	runner.SetEffect(r)
+	if allocs := testing.AllocsPerRun(10, func() { runner.Frame() }); allocs != 0 {
+		t.Errorf("expected no allocations, got %.0f", allocs)
+	}
+}
@@ -0,0 +1,9 @@
+// Package simulator shows LED strips and matrices in a desktop window, so that
+// effects can be developed at full speed before flashing them to a board.
+//
+// The simulator uses Ebitengine (github.com/hajimehoshi/ebiten/v2) and is only
+// built with the ebiten build tag, to avoid pulling in a graphics stack for
+// normal builds:
+//
+//	go run -tags=ebiten ./cmd/myeffect
+package simulator
@@ -0,0 +1,87 @@
+//go:build ebiten
+
+package simulator
+
+import (
+	"sync"
+
+	"github.com/aykevl/ledsgo"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Window is a ledsgo.Displayer that shows frames in a desktop window. Every LED
+// is drawn as a square of Scale by Scale pixels.
+type Window struct {
+	Title string
+
+	width  int
+	height int
+	scale  int
+
+	lock sync.Mutex
+	pix  []byte // RGBA pixels of the last frame
+}
+
+// NewWindow creates a new simulator window for the given number of LEDs. The
+// LEDs are laid out row by row in rows of width LEDs, like a Canvas. Use a
+// width of 0 to show a single strip. The window isn't shown until Run is
+// called.
+func NewWindow(numLEDs, width, scale int) *Window {
+	if width <= 0 {
+		width = max(numLEDs, 1) // at least one column, also without LEDs
+	}
+	if scale <= 0 {
+		scale = 1
+	}
+	height := (numLEDs + width - 1) / width
+	return &Window{
+		Title:  "ledsgo",
+		width:  width,
+		height: height,
+		scale:  scale,
+		pix:    make([]byte, width*height*4),
+	}
+}
+
+// Display stores the frame, to be drawn on the next screen refresh. It is safe
+// to call from any goroutine.
+func (w *Window) Display(frame ledsgo.Strip) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for i, c := range frame {
+		if i*4 >= len(w.pix) {
+			break
+		}
+		w.pix[i*4+0] = c.R
+		w.pix[i*4+1] = c.G
+		w.pix[i*4+2] = c.B
+		w.pix[i*4+3] = 0xff
+	}
+	return nil
+}
+
+// Run shows the window and blocks until it is closed. It must be called from
+// the main goroutine, so effects should be rendered in a separate goroutine.
+func (w *Window) Run() error {
+	ebiten.SetWindowTitle(w.Title)
+	ebiten.SetWindowSize(w.width*w.scale, w.height*w.scale)
+	return ebiten.RunGame(w)
+}
+
+// Update implements ebiten.Game.
+func (w *Window) Update() error {
+	return nil
+}
+
+// Draw implements ebiten.Game.
+func (w *Window) Draw(screen *ebiten.Image) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	screen.WritePixels(w.pix)
+}
+
+// Layout implements ebiten.Game. The screen has one pixel per LED and is scaled
+// up to the window size by Ebitengine.
+func (w *Window) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return w.width, w.height
+}
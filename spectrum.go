@@ -0,0 +1,129 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+// spectrumColors is the default palette of SpectrumBars: green at the bottom,
+// through yellow to red at the top, like the level meters of audio equipment.
+var spectrumColors = GradientPalette{
+	{0, color.RGBA{0, 255, 0, 0}},
+	{160, color.RGBA{255, 255, 0, 0}},
+	{255, color.RGBA{255, 0, 0, 0}},
+}.Palette16()
+
+// SpectrumBars is an Effect that shows the frequency bands of an AudioSource
+// as bars on a LED matrix, with the lowest frequencies on the left. The bars
+// jump up with the music and fall back slowly, and a peak dot above every bar
+// shows its recent highest point.
+//
+// The frame is treated as a Canvas of the given size: the LEDs are row by
+// row, starting at the top left.
+type SpectrumBars struct {
+	Source        AudioSource
+	Width, Height int
+
+	// Bars is the number of bars, which are divided over the width of the
+	// matrix. If it is zero, every column is a bar.
+	Bars int
+
+	// Palette colors the bars by height: the bottom row has the color at
+	// index 0 and the top row the color at index 240. If it is nil, the bars
+	// go from green to red.
+	Palette *Palette16
+
+	// Peak is the color of the peak dots. If it is black, there are no peak
+	// dots.
+	Peak color.RGBA
+
+	// Fall is the time a bar takes to fall from the top of the matrix to the
+	// bottom. Peak dots fall four times slower. The default (set by
+	// NewSpectrumBars) is 400ms.
+	Fall time.Duration
+
+	timer   frameTimer
+	bands   []uint16
+	heights []uint32 // .16 of the height of the matrix
+	peaks   []uint32 // .16
+}
+
+// NewSpectrumBars returns a new spectrum analyzer with default settings for
+// the given audio source and matrix size.
+func NewSpectrumBars(source AudioSource, width, height int) *SpectrumBars {
+	return &SpectrumBars{
+		Source: source,
+		Width:  width,
+		Height: height,
+		Peak:   color.RGBA{255, 255, 255, 0},
+		Fall:   400 * time.Millisecond,
+	}
+}
+
+// SetStandardParams implements StandardEffect. The palette colors the bars
+// (green to red if it is nil).
+func (s *SpectrumBars) SetStandardParams(params StandardParams) {
+	s.Palette = params.Palette
+}
+
+// Params implements ParamEffect.
+func (s *SpectrumBars) Params() []Param {
+	return []Param{
+		{Name: "Bars", Min: 0, Max: int32(max(s.Width, 0)), Default: 0, Value: &s.Bars},
+	}
+}
+
+// Render implements Effect.
+func (s *SpectrumBars) Render(frame Strip, t time.Duration) {
+	bars := s.Bars
+	if bars <= 0 {
+		bars = s.Width
+	}
+	if len(s.bands) != bars {
+		s.bands = make([]uint16, bars)
+		s.heights = make([]uint32, bars)
+		s.peaks = make([]uint32, bars)
+	}
+	s.Source.Bands(s.bands)
+
+	// Bars jump up to the level of their band, and fall back slowly.
+	dt := s.timer.step(t)
+	fall, peakFall := uint32(0x10000), uint32(0x10000)
+	if s.Fall > 0 {
+		fall = uint32(min(uint64(dt)<<16/uint64(s.Fall), 0x10000))
+		peakFall = uint32(min(uint64(dt)<<16/uint64(s.Fall*4), 0x10000))
+	}
+	for i, band := range s.bands {
+		s.heights[i] = max(s.heights[i]-min(fall, s.heights[i]), uint32(band))
+		s.peaks[i] = max(s.peaks[i]-min(peakFall, s.peaks[i]), s.heights[i])
+	}
+
+	palette := s.Palette
+	if palette == nil {
+		palette = &spectrumColors
+	}
+	canvas := frameCanvas(frame, s.Width, s.Height)
+	canvas.Fill(color.RGBA{})
+	if canvas.Height == 0 {
+		return
+	}
+	for x := 0; x < canvas.Width; x++ {
+		bar := x * bars / canvas.Width
+		height := s.heights[bar] * uint32(canvas.Height) >> 8 // .8 rows
+		for row := 0; row < canvas.Height; row++ {
+			covered := min(max(int32(height)-int32(row)<<8, 0), 0x100) // .8
+			if covered == 0 {
+				break
+			}
+			c := palette.ColorAt(uint8(row * 240 / max(canvas.Height-1, 1)))
+			if covered != 0x100 {
+				c = scaleRGBA(c, uint8(covered))
+			}
+			canvas.SetRGBA(x, canvas.Height-1-row, c)
+		}
+		if s.Peak != (color.RGBA{}) && s.peaks[bar] != 0 {
+			row := min(int(s.peaks[bar]*uint32(canvas.Height)>>16), canvas.Height-1)
+			canvas.SetRGBA(x, canvas.Height-1-row, s.Peak)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+// testBands is an AudioSource with a separate level in every band.
+type testBands []uint16
+
+func (a testBands) Level() uint16 {
+	return 0
+}
+
+func (a testBands) Bands(bands []uint16) {
+	copy(bands, a)
+}
+
+func TestSpectrumBars(t *testing.T) {
+	audio := testBands{0xffff, 0x8000, 0x2000, 0}
+	s := NewSpectrumBars(audio, 8, 4)
+	s.Bars = 4
+	frame := make(Strip, 8*4)
+	s.Render(frame, 0)
+	c := &Canvas{Width: 8, Height: 4, Pix: frame}
+	heights := func() (h [8]int) {
+		for x := range h {
+			for y := 0; y < 4; y++ {
+				if c.RGBAAt(x, y) != (color.RGBA{}) {
+					h[x]++
+				}
+			}
+		}
+		return h
+	}
+	if h := heights(); h != [8]int{4, 4, 3, 3, 1, 1, 0, 0} {
+		t.Errorf("unexpected bar heights %v", h)
+	}
+	if c.RGBAAt(0, 3) != (color.RGBA{0, 255, 0, 0}) || c.RGBAAt(0, 0) != s.Peak {
+		t.Errorf("expected a green bar with a peak dot, got %v and %v", c.RGBAAt(0, 3), c.RGBAAt(0, 0))
+	}
+	if c.RGBAAt(2, 1) != s.Peak || c.RGBAAt(4, 3) != s.Peak {
+		t.Errorf("expected peak dots above the bars, got %v and %v", c.RGBAAt(2, 1), c.RGBAAt(4, 3))
+	}
+
+	// When the music stops, the bars fall faster than the peak dots.
+	for i := range audio {
+		audio[i] = 0
+	}
+	s.Render(frame, 200*time.Millisecond)
+	if h := heights(); h != [8]int{3, 3, 1, 1, 0, 0, 0, 0} {
+		t.Errorf("after 200ms: unexpected heights %v", h)
+	}
+	if s.heights[0] != 0xffff-0x8000 || s.peaks[0] != 0xffff-0x2000 {
+		t.Errorf("after 200ms: unexpected bar %#x and peak %#x", s.heights[0], s.peaks[0])
+	}
+	s.Render(frame, 2*time.Second)
+	if !isBlack(frame) {
+		t.Errorf("after 2s: expected the bars to be gone, got %v", frame)
+	}
+
+	if n := testing.AllocsPerRun(10, func() { s.Render(frame, 3*time.Second) }); n != 0 {
+		t.Errorf("expected no allocations, got %.1f", n)
+	}
+}
@@ -0,0 +1,80 @@
+package ledsgo
+
+// SpotNoise is sparse convolution noise: a number of spots is scattered over
+// every cell of a grid at pseudo-random positions and with pseudo-random
+// brightness, and the noise value at a point is the sum of the spots that
+// cover it. Unlike simplex noise, the size, shape and density of the blobs can
+// be controlled directly, which makes it suitable for bokeh-like backgrounds.
+//
+// Coordinates are 19.12 fixed-point values like those of Noise2, where a cell
+// of the grid is 0x1000 wide and high.
+type SpotNoise struct {
+	// Density is the number of spots in every cell.
+	Density uint8
+
+	// Radius is the radius of a spot. It is limited to 0x1000, the size of a
+	// cell.
+	Radius int32
+
+	// Kernel returns the brightness (0..0xffff) of a spot at a given squared
+	// distance from its center, where 0 is the center and 0xffff is the edge
+	// at Radius. For example, a spot with a sharp edge:
+	//
+	//	func(d2 uint16) uint16 { return 0xffff - EaseInCubic16(d2) }
+	//
+	// If it is nil, a smooth bump is used that fades out to zero at the edge.
+	Kernel func(d2 uint16) uint16
+
+	// Seed selects a different pattern of spots.
+	Seed uint32
+}
+
+// At returns the sum of all spots covering (x, y), saturating at 0xffff.
+func (n *SpotNoise) At(x, y int32) uint16 {
+	radius := min(n.Radius, 0x1000)
+	if radius <= 0 || n.Density == 0 {
+		return 0
+	}
+	r2 := uint64(radius) * uint64(radius) // .24
+	invR2 := (1 << 48) / r2               // .24: 1/r2
+	cellX, cellY := x>>12, y>>12          // .0
+	var sum uint32                        // .16
+	for cy := cellY - 1; cy <= cellY+1; cy++ {
+		for cx := cellX - 1; cx <= cellX+1; cx++ {
+			for k := uint32(0); k < uint32(n.Density); k++ {
+				h := spotHash(uint32(cx), uint32(cy), k, n.Seed)
+
+				// Differences are calculated with wrapping arithmetic, so
+				// that cells at the edge of the int32 range work too.
+				dx := int64(int32(uint32(x) - (uint32(cx)<<12 + h&0xfff)))     // .12
+				dy := int64(int32(uint32(y) - (uint32(cy)<<12 + h>>12&0xfff))) // .12
+				d2 := uint64(dx*dx + dy*dy)                                    // .24
+				if d2 >= r2 {
+					continue
+				}
+				u := uint16(d2 * invR2 >> 32) // .16: squared distance relative to the radius
+				var brightness uint32         // .16
+				if n.Kernel != nil {
+					brightness = uint32(n.Kernel(u))
+				} else {
+					brightness = uint32(0xffff-u) * uint32(0xffff-u) >> 16 // (1-u)²
+				}
+				sum += brightness * (h >> 24) >> 8 // .16 * .8 >> 8 = .16
+			}
+		}
+	}
+	return uint16(min(sum, 0xffff))
+}
+
+// spotHash returns a pseudo-random value for the spot k in cell (x, y). It is
+// a simple integer hash that is good enough for visuals. The lower 24 bits are
+// used for the position of the spot, the upper 8 bits for its brightness.
+func spotHash(x, y, k, seed uint32) uint32 {
+	h := x*0x9e3779b1 ^ y*0x85ebca77 ^ k*0xc2b2ae3d ^ seed*0x27d4eb2f
+	h ^= h >> 15
+	h *= 0x2c1b3c6d
+	h ^= h >> 12
+	h *= 0x297a2d39
+	h ^= h >> 15
+	return h
+}
@@ -0,0 +1,78 @@
+package ledsgo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpotNoise(t *testing.T) {
+	for _, n := range []SpotNoise{
+		{Density: 1, Radius: 0x1000},
+		{Density: 3, Radius: 0x0800, Seed: 5},
+		{Density: 2, Radius: 0x2000, Seed: 0xffffffff}, // radius is limited
+		{Density: 4, Radius: 0x0c00, Kernel: func(d2 uint16) uint16 { return 0xffff - EaseInCubic16(d2) }},
+	} {
+		// Floating point reference, which looks at more cells than necessary.
+		radius := math.Min(float64(n.Radius), 0x1000) / 4096
+		reference := func(x, y int32) float64 {
+			sum := 0.0
+			for cy := y>>12 - 2; cy <= y>>12+2; cy++ {
+				for cx := x>>12 - 2; cx <= x>>12+2; cx++ {
+					for k := uint32(0); k < uint32(n.Density); k++ {
+						h := spotHash(uint32(cx), uint32(cy), k, n.Seed)
+						dx := (float64(x) - float64(cx)*4096 - float64(h&0xfff)) / 4096
+						dy := (float64(y) - float64(cy)*4096 - float64(h>>12&0xfff)) / 4096
+						u := (dx*dx + dy*dy) / (radius * radius)
+						if u >= 1 {
+							continue
+						}
+						brightness := (1 - u) * (1 - u)
+						if n.Kernel != nil {
+							brightness = float64(n.Kernel(uint16(u*65536))) / 65536
+						}
+						sum += brightness * float64(h>>24) / 256
+					}
+				}
+			}
+			return math.Min(sum, 1)
+		}
+
+		var covered int
+		for y := int32(-0x6000); y < 0x6000; y += 0x107 {
+			for x := int32(-0x6000); x < 0x6000; x += 0x0fd {
+				v := n.At(x, y)
+				if v != 0 {
+					covered++
+				}
+				if diff := math.Abs(float64(v)/65536 - reference(x, y)); diff > 0.002 {
+					t.Errorf("%+v: At(%d, %d) = %#x, expected %.5f (diff %.5f)", n, x, y, v, reference(x, y), diff)
+				}
+			}
+		}
+		if covered == 0 {
+			t.Errorf("%+v: no spots found", n)
+		}
+	}
+
+	// Spots must also work at the edges of the coordinate space.
+	n := SpotNoise{Density: 8, Radius: 0x1000}
+	for _, p := range [][2]int32{{math.MaxInt32, 0}, {math.MinInt32, 0}, {0, math.MaxInt32}, {math.MinInt32, math.MinInt32}} {
+		n.At(p[0], p[1])
+	}
+
+	// Saturation.
+	n = SpotNoise{Density: 255, Radius: 0x1000, Kernel: func(uint16) uint16 { return 0xffff }}
+	if v := n.At(0x800, 0x800); v != 0xffff {
+		t.Errorf("expected saturation at 0xffff, got %#x", v)
+	}
+	if v := (&SpotNoise{Density: 4}).At(0, 0); v != 0 {
+		t.Errorf("expected 0 with a zero radius, got %#x", v)
+	}
+}
+
+func BenchmarkSpotNoise(b *testing.B) {
+	n := SpotNoise{Density: 2, Radius: 0x0c00}
+	for i := 0; i < b.N; i++ {
+		n.At(int32(i)*0x35, 0x1234)
+	}
+}
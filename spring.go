@@ -0,0 +1,106 @@
+package ledsgo
+
+import (
+	"time"
+)
+
+// Spring is a damped spring that pulls a value towards a target, for natural
+// looking motion of dots and brightness: with little damping the value
+// overshoots the target and oscillates around it before it settles, with
+// more damping it glides into place. The value and target are 16.16
+// fixed-point values in any unit, such as the 24.8 position of a dot shifted
+// left by 8 bits.
+//
+// The zero value doesn't move: set the frequency first, for example with
+// NewSpring.
+type Spring struct {
+	// Position is the current value and Velocity its speed per second, as
+	// 16.16 fixed-point values.
+	Position, Velocity int32
+
+	// Target is the value the spring pulls towards.
+	Target int32
+
+	// Frequency is the number of oscillations per second of the undamped
+	// spring, as a 8.8 fixed-point value. Higher values are faster.
+	Frequency uint16
+
+	// Damping is the damping ratio as a .16 fixed-point value: 0 oscillates
+	// forever, 0x10000 (critical damping) is the fastest movement without
+	// overshoot, and higher values move slower. Values above 16 (0x100000)
+	// are treated as 16.
+	Damping uint32
+}
+
+// NewSpring returns a spring at rest at the given position, with the given
+// frequency (8.8 Hz) and damping ratio (.16).
+func NewSpring(position int32, frequency uint16, damping uint32) *Spring {
+	return &Spring{
+		Position:  position,
+		Target:    position,
+		Frequency: frequency,
+		Damping:   damping,
+	}
+}
+
+// Maximum step of the simulation, as .16 seconds. Longer updates are split in
+// multiple steps.
+const springStep = 1 << 16 / 250 // 4ms
+
+// Update moves the spring forward in time by dt and returns the new position.
+// Stiff springs are simulated in smaller steps to keep them stable, so that
+// the cost of an update grows with the frequency and the damping.
+func (s *Spring) Update(dt time.Duration) int32 {
+	const twoPi = 411775                                       // .16
+	omega := int64(s.Frequency) * twoPi >> 8                   // .16: angular frequency
+	omega2 := omega * omega >> 16                              // .16
+	damping := 2 * int64(min(s.Damping, 16<<16)) * omega >> 16 // .16
+	remaining := int64(dt << 16 / time.Second)                 // .16 seconds
+	maxStep := int64(springStep)
+	if rate := omega + damping; rate != 0 {
+		// Semi-implicit Euler becomes unstable when omega*step or
+		// damping*step get too large. Keeping their sum at most 1 is well
+		// within the limits.
+		maxStep = min(max(1<<32/rate, 1), springStep)
+	}
+	x, v := int64(s.Position), int64(s.Velocity)
+	for remaining > 0 {
+		step := min(remaining, maxStep)
+		remaining -= step
+		// Semi-implicit Euler: update the velocity first, then move with
+		// the new velocity. This keeps the oscillation stable.
+		accel := -(omega2*(x-int64(s.Target))>>16 + damping*v>>16) // .16 per second squared
+		v += accel * step >> 16
+		x += v * step >> 16
+	}
+	s.Position, s.Velocity = int32(x), int32(v)
+	return s.Position
+}
+
+// Settled returns whether the spring is at rest at its target: within the
+// given distance of it, and moving slower than that distance per second.
+func (s *Spring) Settled(tolerance int32) bool {
+	d := s.Position - s.Target
+	return d <= tolerance && d >= -tolerance && s.Velocity <= tolerance && s.Velocity >= -tolerance
+}
+
+// DampedSine16 returns a sine wave that dies out over time, like a struck bell
+// or a plucked string: it starts at 0 at time 0, swings once every period and
+// halves in amplitude every halfLife. The result is a .15 fixed-point value,
+// like the result of Sin16. This is useful to add a wobble or a ring to a
+// position or brightness after an event, without keeping any state.
+func DampedSine16(t, period, halfLife time.Duration) int16 {
+	if t < 0 || period <= 0 || halfLife <= 0 {
+		return 0
+	}
+	phase := uint16((t % period) << 16 / period)
+	halvings := t / halfLife
+	if halvings >= 16 {
+		return 0
+	}
+	// 2^-x for the fraction of the last halving is approximated linearly
+	// between 1 and 0.5.
+	frac := int32((t % halfLife) << 16 / halfLife) // .16
+	amplitude := (0x10000 - frac/2) >> halvings    // .16
+	return int16(int32(Sin16(phase)) * amplitude >> 16)
+}
@@ -0,0 +1,87 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpring(t *testing.T) {
+	// An underdamped spring overshoots and then settles at the target.
+	s := NewSpring(0, 2<<8, 0x3000) // 2Hz, damping 0.19
+	s.Target = 100 << 16
+	var maxPos int32
+	for i := 0; i < 500; i++ {
+		maxPos = max(maxPos, s.Update(10*time.Millisecond))
+	}
+	if maxPos < 140<<16 || maxPos > 160<<16 {
+		t.Errorf("expected an overshoot of about 55%%, got a maximum of %d", maxPos>>16)
+	}
+	if !s.Settled(1 << 16) {
+		t.Errorf("expected the spring to settle, got %d at speed %d", s.Position>>16, s.Velocity>>16)
+	}
+
+	// A critically damped spring doesn't overshoot.
+	s = NewSpring(0, 2<<8, 0x10000)
+	s.Target = 100 << 16
+	maxPos = 0
+	for i := 0; i < 20; i++ {
+		maxPos = max(maxPos, s.Update(50*time.Millisecond)) // longer than a step
+	}
+	if maxPos > 100<<16 || !s.Settled(1<<16) {
+		t.Errorf("expected no overshoot and a settled spring, got a maximum of %d and %d", maxPos>>16, s.Position>>16)
+	}
+
+	// A spring at rest stays at rest.
+	s = NewSpring(5<<16, 10<<8, 0x8000)
+	if pos := s.Update(time.Second); pos != 5<<16 || !s.Settled(0) {
+		t.Errorf("expected the spring to stay at rest, got %d", pos)
+	}
+}
+
+func TestSpringStiff(t *testing.T) {
+	// Stiff and heavily damped springs stay stable, even when updated in
+	// steps that are much longer than their period. An undamped spring swings
+	// between the start and twice the target.
+	for _, tc := range []struct {
+		frequency uint16
+		damping   uint32
+	}{
+		{255 << 8, 0x3000},
+		{255 << 8, 0x10000},
+		{0xffff, 0},
+		{100 << 8, 0xffffffff},
+		{2 << 8, 0xffffffff},
+	} {
+		s := NewSpring(0, tc.frequency, tc.damping)
+		s.Target = 100 << 16
+		for i := 0; i < 100; i++ {
+			if pos := s.Update(20 * time.Millisecond); pos < -10<<16 || pos > 210<<16 {
+				t.Errorf("frequency %#x, damping %#x: position %d after %d updates", tc.frequency, tc.damping, pos>>16, i)
+				break
+			}
+		}
+		if tc.damping != 0 && tc.damping < 0x100000 && !s.Settled(1<<16) {
+			t.Errorf("frequency %#x, damping %#x: expected the spring to settle, got %d", tc.frequency, tc.damping, s.Position>>16)
+		}
+	}
+}
+
+func TestDampedSine16(t *testing.T) {
+	period, halfLife := 100*time.Millisecond, 200*time.Millisecond
+	for _, tc := range []struct {
+		t        time.Duration
+		min, max int16
+	}{
+		{0, 0, 0},
+		{25 * time.Millisecond, 30000, 32767},   // first peak
+		{75 * time.Millisecond, -32767, -25000}, // first trough
+		{225 * time.Millisecond, 15000, 16400},  // a half life later
+		{425 * time.Millisecond, 7500, 8200},    // two half lives later
+		{10 * time.Second, 0, 0},
+		{-time.Second, 0, 0},
+	} {
+		if v := DampedSine16(tc.t, period, halfLife); v < tc.min || v > tc.max {
+			t.Errorf("DampedSine16(%v): expected %d..%d, got %d", tc.t, tc.min, tc.max, v)
+		}
+	}
+}
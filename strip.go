@@ -23,3 +23,105 @@ func (s Strip) FillSolid(color color.RGBA) {
 		s[i] = color
 	}
 }
+
+// FillRainbow fills the strip with a rainbow at full saturation and
+// brightness, like fill_rainbow in FastLED. The first LED gets the given hue
+// and every next LED has its hue increased by hueinc.
+func (s Strip) FillRainbow(hue, hueinc uint16) {
+	for i := range s {
+		s[i] = Color{H: hue, S: 255, V: 255}.Spectrum()
+		hue += hueinc
+	}
+}
+
+// FillGradient fills the strip with a linear gradient in RGB space, where the
+// first LED gets the color from and the last LED gets the color to.
+func (s Strip) FillGradient(from, to color.RGBA) {
+	if len(s) == 0 {
+		return
+	}
+	if len(s) == 1 {
+		s[0] = from
+		return
+	}
+	// Step through the gradient with 8.16 fixed-point values, so that only
+	// three divisions are needed for the whole strip.
+	n := int32(len(s) - 1)
+	r := int32(from.R) << 16                      // .16
+	g := int32(from.G) << 16                      // .16
+	b := int32(from.B) << 16                      // .16
+	dr := (int32(to.R) - int32(from.R)) << 16 / n // .16
+	dg := (int32(to.G) - int32(from.G)) << 16 / n // .16
+	db := (int32(to.B) - int32(from.B)) << 16 / n // .16
+	for i := range s {
+		s[i] = color.RGBA{uint8((r + 0x8000) >> 16), uint8((g + 0x8000) >> 16), uint8((b + 0x8000) >> 16), 0}
+		r += dr
+		g += dg
+		b += db
+	}
+}
+
+// FillPalette fills the strip with colors from the palette, like fill_palette
+// in FastLED. The first LED gets the color at the start index and the index is
+// increased by inc for every next LED.
+func (s Strip) FillPalette(p *Palette16, start, inc uint8) {
+	for i := range s {
+		s[i] = p.ColorAt(start)
+		start += inc
+	}
+}
+
+// FadeAll dims all LEDs by amount/256, like fadeToBlackBy in FastLED. Calling
+// it every frame with a small amount leaves fading trails behind moving
+// objects.
+func (s Strip) FadeAll(amount uint8) {
+	scaleStrip(s, s, 255-amount)
+}
+
+// scaleRGBA scales the red, green and blue channels of the color by
+// scale/256, where 255 keeps the color unchanged and 0 makes it black.
+func scaleRGBA(c color.RGBA, scale uint8) color.RGBA {
+	return color.RGBA{Scale8(c.R, scale), Scale8(c.G, scale), Scale8(c.B, scale), c.A}
+}
+
+// addRGBA adds two colors together, saturating each channel at 255.
+func addRGBA(a, b color.RGBA) color.RGBA {
+	return color.RGBA{QAdd8(a.R, b.R), QAdd8(a.G, b.G), QAdd8(a.B, b.B), a.A}
+}
+
+// addWeighted adds the color c, scaled by weight/256, to the color dst. The
+// weight must be in the range 0..256 (inclusive).
+func addWeighted(dst, c color.RGBA, weight uint16) color.RGBA {
+	c.R = uint8(uint16(c.R) * weight >> 8)
+	c.G = uint8(uint16(c.G) * weight >> 8)
+	c.B = uint8(uint16(c.B) * weight >> 8)
+	return addRGBA(dst, c)
+}
+
+// DrawDot draws a single dot at a fractional position, spreading the color
+// over the two nearest LEDs (like Wu's anti-aliased lines). This makes slowly
+// moving dots glide smoothly instead of visibly stepping from LED to LED. The
+// position is a 24.8 fixed-point LED index and the color is added to the
+// existing colors of the LEDs. Parts of the dot outside the strip are not
+// drawn.
+func (s Strip) DrawDot(pos int32, c color.RGBA) {
+	i := int(pos >> 8)
+	frac := uint16(pos & 0xff)
+	if i >= 0 && i < len(s) {
+		s[i] = addWeighted(s[i], c, 256-frac)
+	}
+	if frac != 0 && i+1 >= 0 && i+1 < len(s) {
+		s[i+1] = addWeighted(s[i+1], c, frac)
+	}
+}
+
+// Blend returns a mix of the colors a and b, where frac is the fraction of the
+// way from a to b: 0 returns a and 255 returns (almost) b.
+func Blend(a, b color.RGBA, frac uint8) color.RGBA {
+	return color.RGBA{
+		R: Lerp8by8(a.R, b.R, frac),
+		G: Lerp8by8(a.G, b.G, frac),
+		B: Lerp8by8(a.B, b.B, frac),
+		A: Lerp8by8(a.A, b.A, frac),
+	}
+}
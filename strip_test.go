@@ -0,0 +1,135 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestFillGradient(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 10, 255, 1000} {
+		s := make(Strip, n)
+		from := color.RGBA{R: 255, G: 10, B: 0}
+		to := color.RGBA{R: 0, G: 20, B: 255}
+		s.FillGradient(from, to)
+		if s[0] != from {
+			t.Errorf("n=%d: expected the first LED to be %v, got %v", n, from, s[0])
+		}
+		if n > 1 && s[n-1] != to {
+			t.Errorf("n=%d: expected the last LED to be %v, got %v", n, to, s[n-1])
+		}
+		for i := 1; i < n; i++ {
+			if s[i].R > s[i-1].R || s[i].G < s[i-1].G || s[i].B < s[i-1].B {
+				t.Errorf("n=%d: LED %d: gradient is not monotonic: %v after %v", n, i, s[i], s[i-1])
+				break
+			}
+		}
+	}
+	Strip(nil).FillGradient(color.RGBA{}, color.RGBA{}) // must not panic
+}
+
+func TestFillRainbow(t *testing.T) {
+	s := make(Strip, 3)
+	s.FillRainbow(0, 0x10000/3)
+	for i, c := range s {
+		expected := Color{H: uint16(i * 0x10000 / 3), S: 255, V: 255}.Spectrum()
+		if c != expected {
+			t.Errorf("LED %d: expected %v, got %v", i, expected, c)
+		}
+	}
+}
+
+func TestFillPalette(t *testing.T) {
+	s := make(Strip, 16)
+	s.FillPalette(&RainbowColors, 0, 16)
+	for i, c := range s {
+		if c != RainbowColors[i] {
+			t.Errorf("LED %d: expected %v, got %v", i, RainbowColors[i], c)
+		}
+	}
+}
+
+func TestFadeAll(t *testing.T) {
+	s := Strip{{R: 255, G: 128, B: 1}}
+	s.FadeAll(0)
+	if s[0] != (color.RGBA{R: 255, G: 128, B: 1}) {
+		t.Errorf("fade by 0: expected no change, got %v", s[0])
+	}
+	s.FadeAll(128)
+	if s[0] != (color.RGBA{R: 127, G: 64, B: 0}) {
+		t.Errorf("fade by 128: unexpected color %v", s[0])
+	}
+	s.FadeAll(255)
+	if s[0] != (color.RGBA{}) {
+		t.Errorf("fade by 255: expected black, got %v", s[0])
+	}
+}
+
+func TestScaleStrip(t *testing.T) {
+	var src Strip
+	for i := 0; i < 256; i++ {
+		src = append(src, color.RGBA{R: uint8(i), G: uint8(255 - i), B: uint8(i * 7), A: uint8(i * 3)})
+	}
+	dst := make(Strip, len(src)+1)
+	for scale := 0; scale < 256; scale++ {
+		scaleStrip(dst, src, uint8(scale))
+		for i, c := range src {
+			if expected := scaleRGBA(c, uint8(scale)); dst[i] != expected {
+				t.Fatalf("scale %d: %v: expected %v, got %v", scale, c, expected, dst[i])
+			}
+		}
+		if dst[len(src)] != (color.RGBA{}) {
+			t.Fatalf("scale %d: wrote beyond the end of src", scale)
+		}
+	}
+}
+
+func TestBlend(t *testing.T) {
+	for _, tc := range []struct {
+		a, b   color.RGBA
+		frac   uint8
+		result color.RGBA
+	}{
+		{color.RGBA{R: 255}, color.RGBA{B: 255}, 0, color.RGBA{R: 255}},
+		{color.RGBA{R: 255}, color.RGBA{B: 255}, 128, color.RGBA{R: 127, B: 128}},
+		{color.RGBA{R: 255}, color.RGBA{B: 255}, 255, color.RGBA{R: 1, B: 254}},
+		{color.RGBA{10, 20, 30, 0}, color.RGBA{10, 20, 30, 0}, 77, color.RGBA{10, 20, 30, 0}},
+		{color.RGBA{0, 0, 0, 0}, color.RGBA{100, 200, 40, 255}, 64, color.RGBA{25, 50, 10, 64}},
+	} {
+		if result := Blend(tc.a, tc.b, tc.frac); result != tc.result {
+			t.Errorf("Blend(%v, %v, %d): expected %v, got %v", tc.a, tc.b, tc.frac, tc.result, result)
+		}
+	}
+}
+
+func TestDrawDot(t *testing.T) {
+	c := color.RGBA{R: 200, G: 100}
+	for _, tc := range []struct {
+		pos      int32
+		expected Strip
+	}{
+		{1 << 8, Strip{{}, {R: 200, G: 100}, {}, {}}},                 // exactly on a LED
+		{1<<8 + 64, Strip{{}, {R: 150, G: 75}, {R: 50, G: 25}, {}}},   // a quarter of the way
+		{1<<8 + 128, Strip{{}, {R: 100, G: 50}, {R: 100, G: 50}, {}}}, // halfway
+		{2<<8 + 255, Strip{{}, {}, {}, {R: 199, G: 99}}},              // almost on the next LED
+		{3<<8 + 128, Strip{{}, {}, {}, {R: 100, G: 50}}},              // half past the end
+		{4 << 8, Strip{{}, {}, {}, {}}},                               // past the end
+		{-128, Strip{{R: 100, G: 50}, {}, {}, {}}},                    // half before the start
+		{-1 << 8, Strip{{}, {}, {}, {}}},                              // before the start
+		{-3<<8 + 64, Strip{{}, {}, {}, {}}},                           // far before the start
+	} {
+		s := make(Strip, 4)
+		s.DrawDot(tc.pos, c)
+		for i := range s {
+			if s[i] != tc.expected[i] {
+				t.Errorf("DrawDot(%#x): LED %d: expected %v, got %v", tc.pos, i, tc.expected[i], s[i])
+			}
+		}
+	}
+
+	// The dot is added to the existing colors.
+	s := Strip{{R: 100, G: 5}}
+	s.DrawDot(0, c)
+	if s[0] != (color.RGBA{R: 255, G: 105}) {
+		t.Errorf("expected the colors to be added, got %v", s[0])
+	}
+}
@@ -0,0 +1,124 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+// Band is a single band of a Stripes pattern.
+type Band struct {
+	Color color.RGBA
+
+	// Weight is the size of the band relative to the other bands. A weight of
+	// zero counts as one, so that bands without a weight all have the same
+	// size.
+	Weight uint8
+}
+
+// Stripes is an Effect that shows a pattern of bands, such as the flag of a
+// country or the colors of a sports team. The bands can optionally wave, as if
+// the flag is blowing in the wind.
+type Stripes struct {
+	Bands []Band
+
+	// Vertical makes the bands run from left to right on a canvas, like the
+	// flag of France, instead of from top to bottom, like the flag of Germany.
+	// It isn't used on a strip, where the bands always run from the first to
+	// the last LED.
+	Vertical bool
+
+	// Wave is the amount of waving, where 0 is a flat flag. The edges between
+	// the bands move by up to an eighth of the size of the flag and the bands
+	// get darker in the folds of the flag.
+	Wave uint8
+}
+
+// NewStripes returns a new Stripes pattern with bands of the same size in the
+// given colors.
+func NewStripes(colors ...color.RGBA) *Stripes {
+	s := &Stripes{Bands: make([]Band, len(colors))}
+	for i, c := range colors {
+		s.Bands[i].Color = c
+	}
+	return s
+}
+
+// Params implements ParamEffect.
+func (s *Stripes) Params() []Param {
+	return []Param{
+		{Name: "Wave", Min: 0, Max: 255, Default: 0, Value: &s.Wave},
+		{Name: "Vertical", Min: 0, Max: 1, Default: 0, Value: &s.Vertical},
+	}
+}
+
+// Render implements Effect. The bands are drawn along the strip.
+func (s *Stripes) Render(frame Strip, t time.Duration) {
+	wave := newStripesWave(t)
+	for i := range frame {
+		frame[i] = s.color(i, uint32(i*2+1)<<15/uint32(len(frame)), &wave)
+	}
+}
+
+// RenderCanvas draws the bands on the canvas.
+func (s *Stripes) RenderCanvas(c *Canvas, t time.Duration) {
+	wave := newStripesWave(t)
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			pos := uint32(y*2+1) << 15 / uint32(c.Height)
+			if s.Vertical {
+				pos = uint32(x*2+1) << 15 / uint32(c.Width)
+			}
+			c.Pix[y*c.Width+x] = s.color(x, pos, &wave)
+		}
+	}
+}
+
+// stripesTime is the time axis of the waving of Stripes: the wave moves along
+// the flag at about one fold per eight LEDs per second.
+var stripesTime = NoiseTime{Speed: 0x1000}
+
+// stripesWave is the position along the time axis of the waving for a single
+// frame, see NoiseTime.Crossfade.
+type stripesWave struct {
+	z, next int32
+	frac    uint8
+}
+
+// newStripesWave returns the position of the wave at time t.
+func newStripesWave(t time.Duration) stripesWave {
+	z, next, frac := stripesTime.Crossfade(t)
+	return stripesWave{z, next, frac}
+}
+
+// color returns the color at the position across the bands (as a .16 fraction
+// of the flag), where x is the position along the direction in which the flag
+// waves.
+func (s *Stripes) color(x int, pos uint32, wave *stripesWave) color.RGBA {
+	var shade uint8 = 255
+	if s.Wave != 0 {
+		n := int32(Noise2(int32(x)<<9, wave.z)) // .15
+		if wave.frac != 0 {
+			n += (int32(Noise2(int32(x)<<9, wave.next)) - n) * int32(wave.frac) >> 8
+		}
+		pos = uint32(min(max(int32(pos)+n*int32(s.Wave)>>10, 0), 0xffff))
+		shade -= uint8((n + 32767) * int32(s.Wave) >> 18)
+	}
+
+	total := uint32(0)
+	for _, band := range s.Bands {
+		total += uint32(max(band.Weight, 1))
+	}
+	var c color.RGBA
+	end := uint32(0)
+	for _, band := range s.Bands {
+		end += uint32(max(band.Weight, 1))
+		c = band.Color
+		if pos < end<<16/total {
+			break
+		}
+	}
+	if shade != 255 {
+		c = scaleRGBA(c, shade)
+	}
+	return c
+}
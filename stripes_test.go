@@ -0,0 +1,85 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestStripes(t *testing.T) {
+	black, red, gold := color.RGBA{}, color.RGBA{R: 255}, color.RGBA{255, 204, 0, 0}
+
+	// The flag of Germany, with horizontal bands of the same size.
+	germany := NewStripes(black, red, gold)
+	c := NewCanvas(5, 6)
+	germany.RenderCanvas(c, 0)
+	for y, want := range []color.RGBA{black, black, red, red, gold, gold} {
+		for x := 0; x < c.Width; x++ {
+			if got := c.RGBAAt(x, y); got != want {
+				t.Errorf("germany (%d, %d): expected %v, got %v", x, y, want, got)
+			}
+		}
+	}
+
+	// The same bands from left to right.
+	germany.Vertical = true
+	germany.RenderCanvas(c, 0)
+	if c.RGBAAt(0, 3) != black || c.RGBAAt(2, 3) != red || c.RGBAAt(4, 3) != gold {
+		t.Errorf("vertical: unexpected row %v", c.Pix[15:20])
+	}
+
+	// Weights change the size of the bands.
+	s := &Stripes{Bands: []Band{{Color: red, Weight: 3}, {Color: gold}}}
+	frame := make(Strip, 8)
+	s.Render(frame, 0)
+	for i, want := range []color.RGBA{red, red, red, red, red, red, gold, gold} {
+		if frame[i] != want {
+			t.Errorf("weights: expected %v at %d, got %v", want, i, frame)
+			break
+		}
+	}
+
+	// Waving moves the edge and darkens the folds, but keeps the colors.
+	s.Wave = 255
+	moved := false
+	for ts := time.Duration(0); ts < 5*time.Second; ts += 100 * time.Millisecond {
+		s.Render(frame, ts)
+		if frame[5].G != 0 {
+			moved = true
+		}
+		for _, c := range frame {
+			if c.B != 0 || c.R < 191 && c.R != 0 {
+				t.Fatalf("wave: unexpected color %v", c)
+			}
+		}
+	}
+	if !moved {
+		t.Error("wave: expected the edge between the bands to move")
+	}
+
+	(&Stripes{}).Render(frame, 0)
+	if !isBlack(frame) {
+		t.Errorf("no bands: expected black, got %v", frame)
+	}
+}
+
+func TestStripesLongUptime(t *testing.T) {
+	// The wave keeps moving smoothly after a long uptime, including when its
+	// time axis wraps around.
+	s := &Stripes{Bands: []Band{{Color: color.RGBA{R: 255}}, {Color: color.RGBA{B: 255}}}, Wave: 255}
+	frame := make(Strip, 32)
+	prev := make(Strip, len(frame))
+	wrap := 0x10000 * time.Second // period of stripesTime
+	for _, start := range []time.Duration{7 * 24 * time.Hour, wrap - time.Second, 3*wrap - time.Second} {
+		s.Render(prev, start)
+		for ts := start; ts < start+2*time.Second; ts += 20 * time.Millisecond {
+			s.Render(frame, ts)
+			for i := range frame {
+				if d := abs(int(frame[i].R) - int(prev[i].R)); d > 64 && frame[i].R != 0 && prev[i].R != 0 {
+					t.Fatalf("%v: the wave jumped by %d at LED %d", ts, d, i)
+				}
+			}
+			copy(prev, frame)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+func init() {
+	RegisterEffect(EffectInfo{
+		Name:        "Strobe",
+		Description: "White flashes at a safe rate",
+		Category:    "flash",
+		New: func() Effect {
+			return &Strobe{Color: color.RGBA{255, 255, 255, 0}, Interval: time.Second}
+		},
+	})
+}
+
+// StrobeMinInterval is the shortest time between two flashes of a Strobe,
+// unless it is deliberately overridden with AllowUnsafeRate. It limits the
+// strobe to three flashes per second: flashing lights between about 3 and 60
+// flashes per second can trigger seizures in people with photosensitive
+// epilepsy, which is why accessibility guidelines such as WCAG 2.3.1 and the
+// broadcast guidelines based on the same research limit flashes to three per
+// second.
+const StrobeMinInterval = time.Second / 3
+
+// Strobe is an Effect that flashes the whole strip at a regular interval,
+// like a strobe light. The rate is limited to StrobeMinInterval by default, so
+// that a party mode can't accidentally flash at a dangerous rate.
+type Strobe struct {
+	// Color is the color of the flashes.
+	Color color.RGBA
+
+	// Interval is the time between the start of two flashes. It is limited to
+	// StrobeMinInterval, unless AllowUnsafeRate is set.
+	Interval time.Duration
+
+	// Flash is the duration of a single flash. If it is zero, flashes are
+	// 50ms. It is never longer than half the interval.
+	Flash time.Duration
+
+	// AllowUnsafeRate removes the limit on the interval. Only set it when
+	// everybody who can see the LEDs knows about and accepts the risk, for
+	// example with a warning at the entrance of a venue.
+	AllowUnsafeRate bool
+
+	lastFlash time.Duration
+	started   bool
+}
+
+// interval returns the interval between two flashes, limited to a safe rate
+// unless that is overridden.
+func (s *Strobe) interval() time.Duration {
+	if s.AllowUnsafeRate {
+		return max(s.Interval, time.Millisecond)
+	}
+	return max(s.Interval, StrobeMinInterval)
+}
+
+// SetStandardParams implements StandardEffect. The speed sets the interval,
+// from two seconds at speed 0 through one second at speed 128 to 670ms at
+// speed 255. The rate is still limited as described for Interval.
+func (s *Strobe) SetStandardParams(params StandardParams) {
+	s.Interval = 256 * time.Second / (time.Duration(params.Speed) + 128)
+}
+
+// Render implements Effect. A new flash starts at the first call to Render and
+// whenever the interval has passed since the previous flash, so that changing
+// the interval never results in two flashes closer together than allowed.
+func (s *Strobe) Render(frame Strip, t time.Duration) {
+	interval := s.interval()
+	if !s.started || t-s.lastFlash >= interval || t < s.lastFlash {
+		// Don't try to catch up on missed flashes, and start counting from
+		// the last flash that should have happened.
+		if s.started && t > s.lastFlash {
+			s.lastFlash = t - (t-s.lastFlash)%interval
+		} else {
+			s.lastFlash = t
+		}
+		s.started = true
+	}
+	flash := s.Flash
+	if flash <= 0 {
+		flash = 50 * time.Millisecond
+	}
+	if t-s.lastFlash < min(flash, interval/2) {
+		frame.FillSolid(s.Color)
+	} else {
+		frame.FillSolid(color.RGBA{})
+	}
+}
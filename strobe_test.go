@@ -0,0 +1,58 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+// countFlashes renders the effect at 100 frames per second for the given time
+// and returns the number of flashes.
+func countFlashes(e Effect, frame Strip, start, duration time.Duration) int {
+	flashes := 0
+	lit := false
+	for t := start; t < start+duration; t += 10 * time.Millisecond {
+		e.Render(frame, t)
+		if !isBlack(frame) && !lit {
+			flashes++
+		}
+		lit = !isBlack(frame)
+	}
+	return flashes
+}
+
+func TestStrobe(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 0}
+	frame := make(Strip, 4)
+	s := &Strobe{Color: white, Interval: 500 * time.Millisecond}
+	s.Render(frame, 0)
+	if frame[0] != white || frame[3] != white {
+		t.Errorf("expected a flash at the start, got %v", frame)
+	}
+	s.Render(frame, 60*time.Millisecond)
+	if !isBlack(frame) {
+		t.Errorf("expected the flash to end after 50ms, got %v", frame)
+	}
+	if n := countFlashes(s, frame, 0, 10*time.Second); n != 20 {
+		t.Errorf("500ms: expected 20 flashes, got %d", n)
+	}
+
+	// The rate is limited to three flashes a second, also when the interval
+	// changes in the middle of the sequence.
+	s = &Strobe{Color: white, Interval: 50 * time.Millisecond}
+	if n := countFlashes(s, frame, 0, 10*time.Second); n != 30 {
+		t.Errorf("50ms: expected 30 flashes, got %d", n)
+	}
+	s.Interval = time.Second
+	countFlashes(s, frame, 10*time.Second, 250*time.Millisecond)
+	s.Interval = 0
+	if n := countFlashes(s, frame, 10250*time.Millisecond, 10*time.Second); n > 30 {
+		t.Errorf("after a change: expected at most 30 flashes, got %d", n)
+	}
+
+	// Unless the limit is deliberately removed.
+	s = &Strobe{Color: white, Interval: 100 * time.Millisecond, Flash: 20 * time.Millisecond, AllowUnsafeRate: true}
+	if n := countFlashes(s, frame, 0, 10*time.Second); n != 100 {
+		t.Errorf("unsafe: expected 100 flashes, got %d", n)
+	}
+}
@@ -0,0 +1,94 @@
+// Package terminal implements a Displayer that shows frames in a terminal
+// using 24-bit ANSI colors. This makes it possible to develop and debug effects
+// without any hardware, even over SSH.
+package terminal
+
+import (
+	"image/color"
+	"io"
+	"strconv"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Displayer is a ledsgo.Displayer that draws frames on a terminal. Each frame
+// is drawn over the previous one. The terminal must support 24-bit colors,
+// which almost all modern terminals do.
+type Displayer struct {
+	W io.Writer
+
+	// Width is the width of the matrix in LEDs. The frame is drawn as a matrix
+	// row by row, using one character for two rows of LEDs. If Width is 0,
+	// the frame is drawn as a strip on a single line.
+	Width int
+
+	buf   []byte
+	lines int // number of lines drawn in the previous frame
+}
+
+// NewDisplayer returns a new terminal displayer. Use a width of 0 for a strip.
+func NewDisplayer(w io.Writer, width int) *Displayer {
+	return &Displayer{W: w, Width: width}
+}
+
+// Display draws the frame.
+func (d *Displayer) Display(frame ledsgo.Strip) error {
+	buf := d.buf[:0]
+	if d.lines != 0 {
+		// Move the cursor back to the start of the previous frame.
+		buf = append(buf, "\x1b["...)
+		buf = strconv.AppendInt(buf, int64(d.lines), 10)
+		buf = append(buf, "A\r"...)
+	}
+	d.lines = 0
+	if d.Width <= 0 {
+		// Draw a strip, with two characters per LED so that they're roughly
+		// square.
+		for _, c := range frame {
+			buf = appendColor(buf, 48, c)
+			buf = append(buf, "  "...)
+		}
+		buf = append(buf, "\x1b[0m\n"...)
+		d.lines++
+	} else {
+		// Draw a matrix, using the upper half block character with the
+		// foreground color for the upper row and the background color for
+		// the lower row.
+		height := (len(frame) + d.Width - 1) / d.Width
+		for y := 0; y < height; y += 2 {
+			for x := 0; x < d.Width; x++ {
+				buf = appendColor(buf, 38, pixel(frame, y*d.Width+x))
+				buf = appendColor(buf, 48, pixel(frame, (y+1)*d.Width+x))
+				buf = append(buf, "▀"...)
+			}
+			buf = append(buf, "\x1b[0m\n"...)
+			d.lines++
+		}
+	}
+	d.buf = buf
+	_, err := d.W.Write(buf)
+	return err
+}
+
+// pixel returns the color at the given index, or black if the index is outside
+// of the frame.
+func pixel(frame ledsgo.Strip, i int) color.RGBA {
+	if i >= len(frame) {
+		return color.RGBA{}
+	}
+	return frame[i]
+}
+
+// appendColor appends the escape sequence to set the foreground (38) or
+// background (48) color.
+func appendColor(buf []byte, code int, c color.RGBA) []byte {
+	buf = append(buf, "\x1b["...)
+	buf = strconv.AppendInt(buf, int64(code), 10)
+	buf = append(buf, ";2;"...)
+	buf = strconv.AppendUint(buf, uint64(c.R), 10)
+	buf = append(buf, ';')
+	buf = strconv.AppendUint(buf, uint64(c.G), 10)
+	buf = append(buf, ';')
+	buf = strconv.AppendUint(buf, uint64(c.B), 10)
+	return append(buf, 'm')
+}
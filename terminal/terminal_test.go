@@ -0,0 +1,60 @@
+package terminal
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/aykevl/ledsgo"
+)
+
+func TestDisplayStrip(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewDisplayer(&buf, 0)
+	frame := ledsgo.Strip{{R: 255}, {G: 128, B: 1}}
+	if err := d.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	expected := "\x1b[48;2;255;0;0m  \x1b[48;2;0;128;1m  \x1b[0m\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("first frame: expected %q, got %q", expected, got)
+	}
+
+	// The next frame is drawn over the previous one.
+	buf.Reset()
+	frame[0] = color.RGBA{1, 2, 3, 0}
+	if err := d.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	expected = "\x1b[1A\r\x1b[48;2;1;2;3m  \x1b[48;2;0;128;1m  \x1b[0m\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("second frame: expected %q, got %q", expected, got)
+	}
+}
+
+func TestDisplayMatrix(t *testing.T) {
+	// A 2x3 matrix takes two lines, where the bottom half of the last line is
+	// black.
+	var buf bytes.Buffer
+	d := NewDisplayer(&buf, 2)
+	frame := ledsgo.Strip{
+		{R: 1}, {R: 2},
+		{G: 3}, {G: 4},
+		{B: 5}, {B: 6},
+	}
+	if err := d.Display(frame); err != nil {
+		t.Fatal(err)
+	}
+	expected := "" +
+		"\x1b[38;2;1;0;0m\x1b[48;2;0;3;0m▀\x1b[38;2;2;0;0m\x1b[48;2;0;4;0m▀\x1b[0m\n" +
+		"\x1b[38;2;0;0;5m\x1b[48;2;0;0;0m▀\x1b[38;2;0;0;6m\x1b[48;2;0;0;0m▀\x1b[0m\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	buf.Reset()
+	d.Display(frame)
+	if got := buf.String(); got != "\x1b[2A\r"+expected {
+		t.Errorf("second frame: expected to move up two lines, got %q", got)
+	}
+}
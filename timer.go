@@ -0,0 +1,66 @@
+package ledsgo
+
+import (
+	"math/bits"
+	"time"
+)
+
+// EveryN helps with doing periodic work inside an animation loop, such as
+// changing the palette or spawning a particle. It fires at most once per
+// interval and keeps track of when it last fired.
+//
+// The zero value is not useful: set the Interval first. The time passed to its
+// methods should come from a monotonic time source, for example the time since
+// the program started.
+type EveryN struct {
+	Interval time.Duration
+	last     time.Duration
+	started  bool
+}
+
+// Ready returns true if at least Interval has passed since the last time it
+// returned true. The first call always returns true. If Ready isn't called for
+// a long time, it fires only once instead of trying to catch up.
+func (e *EveryN) Ready(now time.Duration) bool {
+	if e.started && now-e.last < e.Interval {
+		return false
+	}
+	e.started = true
+	e.last = now
+	return true
+}
+
+// Reset restarts the interval, so that Ready will next return true when the
+// interval has passed after now.
+func (e *EveryN) Reset(now time.Duration) {
+	e.started = true
+	e.last = now
+}
+
+// frameTimer keeps track of the time between two frames of an effect that
+// moves things at a fixed speed.
+type frameTimer struct {
+	last    time.Duration
+	started bool
+}
+
+// step returns the time since the previous frame, which is zero for the first
+// frame or when the animation time jumps back.
+func (c *frameTimer) step(t time.Duration) time.Duration {
+	dt := t - c.last
+	if !c.started || dt < 0 {
+		dt = 0
+	}
+	c.last = t
+	c.started = true
+	return dt
+}
+
+// durationFraction returns part/whole as a .16 fixed-point fraction, for
+// 0 <= part < whole. It is exact for any duration, where shifting part left by
+// 16 bits first would overflow for durations of more than a few days.
+func durationFraction(part, whole time.Duration) uint16 {
+	hi, lo := bits.Mul64(uint64(part), 1<<16)
+	frac, _ := bits.Div64(hi, lo, uint64(whole))
+	return uint16(frac)
+}
@@ -0,0 +1,69 @@
+package ledsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEveryN(t *testing.T) {
+	e := EveryN{Interval: 100 * time.Millisecond}
+	for _, tc := range []struct {
+		now   time.Duration
+		ready bool
+	}{
+		{5 * time.Second, true}, // the first call always fires
+		{5*time.Second + 50*time.Millisecond, false},
+		{5*time.Second + 99*time.Millisecond, false},
+		{5*time.Second + 100*time.Millisecond, true},
+		{5*time.Second + 150*time.Millisecond, false},
+		{5*time.Second + 250*time.Millisecond, true},
+		{10 * time.Second, true}, // doesn't catch up after a long pause
+		{10*time.Second + 10*time.Millisecond, false},
+	} {
+		if ready := e.Ready(tc.now); ready != tc.ready {
+			t.Errorf("Ready(%v): expected %v, got %v", tc.now, tc.ready, ready)
+		}
+	}
+}
+
+func TestEveryNReset(t *testing.T) {
+	e := EveryN{Interval: time.Second}
+	if !e.Ready(0) {
+		t.Fatal("first call to Ready didn't fire")
+	}
+	e.Reset(500 * time.Millisecond)
+	if e.Ready(time.Second) {
+		t.Error("Ready fired before the interval passed after Reset")
+	}
+	if !e.Ready(1500 * time.Millisecond) {
+		t.Error("Ready didn't fire an interval after Reset")
+	}
+
+	// A Reset before the first call makes Ready wait for the interval.
+	e = EveryN{Interval: time.Second}
+	e.Reset(0)
+	if e.Ready(0) {
+		t.Error("Ready fired right after Reset")
+	}
+}
+
+func TestEveryNBackwards(t *testing.T) {
+	// When the time goes back, for example because the animation was
+	// restarted, Ready doesn't fire until the time has caught up again.
+	e := EveryN{Interval: time.Second}
+	e.Ready(time.Hour)
+	for now := time.Duration(0); now < 10*time.Second; now += 100 * time.Millisecond {
+		if e.Ready(now) {
+			t.Fatalf("Ready(%v) fired after the time went back", now)
+		}
+	}
+
+	// Reset starts the interval from the new time.
+	e.Reset(10 * time.Second)
+	if e.Ready(10*time.Second + 500*time.Millisecond) {
+		t.Error("Ready fired before the interval passed after Reset")
+	}
+	if !e.Ready(11 * time.Second) {
+		t.Error("Ready didn't fire an interval after Reset")
+	}
+}
@@ -0,0 +1,97 @@
+package ledsgo
+
+// Quarter of a sine wave, from 0 to 90 degrees in 64 steps (and the endpoint).
+// Values in between are linearly interpolated, which gives an error of at most
+// a few units in the last place.
+var sinTable = [65]int16{
+	0, 804, 1608, 2410, 3212, 4011, 4808, 5602,
+	6393, 7179, 7962, 8739, 9512, 10278, 11039, 11793,
+	12539, 13279, 14010, 14732, 15446, 16151, 16846, 17530,
+	18204, 18868, 19519, 20159, 20787, 21403, 22005, 22594,
+	23170, 23731, 24279, 24811, 25329, 25832, 26319, 26790,
+	27245, 27683, 28105, 28510, 28898, 29268, 29621, 29956,
+	30273, 30571, 30852, 31113, 31356, 31580, 31785, 31971,
+	32137, 32285, 32412, 32521, 32609, 32678, 32728, 32757,
+	32767,
+}
+
+// Sin16 returns the sine of the given angle, where a full circle is 0x10000.
+// The result is a 0.15 fixed-point value in the range -32767..32767.
+//
+// It uses a small lookup table with linear interpolation, avoiding floating
+// point math entirely.
+func Sin16(theta uint16) int16 {
+	x := theta & 0x3fff // position within the quadrant
+	if theta&0x4000 != 0 {
+		x = 0x4000 - x // second half of each half wave is mirrored
+	}
+	i := x >> 8
+	frac := int32(x & 0xff)
+	y := int32(sinTable[i])
+	if frac != 0 {
+		y += ((int32(sinTable[i+1])-y)*frac + 0x80) >> 8
+	}
+	if theta&0x8000 != 0 {
+		y = -y
+	}
+	return int16(y)
+}
+
+// Cos16 returns the cosine of the given angle, where a full circle is 0x10000.
+// See Sin16 for details.
+func Cos16(theta uint16) int16 {
+	return Sin16(theta + 0x4000)
+}
+
+// Arctangent from 0 to 1 (0 to 45 degrees) in 32 steps (and the endpoint), as
+// 16-bit angles. Values in between are linearly interpolated.
+var atanTable = [33]uint16{
+	0, 326, 651, 975, 1297, 1617, 1933, 2246,
+	2555, 2860, 3159, 3453, 3742, 4025, 4302, 4572,
+	4836, 5094, 5344, 5589, 5826, 6058, 6282, 6500,
+	6712, 6917, 7117, 7310, 7498, 7679, 7856, 8026,
+	8192,
+}
+
+// Atan2 returns the angle of the point (x, y) as seen from the origin, where a
+// full circle is 0x10000 and the angle increases counter-clockwise starting at
+// the positive X axis (like math.Atan2). This can be used to map positions on a
+// matrix to hues for radial and spiral effects. Atan2(0, 0) returns 0.
+func Atan2(y, x int32) uint16 {
+	ax := int64(x)
+	ay := int64(y)
+	if ax < 0 {
+		ax = -ax
+	}
+	if ay < 0 {
+		ay = -ay
+	}
+	if ax == 0 && ay == 0 {
+		return 0
+	}
+
+	// Reduce to the first octant, where the ratio between both is in 0..1.
+	swapped := ay > ax
+	if swapped {
+		ax, ay = ay, ax
+	}
+	t := uint32(ay << 16 / ax) // .16
+	i := t >> 11
+	frac := t & 0x7ff
+	angle := uint32(atanTable[i])
+	if frac != 0 {
+		angle += ((uint32(atanTable[i+1])-angle)*frac + 0x400) >> 11
+	}
+
+	// Move the angle to the right octant.
+	if swapped {
+		angle = 0x4000 - angle
+	}
+	if x < 0 {
+		angle = 0x8000 - angle
+	}
+	if y < 0 {
+		angle = 0x10000 - angle
+	}
+	return uint16(angle)
+}
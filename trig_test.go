@@ -0,0 +1,60 @@
+package ledsgo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSin16(t *testing.T) {
+	maxDiff := 0.0
+	for i := 0; i < 0x10000; i++ {
+		expected := math.Sin(float64(i)/0x10000*2*math.Pi) * 32767
+		diff := math.Abs(float64(Sin16(uint16(i))) - expected)
+		if diff > maxDiff {
+			maxDiff = diff
+		}
+		if cos := math.Cos(float64(i)/0x10000*2*math.Pi) * 32767; math.Abs(float64(Cos16(uint16(i)))-cos) > 4 {
+			t.Errorf("Cos16(%#04x): expected %.1f, got %d", i, cos, Cos16(uint16(i)))
+		}
+	}
+	t.Logf("max diff: %.2f", maxDiff)
+	if maxDiff > 4 {
+		t.Errorf("max diff is too high: %.2f", maxDiff)
+	}
+	for _, tc := range []struct {
+		theta uint16
+		sin   int16
+	}{
+		{0x0000, 0},
+		{0x4000, 32767},
+		{0x8000, 0},
+		{0xc000, -32767},
+	} {
+		if sin := Sin16(tc.theta); sin != tc.sin {
+			t.Errorf("Sin16(%#04x): expected %d, got %d", tc.theta, tc.sin, sin)
+		}
+	}
+}
+
+func TestAtan2(t *testing.T) {
+	maxDiff := 0.0
+	for _, r := range []int32{1000, 1 << 20, 1<<31 - 1} {
+		for i := 0; i < 0x10000; i += 0x10 {
+			a := float64(i) / 0x10000 * 2 * math.Pi
+			x := int32(math.Round(math.Cos(a) * float64(r)))
+			y := int32(math.Round(math.Sin(a) * float64(r)))
+			expected := math.Atan2(float64(y), float64(x)) / (2 * math.Pi) * 0x10000
+			diff := math.Abs(float64(int16(Atan2(y, x) - uint16(int32(math.Round(expected))))))
+			if diff > maxDiff {
+				maxDiff = diff
+			}
+			if diff > 3 {
+				t.Errorf("Atan2(%d, %d): expected %.1f, got %d", y, x, expected, Atan2(y, x))
+			}
+		}
+	}
+	t.Logf("max diff: %.0f", maxDiff)
+	if Atan2(0, 0) != 0 {
+		t.Errorf("Atan2(0, 0): expected 0, got %d", Atan2(0, 0))
+	}
+}
@@ -0,0 +1,58 @@
+package ledsgo
+
+// This file implements periodic waveforms, similar to those in FastLED. The
+// input is a phase where the full range of the integer is one period, so that
+// the phase wraps around for free (it is commonly the output of Beat8 or
+// Beat16). The output covers the full range of the integer.
+
+// Triwave8 returns a triangle wave: it rises from 0 to 254 in the first half of
+// the period and falls back in the second half.
+func Triwave8(in uint8) uint8 {
+	if in&0x80 != 0 {
+		in = 0xff - in
+	}
+	return in << 1
+}
+
+// Triwave16 returns a triangle wave: it rises from 0 to 65534 in the first half
+// of the period and falls back in the second half.
+func Triwave16(in uint16) uint16 {
+	if in&0x8000 != 0 {
+		in = 0xffff - in
+	}
+	return in << 1
+}
+
+// Quadwave8 returns a triangle wave with quadratic easing, which looks a lot
+// like a sine wave but is cheaper to calculate.
+func Quadwave8(in uint8) uint8 {
+	return EaseInOutQuad8(Triwave8(in))
+}
+
+// Quadwave16 returns a triangle wave with quadratic easing, which looks a lot
+// like a sine wave but is cheaper to calculate.
+func Quadwave16(in uint16) uint16 {
+	return EaseInOutQuad16(Triwave16(in))
+}
+
+// Cubicwave8 returns a triangle wave with cubic easing. It spends more time
+// near the minimum and maximum than Quadwave8.
+func Cubicwave8(in uint8) uint8 {
+	return EaseInOutCubic8(Triwave8(in))
+}
+
+// Cubicwave16 returns a triangle wave with cubic easing. It spends more time
+// near the minimum and maximum than Quadwave16.
+func Cubicwave16(in uint16) uint16 {
+	return EaseInOutCubic16(Triwave16(in))
+}
+
+// Squarewave8 returns a square wave: 255 for the first part of the period and 0
+// for the rest. The pulse width is the length of the first part, where 128
+// gives a 50% duty cycle. A pulse width of 255 always returns 255.
+func Squarewave8(in, pulseWidth uint8) uint8 {
+	if in < pulseWidth || pulseWidth == 0xff {
+		return 0xff
+	}
+	return 0
+}
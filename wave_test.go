@@ -0,0 +1,89 @@
+package ledsgo
+
+import (
+	"testing"
+)
+
+func TestWaves8(t *testing.T) {
+	for _, wave := range []struct {
+		name string
+		fn   func(uint8) uint8
+	}{
+		{"Triwave8", Triwave8},
+		{"Quadwave8", Quadwave8},
+		{"Cubicwave8", Cubicwave8},
+	} {
+		// The waves start at the bottom, are at the top halfway through the
+		// period, and rise and fall symmetrically.
+		if result := wave.fn(0); result != 0 {
+			t.Errorf("%s(0): expected 0, got %d", wave.name, result)
+		}
+		if result := wave.fn(0x7f); result < 0xfc {
+			t.Errorf("%s(0x7f): expected the top, got %d", wave.name, result)
+		}
+		for x := 1; x < 0x80; x++ {
+			if wave.fn(uint8(x)) < wave.fn(uint8(x-1)) {
+				t.Errorf("%s: falls at %d in the first half", wave.name, x)
+				break
+			}
+		}
+		for x := 0; x < 0x80; x++ {
+			if up, down := wave.fn(uint8(x)), wave.fn(uint8(0xff-x)); up != down {
+				t.Errorf("%s: not symmetric at %d: %d != %d", wave.name, x, up, down)
+				break
+			}
+		}
+	}
+}
+
+func TestWaves16(t *testing.T) {
+	for _, wave := range []struct {
+		name string
+		fn   func(uint16) uint16
+	}{
+		{"Triwave16", Triwave16},
+		{"Quadwave16", Quadwave16},
+		{"Cubicwave16", Cubicwave16},
+	} {
+		if result := wave.fn(0); result != 0 {
+			t.Errorf("%s(0): expected 0, got %d", wave.name, result)
+		}
+		if result := wave.fn(0x7fff); result < 0xfffc {
+			t.Errorf("%s(0x7fff): expected the top, got %d", wave.name, result)
+		}
+		for x := 1; x < 0x8000; x++ {
+			if wave.fn(uint16(x)) < wave.fn(uint16(x-1)) {
+				t.Errorf("%s: falls at %d in the first half", wave.name, x)
+				break
+			}
+		}
+		for x := 0; x < 0x8000; x++ {
+			if up, down := wave.fn(uint16(x)), wave.fn(uint16(0xffff-x)); up != down {
+				t.Errorf("%s: not symmetric at %d: %d != %d", wave.name, x, up, down)
+				break
+			}
+		}
+	}
+}
+
+func TestSquarewave8(t *testing.T) {
+	for _, tc := range []struct {
+		in, pulseWidth, result uint8
+	}{
+		{0, 128, 255},
+		{127, 128, 255},
+		{128, 128, 0},
+		{255, 128, 0},
+		{0, 0, 0},
+		{255, 0, 0},
+		{0, 255, 255},
+		{254, 255, 255},
+		{255, 255, 255},
+		{9, 10, 255},
+		{10, 10, 0},
+	} {
+		if result := Squarewave8(tc.in, tc.pulseWidth); result != tc.result {
+			t.Errorf("Squarewave8(%d, %d): expected %d, got %d", tc.in, tc.pulseWidth, tc.result, result)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package ledsgo
+
+import (
+	"image/color"
+	"time"
+)
+
+func init() {
+	RegisterEffect(EffectInfo{
+		Name:        "Rain",
+		Description: "Raindrops falling down the strip",
+		Category:    "weather",
+		New:         func() Effect { return &Rain{Intensity: 128} },
+	})
+	RegisterEffect(EffectInfo{
+		Name:        "Snow",
+		Description: "Snowflakes that pile up at the end of the strip",
+		Category:    "weather",
+		New:         func() Effect { return &Snow{Intensity: 128} },
+	})
+	RegisterEffect(EffectInfo{
+		Name:        "Sun",
+		Description: "A pulsing sun in the middle of the strip",
+		Category:    "weather",
+		New:         func() Effect { return &Sun{Intensity: 128} },
+	})
+}
+
+// The weather effects in this file show rain, snow and sunshine on a strip
+// that hangs down from its first LED, so that rain and snow fall towards the
+// last LED. They all have an intensity, so that a weather station can show for
+// example a light drizzle or a heavy shower depending on the forecast.
+
+// weatherMaxParticles is the maximum number of raindrops or snowflakes that
+// are shown at the same time.
+const weatherMaxParticles = 64
+
+// weatherSpawn adds the number of particles that should be spawned in dt to
+// the .16 fixed-point counter and returns the number of whole particles,
+// where perSecond is the rate at the highest intensity. The rate is varied
+// randomly so that the particles don't arrive at regular intervals.
+func weatherSpawn(counter *uint32, r *Rand, intensity uint8, perSecond uint32, dt time.Duration) int {
+	step := uint64(dt<<16/time.Second) * uint64(intensity) * uint64(perSecond) / 255 // .16
+	*counter += uint32(step * uint64(r.Uint8()) >> 7)
+	n := int(*counter >> 16)
+	*counter &= 0xffff
+	return n
+}
+
+// Rain is an Effect that shows raindrops falling down the strip as short
+// streaks. The zero value is a usable effect without rain: set the Intensity
+// to make it rain.
+type Rain struct {
+	// Intensity is the amount of rain, from a single drop now and then to a
+	// heavy shower at 255.
+	Intensity uint8
+
+	// Color is the color of the raindrops. If it is black, the drops are
+	// light blue.
+	Color color.RGBA
+
+	clock   frameTimer
+	rand    Rand
+	drops   *ParticleSystem
+	counter uint32
+}
+
+// Length of the streak of a raindrop, in LEDs.
+const rainStreak = 4
+
+// SetStandardParams implements StandardEffect. The intensity is the amount of
+// rain.
+func (r *Rain) SetStandardParams(params StandardParams) {
+	r.Intensity = params.Intensity
+}
+
+// Params implements ParamEffect.
+func (r *Rain) Params() []Param {
+	return []Param{
+		{Name: "Intensity", Min: 0, Max: 255, Default: 128, Value: &r.Intensity},
+	}
+}
+
+// Render implements Effect.
+func (r *Rain) Render(frame Strip, t time.Duration) {
+	if r.drops == nil {
+		r.drops = NewParticleSystem(weatherMaxParticles)
+	}
+	dt := r.clock.step(t)
+	r.drops.Update(dt)
+	for n := weatherSpawn(&r.counter, &r.rand, r.Intensity, 40, dt); n > 0; n-- {
+		// A drop falls at 20 to 40 LEDs per second, until its streak has
+		// left the strip.
+		speed := int64(20+r.rand.Intn(20)) << 16
+		r.drops.Spawn(Particle{
+			VX:       int32(speed),
+			Lifetime: time.Duration(int64(len(frame)+rainStreak) << 16 * int64(time.Second) / speed),
+		})
+	}
+
+	c := r.Color
+	if c == (color.RGBA{}) {
+		c = color.RGBA{60, 100, 255, 0}
+	}
+	frame.FillSolid(color.RGBA{})
+	for _, drop := range r.drops.Particles() {
+		// The streak fades out behind the drop.
+		for i := int32(0); i < rainStreak; i++ {
+			frame.DrawDot(drop.X>>8-i<<8, scaleRGBA(c, uint8(255-i*255/rainStreak)))
+		}
+	}
+}
+
+// Snow is an Effect that shows snowflakes drifting down the strip. The flakes
+// pile up at the end of the strip, and the pile slowly melts so that it only
+// grows while it keeps snowing. The zero value is a usable effect without
+// snow: set the Intensity to make it snow.
+type Snow struct {
+	// Intensity is the amount of snow, from a single flake now and then to a
+	// snowstorm at 255.
+	Intensity uint8
+
+	// Color is the color of the flakes and the pile. If it is black, the
+	// snow is white.
+	Color color.RGBA
+
+	clock   frameTimer
+	rand    Rand
+	flakes  *ParticleSystem
+	counter uint32
+	depth   int32 // depth of the pile in LEDs, .16
+}
+
+// Depth returns the depth of the pile of snow at the end of the strip, in
+// LEDs as a .16 fixed-point value.
+func (s *Snow) Depth() int32 {
+	return s.depth
+}
+
+// SetStandardParams implements StandardEffect. The intensity is the amount of
+// snow.
+func (s *Snow) SetStandardParams(params StandardParams) {
+	s.Intensity = params.Intensity
+}
+
+// Params implements ParamEffect.
+func (s *Snow) Params() []Param {
+	return []Param{
+		{Name: "Intensity", Min: 0, Max: 255, Default: 128, Value: &s.Intensity},
+	}
+}
+
+// Render implements Effect.
+func (s *Snow) Render(frame Strip, t time.Duration) {
+	if s.flakes == nil {
+		s.flakes = NewParticleSystem(weatherMaxParticles)
+	}
+	dt := s.clock.step(t)
+	s.flakes.Update(dt)
+	for n := weatherSpawn(&s.counter, &s.rand, s.Intensity, 8, dt); n > 0; n-- {
+		// Flakes fall at 3 to 6 LEDs per second. The Y position isn't used on
+		// a strip, so it is used as the phase of the drift of the flake.
+		s.flakes.Spawn(Particle{
+			Y:        int32(s.rand.Uint16()),
+			VX:       int32(3+s.rand.Intn(3)) << 16,
+			Lifetime: time.Hour, // long enough to land on the pile
+		})
+	}
+
+	// Flakes that landed add an eighth of a LED to the pile. The pile melts
+	// at 0.2 LEDs per second, and can fill at most half of the strip.
+	top := int32(len(frame))<<16 - s.depth // .16
+	flakes := s.flakes.Particles()
+	for i := range flakes {
+		if flakes[i].X >= top-1<<16 {
+			s.depth += 0x2000
+			flakes[i].Age = flakes[i].Lifetime // removed at the next update
+		}
+	}
+	step := int64(dt << 16 / time.Second) // .16 seconds
+	s.depth = int32(max(int64(s.depth)-step*0x3333>>16, 0))
+	s.depth = min(s.depth, int32(len(frame))<<15)
+
+	c := s.Color
+	if c == (color.RGBA{}) {
+		c = color.RGBA{255, 255, 255, 0}
+	}
+	frame.FillSolid(color.RGBA{})
+	for i := range flakes {
+		flake := &flakes[i]
+		if flake.Age == flake.Lifetime {
+			continue // landed
+		}
+		// Flakes drift around their position by up to half a LED, with a
+		// period of two seconds.
+		drift := int32(Sin16(uint16(flake.Y)+uint16(flake.Age*0x10000/(2*time.Second)))) >> 8 // .8
+		frame.DrawDot(flake.X>>8+drift, c)
+	}
+
+	// Draw the pile from the end of the strip, where the top LED is partly
+	// lit.
+	for i := range frame {
+		covered := min(max(s.depth>>8-int32(i)<<8, 0), 0x100) // .8
+		if covered == 0 {
+			break
+		}
+		frame[len(frame)-1-i] = addWeighted(frame[len(frame)-1-i], c, uint16(covered))
+	}
+}
+
+// Sun is an Effect that shows a glowing sun in the middle of the strip, which
+// slowly pulses. The zero value is a usable effect without sun: set the
+// Intensity to make the sun shine.
+type Sun struct {
+	// Intensity is the strength of the sunshine: the sun gets bigger and
+	// brighter with the intensity, until it fills the whole strip at 255.
+	Intensity uint8
+
+	// Color is the color of the sun. If it is black, the sun is a warm
+	// yellow.
+	Color color.RGBA
+}
+
+// SetStandardParams implements StandardEffect. The intensity is the strength
+// of the sunshine.
+func (s *Sun) SetStandardParams(params StandardParams) {
+	s.Intensity = params.Intensity
+}
+
+// Params implements ParamEffect.
+func (s *Sun) Params() []Param {
+	return []Param{
+		{Name: "Intensity", Min: 0, Max: 255, Default: 128, Value: &s.Intensity},
+	}
+}
+
+// Render implements Effect.
+func (s *Sun) Render(frame Strip, t time.Duration) {
+	c := s.Color
+	if c == (color.RGBA{}) {
+		c = color.RGBA{255, 160, 20, 0}
+	}
+
+	// Pulse between 75% and 100% of the intensity every four seconds.
+	pulse := int32(Sin16(uint16(t % (4 * time.Second) * 0x10000 / (4 * time.Second))))
+	c = scaleRGBA(c, Scale8(uint8(224+pulse*31/32767), s.Intensity))
+
+	// The sun is fully lit up to the radius, and then fades out over half
+	// the radius plus one LED.
+	radius := int32(len(frame)) * (int32(s.Intensity) + 1) >> 1 // .8
+	fade := radius/2 + 0x100
+	center := int32(len(frame)) << 7 // .8
+	for i := range frame {
+		distance := int32(i)<<8 + 0x80 - center
+		if distance < 0 {
+			distance = -distance
+		}
+		switch {
+		case distance <= radius:
+			frame[i] = c
+		case distance < radius+fade:
+			frame[i] = scaleRGBA(c, uint8((radius+fade-distance)*255/fade))
+		default:
+			frame[i] = color.RGBA{}
+		}
+	}
+}
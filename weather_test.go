@@ -0,0 +1,126 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestRain(t *testing.T) {
+	frame := make(Strip, 60)
+	r := &Rain{}
+	for ts := time.Duration(0); ts < 5*time.Second; ts += 20 * time.Millisecond {
+		r.Render(frame, ts)
+		if !isBlack(frame) {
+			t.Fatalf("expected no rain at intensity 0, got %v", frame)
+		}
+	}
+
+	// Drops start at the top and fall down.
+	r.Intensity = 255
+	lit := 0
+	for ts := 5 * time.Second; ts < 15*time.Second; ts += 20 * time.Millisecond {
+		r.Render(frame, ts)
+		for _, c := range frame {
+			if c != (color.RGBA{}) {
+				lit++
+				if c.B < c.R || c.B < c.G {
+					t.Fatalf("expected blue drops, got %v", c)
+				}
+			}
+		}
+	}
+	if lit < 500*len(frame)/20 {
+		t.Errorf("expected a heavy shower, got %d lit LEDs in 500 frames", lit)
+	}
+	if len(r.drops.Particles()) < 5 {
+		t.Errorf("expected many drops, got %d", len(r.drops.Particles()))
+	}
+	for _, drop := range r.drops.Particles() {
+		if drop.VX <= 0 {
+			t.Errorf("expected drops to fall, got speed %d", drop.VX)
+		}
+	}
+
+	// Once the rain stops, all drops leave the strip.
+	r.Intensity = 0
+	r.Render(frame, 20*time.Second)
+	r.Render(frame, 20*time.Second+20*time.Millisecond)
+	if !isBlack(frame) {
+		t.Errorf("expected the rain to stop, got %v", frame)
+	}
+
+	if n := testing.AllocsPerRun(10, func() { r.Render(frame, 30*time.Second) }); n != 0 {
+		t.Errorf("expected no allocations, got %.1f", n)
+	}
+}
+
+func TestSnow(t *testing.T) {
+	frame := make(Strip, 40)
+	s := &Snow{Intensity: 255}
+	for ts := time.Duration(0); ts < 20*time.Second; ts += 20 * time.Millisecond {
+		s.Render(frame, ts)
+	}
+
+	// The pile grows at the end of the strip.
+	depth := s.Depth()
+	if depth < 4<<16 {
+		t.Fatalf("expected a pile of snow, got a depth of %d", depth)
+	}
+	if frame[39] != (color.RGBA{255, 255, 255, 0}) || frame[38] != frame[39] {
+		t.Errorf("expected white snow at the end of the strip, got %v", frame[36:])
+	}
+	if isBlack(frame[:30]) {
+		t.Error("expected falling flakes")
+	}
+
+	// It is at most half of the strip deep, and melts once the snow stops.
+	for ts := 20 * time.Second; ts < 60*time.Second; ts += 20 * time.Millisecond {
+		s.Render(frame, ts)
+	}
+	if depth := s.Depth(); depth > 20<<16 {
+		t.Errorf("expected the pile to fill at most half the strip, got %d", depth)
+	}
+	s.Intensity = 0
+	s.Render(frame, 70*time.Second)
+	prev := s.Depth()
+	s.Render(frame, 71*time.Second)
+	if depth := s.Depth(); depth >= prev {
+		t.Errorf("expected the pile to melt, but it went from %d to %d", prev, depth)
+	}
+}
+
+func TestSun(t *testing.T) {
+	frame := make(Strip, 30)
+	s := &Sun{}
+	s.Render(frame, 0)
+	if !isBlack(frame) {
+		t.Errorf("expected no sun at intensity 0, got %v", frame)
+	}
+
+	// The sun is in the middle, and fades towards the ends.
+	s.Intensity = 128
+	s.Render(frame, time.Second)
+	if frame[14] != frame[15] || frame[10] != frame[19] || frame[0] != (color.RGBA{}) {
+		t.Errorf("expected a symmetric sun, got %v", frame)
+	}
+	if frame[14].R < 100 || frame[14].R <= frame[14].G || frame[14].G <= frame[14].B {
+		t.Errorf("expected a yellow sun, got %v", frame[14])
+	}
+	if frame[6].R == 0 || frame[6].R >= frame[14].R {
+		t.Errorf("expected the sun to fade out, got %v", frame)
+	}
+
+	// It pulses.
+	bright := frame[15]
+	s.Render(frame, 3*time.Second)
+	if frame[15].R >= bright.R || frame[15].R < bright.R*3/4 {
+		t.Errorf("expected the sun to pulse, got %v and %v", bright, frame[15])
+	}
+
+	s.Intensity = 255
+	s.Render(frame, 0)
+	if frame[0] == (color.RGBA{}) {
+		t.Errorf("expected the sun to fill the strip, got %v", frame)
+	}
+}
@@ -0,0 +1,67 @@
+package ledsgo
+
+import (
+	"image/color"
+)
+
+// WhiteBalance is a Displayer that adjusts the white point of all frames
+// before they are sent to the LEDs, for example to match the LEDs to the warm
+// ambient light of a room, or to make a new batch of LEDs look the same as a
+// reference fixture.
+//
+// The adjustment is a simplified version of the chromatic adaptation used in
+// color management (such as the Bradford transform): every channel is scaled
+// by the ratio between the target white and the source white. The Bradford
+// transform does this in a cone response space reached with a 3x3 matrix, but
+// for the narrow-band primaries of LEDs scaling the channels directly gives
+// nearly the same result at a fraction of the cost.
+type WhiteBalance struct {
+	displayer Displayer
+	buf       Strip
+	scale     [3]uint8 // red, green and blue scale, see Scale8
+}
+
+// NewWhiteBalance returns a new WhiteBalance that sends frames to the given
+// displayer, with a white point adjustment from source to target (see
+// SetWhitePoint).
+func NewWhiteBalance(displayer Displayer, source, target color.RGBA) *WhiteBalance {
+	w := &WhiteBalance{displayer: displayer}
+	w.SetWhitePoint(source, target)
+	return w
+}
+
+// SetWhitePoint changes the adjustment so that the color source is displayed
+// as target. The source is the white of the LEDs as it is now, for example as
+// measured with a camera against a reference, and the target is the white it
+// should be. The channels are never made brighter (which would clip them), so
+// the channel with the largest ratio between target and source stays the same
+// and the others are dimmed.
+func (w *WhiteBalance) SetWhitePoint(source, target color.RGBA) {
+	var ratios [3]uint64 // .16
+	for i, c := range [3][2]uint8{{source.R, target.R}, {source.G, target.G}, {source.B, target.B}} {
+		ratios[i] = uint64(c[1]) << 16 / uint64(max(c[0], 1))
+	}
+	highest := max(ratios[0], ratios[1], ratios[2])
+	for i, ratio := range ratios {
+		if highest == 0 {
+			w.scale[i] = 0 // target is black
+			continue
+		}
+		w.scale[i] = uint8((ratio*255 + highest/2) / highest)
+	}
+}
+
+// Display sends the frame to the underlying displayer with the white point
+// adjusted.
+func (w *WhiteBalance) Display(frame Strip) error {
+	if w.scale == [3]uint8{255, 255, 255} {
+		return w.displayer.Display(frame)
+	}
+	if len(w.buf) != len(frame) {
+		w.buf = make(Strip, len(frame))
+	}
+	for i, c := range frame {
+		w.buf[i] = color.RGBA{Scale8(c.R, w.scale[0]), Scale8(c.G, w.scale[1]), Scale8(c.B, w.scale[2]), c.A}
+	}
+	return w.displayer.Display(w.buf)
+}
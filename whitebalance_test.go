@@ -0,0 +1,45 @@
+package ledsgo
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestWhiteBalance(t *testing.T) {
+	rec := &frameRecorder{}
+	frame := Strip{{255, 255, 255, 0}, {100, 50, 0, 0}}
+
+	// The LEDs are too blue: dim blue, keep red.
+	w := NewWhiteBalance(rec, color.RGBA{200, 220, 255, 0}, color.RGBA{255, 255, 255, 0})
+	w.Display(frame)
+	if c := rec.frames[0][0]; c != (color.RGBA{255, 232, 200, 0}) {
+		t.Errorf("white: expected {255 232 200}, got %v", c)
+	}
+	if c := rec.frames[0][1]; c != (color.RGBA{100, 45, 0, 0}) {
+		t.Errorf("orange: expected {100 45 0}, got %v", c)
+	}
+	if frame[0].B != 255 {
+		t.Error("the original frame was modified")
+	}
+
+	// Adapt to warm white.
+	w.SetWhitePoint(color.RGBA{255, 255, 255, 0}, color.RGBA{255, 180, 100, 0})
+	w.Display(frame)
+	if c := rec.frames[1][0]; c != (color.RGBA{255, 180, 100, 0}) {
+		t.Errorf("warm white: expected {255 180 100}, got %v", c)
+	}
+
+	// No adjustment.
+	w.SetWhitePoint(color.RGBA{10, 20, 30, 0}, color.RGBA{10, 20, 30, 0})
+	w.Display(frame)
+	if c := rec.frames[2][1]; c != frame[1] {
+		t.Errorf("no adjustment: expected %v, got %v", frame[1], c)
+	}
+
+	// A black target results in black.
+	w.SetWhitePoint(color.RGBA{255, 255, 255, 0}, color.RGBA{})
+	w.Display(frame)
+	if !isBlack(rec.frames[3]) {
+		t.Errorf("black target: expected a black frame, got %v", rec.frames[3])
+	}
+}
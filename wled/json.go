@@ -0,0 +1,99 @@
+// Package wled implements a subset of the WLED JSON API on top of a
+// ledsgo.Runner, so that existing WLED apps and remotes can control a
+// ledsgo-based firmware. Supported are the on/off state, brightness, effect,
+// palette, colors, speed and intensity. Only a single segment is supported.
+package wled
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// State is the state object of the JSON API, as used in /json/state. All
+// fields are optional when updating the state.
+type State struct {
+	On         *Switch   `json:"on,omitempty"`
+	Brightness *uint8    `json:"bri,omitempty"`
+	Transition *int      `json:"transition,omitempty"`
+	Segments   []Segment `json:"seg,omitempty"`
+
+	// Verbose requests the full state in the response to an update.
+	Verbose bool `json:"v,omitempty"`
+}
+
+// Segment is a segment object in the state. All fields are optional when
+// updating the state.
+type Segment struct {
+	ID        int     `json:"id"`
+	Start     *int    `json:"start,omitempty"`
+	Stop      *int    `json:"stop,omitempty"`
+	Length    *int    `json:"len,omitempty"`
+	On        *Switch `json:"on,omitempty"`
+	Effect    *int    `json:"fx,omitempty"`
+	Speed     *uint8  `json:"sx,omitempty"`
+	Intensity *uint8  `json:"ix,omitempty"`
+	Custom1   *uint8  `json:"c1,omitempty"`
+	Custom2   *uint8  `json:"c2,omitempty"`
+	Custom3   *uint8  `json:"c3,omitempty"`
+	Palette   *int    `json:"pal,omitempty"`
+	Colors    [][]int `json:"col,omitempty"`
+	Selected  *bool   `json:"sel,omitempty"`
+}
+
+// Switch is an on/off value. Apart from true and false, WLED also accepts the
+// string "t" to toggle the current value.
+type Switch struct {
+	On     bool
+	Toggle bool
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Switch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.On)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Switch) UnmarshalJSON(data []byte) error {
+	var str string
+	if json.Unmarshal(data, &str) == nil {
+		if str != "t" {
+			return errors.New("wled: invalid switch value")
+		}
+		*s = Switch{Toggle: true}
+		return nil
+	}
+	*s = Switch{}
+	return json.Unmarshal(data, &s.On)
+}
+
+// apply returns the new value of a boolean after applying the switch.
+func (s *Switch) apply(value bool) bool {
+	if s == nil {
+		return value
+	}
+	if s.Toggle {
+		return !value
+	}
+	return s.On
+}
+
+// Info is the info object of the JSON API, as used in /json/info.
+type Info struct {
+	Version      string   `json:"ver"`
+	VersionID    int      `json:"vid"`
+	LEDs         LEDsInfo `json:"leds"`
+	Name         string   `json:"name"`
+	EffectCount  int      `json:"fxcount"`
+	PaletteCount int      `json:"palcount"`
+	Brand        string   `json:"brand"`
+	Product      string   `json:"product"`
+	Arch         string   `json:"arch"`
+	MAC          string   `json:"mac"`
+}
+
+// LEDsInfo is the LED information in the info object.
+type LEDsInfo struct {
+	Count int  `json:"count"`
+	RGBW  bool `json:"rgbw"`
+	FPS   int  `json:"fps"`
+}
@@ -0,0 +1,354 @@
+package wled
+
+import (
+	"encoding/json"
+	"image/color"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Version is the WLED version reported by the API. Apps use it to determine
+// which features are available.
+const Version = "0.14.0"
+
+// VersionID is the build number belonging to Version.
+const VersionID = 2310130
+
+// Effect is an effect that can be selected through the API.
+type Effect struct {
+	Name string
+
+	// New returns a new instance of the effect with the given parameters. It
+	// is called again whenever one of the parameters changes. The speed,
+	// intensity and palette are also set as the standard parameters of the
+	// runner, so effects that implement ledsgo.StandardEffect get them as
+	// well.
+	New func(params Params) ledsgo.Effect
+}
+
+// Params are the effect parameters that can be changed through the API.
+type Params struct {
+	Palette   *ledsgo.Palette16
+	Colors    [3]color.RGBA // primary, secondary and tertiary color
+	Speed     uint8
+	Intensity uint8
+}
+
+// Palette is a palette that can be selected through the API.
+type Palette struct {
+	Name    string
+	Palette *ledsgo.Palette16
+}
+
+// DefaultPalettes are the palettes used when no palettes are configured.
+var DefaultPalettes = []Palette{
+	{"Rainbow", &ledsgo.RainbowColors},
+	{"Party", &ledsgo.PartyColors},
+	{"Lava", &ledsgo.LavaColors},
+	{"Ocean", &ledsgo.OceanColors},
+	{"Forest", &ledsgo.ForestColors},
+	{"Cloud", &ledsgo.CloudColors},
+	{"Heat", &ledsgo.HeatColors},
+}
+
+// Server implements the WLED JSON API for a Runner. It is a http.Handler that
+// should be registered at the root of the web server, as apps expect the API
+// at /json.
+//
+// The three custom sliders of WLED change the first three parameters of
+// effects that implement ledsgo.ParamEffect, scaled to the range of the
+// parameter.
+type Server struct {
+	// Name is the name of the device shown in apps.
+	Name string
+
+	// Palettes are the palettes that can be selected. If nil,
+	// DefaultPalettes is used.
+	Palettes []Palette
+
+	runner  *ledsgo.Runner
+	effects []Effect
+
+	lock      sync.Mutex
+	on        bool // global on/off state
+	segmentOn bool // on/off state of the (single) segment
+	effect    int
+	palette   int
+	params    Params
+	custom    [3]*uint8 // custom sliders, nil for the default of the effect
+}
+
+// NewServer returns a new server for the runner, offering the given effects.
+// The first effect is started immediately.
+func NewServer(runner *ledsgo.Runner, effects []Effect) *Server {
+	s := &Server{
+		Name:      "ledsgo",
+		runner:    runner,
+		effects:   effects,
+		on:        runner.On(),
+		segmentOn: true,
+		params: Params{
+			Colors:    [3]color.RGBA{{255, 160, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}},
+			Speed:     128,
+			Intensity: 128,
+		},
+	}
+	s.lock.Lock()
+	s.updateEffect()
+	s.lock.Unlock()
+	return s
+}
+
+// palettes returns the configured palettes.
+func (s *Server) palettes() []Palette {
+	if s.Palettes == nil {
+		return DefaultPalettes
+	}
+	return s.Palettes
+}
+
+// State returns the current state.
+func (s *Server) State() State {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.state()
+}
+
+func (s *Server) state() State {
+	brightness := s.runner.Brightness()
+	start, stop := 0, s.runner.Len()
+	effect, palette := s.effect, s.palette
+	speed, intensity := s.params.Speed, s.params.Intensity
+	selected := true
+	var colors [][]int
+	for _, c := range s.params.Colors {
+		colors = append(colors, []int{int(c.R), int(c.G), int(c.B)})
+	}
+	transition := 0
+	return State{
+		On:         &Switch{On: s.on},
+		Brightness: &brightness,
+		Transition: &transition,
+		Segments: []Segment{{
+			Start:     &start,
+			Stop:      &stop,
+			Length:    &stop,
+			On:        &Switch{On: s.segmentOn},
+			Effect:    &effect,
+			Speed:     &speed,
+			Intensity: &intensity,
+			Palette:   &palette,
+			Custom1:   s.customSlider(0),
+			Custom2:   s.customSlider(1),
+			Custom3:   s.customSlider(2),
+			Colors:    colors,
+			Selected:  &selected,
+		}},
+	}
+}
+
+// customSlider returns the position of custom slider i, or nil if the effect
+// has no such parameter.
+func (s *Server) customSlider(i int) *uint8 {
+	params := s.runner.Params()
+	if i >= len(params) {
+		return nil
+	}
+	p := &params[i]
+	value, ok := s.runner.Param(p.Name)
+	if !ok {
+		return nil
+	}
+	var slider uint8
+	if span := int64(p.Max) - int64(p.Min); span > 0 {
+		slider = uint8(((int64(value)-int64(p.Min))*255 + span/2) / span)
+	}
+	return &slider
+}
+
+// applyCustom changes the parameter of custom slider i to the position of the
+// slider, if it was set.
+func (s *Server) applyCustom(i int) {
+	params := s.runner.Params()
+	if i >= len(params) || s.custom[i] == nil {
+		return
+	}
+	p := &params[i]
+	span := int64(p.Max) - int64(p.Min)
+	s.runner.SetParam(p.Name, int32(int64(p.Min)+(int64(*s.custom[i])*span+127)/255))
+}
+
+// SetState updates the state. Fields that are not set are left unchanged, as
+// are segments other than segment 0.
+func (s *Server) SetState(state State) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.on = state.On.apply(s.on)
+	if state.Brightness != nil {
+		if *state.Brightness == 0 {
+			// Like WLED, a brightness of zero switches the light off and
+			// keeps the brightness for when it is switched on again.
+			s.on = false
+		} else {
+			s.runner.SetBrightness(*state.Brightness)
+		}
+	}
+	changed := false
+	for _, seg := range state.Segments {
+		if seg.ID != 0 {
+			continue
+		}
+		s.segmentOn = seg.On.apply(s.segmentOn)
+		if seg.Effect != nil && *seg.Effect >= 0 && *seg.Effect < len(s.effects) && *seg.Effect != s.effect {
+			s.effect = *seg.Effect
+			s.custom = [3]*uint8{} // start with the defaults of the new effect
+			changed = true
+		}
+		if seg.Palette != nil && *seg.Palette >= 0 && *seg.Palette < len(s.palettes()) && *seg.Palette != s.palette {
+			s.palette = *seg.Palette
+			changed = true
+		}
+		if seg.Speed != nil && *seg.Speed != s.params.Speed {
+			s.params.Speed = *seg.Speed
+			changed = true
+		}
+		if seg.Intensity != nil && *seg.Intensity != s.params.Intensity {
+			s.params.Intensity = *seg.Intensity
+			changed = true
+		}
+		for i, c := range seg.Colors {
+			if i >= len(s.params.Colors) || len(c) < 3 {
+				continue
+			}
+			s.params.Colors[i] = color.RGBA{colorComponent(c[0]), colorComponent(c[1]), colorComponent(c[2]), 0}
+			changed = true
+		}
+		for i, slider := range [3]*uint8{seg.Custom1, seg.Custom2, seg.Custom3} {
+			if slider != nil {
+				value := *slider
+				s.custom[i] = &value
+				s.applyCustom(i)
+			}
+		}
+	}
+	s.runner.SetOn(s.on && s.segmentOn)
+	if changed {
+		s.updateEffect()
+	}
+}
+
+// colorComponent returns a color component of the JSON API, clamped to the
+// range of a color channel.
+func colorComponent(c int) uint8 {
+	return uint8(min(max(c, 0), 255))
+}
+
+// updateEffect (re)starts the current effect with the current parameters.
+func (s *Server) updateEffect() {
+	if s.effect >= len(s.effects) {
+		s.runner.SetEffect(nil)
+		return
+	}
+	params := s.params
+	if palettes := s.palettes(); s.palette < len(palettes) {
+		params.Palette = palettes[s.palette].Palette
+	} else {
+		params.Palette = &ledsgo.RainbowColors
+	}
+	s.runner.SetStandardParams(ledsgo.StandardParams{
+		Speed:     params.Speed,
+		Intensity: params.Intensity,
+		Palette:   params.Palette,
+	})
+	s.runner.SetEffect(s.effects[s.effect].New(params))
+	for i := range s.custom {
+		s.applyCustom(i)
+	}
+}
+
+// Info returns the info object.
+func (s *Server) Info() Info {
+	return Info{
+		Version:      Version,
+		VersionID:    VersionID,
+		LEDs:         LEDsInfo{Count: s.runner.Len()},
+		Name:         s.Name,
+		EffectCount:  len(s.effects),
+		PaletteCount: len(s.palettes()),
+		Brand:        "ledsgo",
+		Product:      "ledsgo",
+		Arch:         "ledsgo",
+	}
+}
+
+// effectNames returns the names of all effects.
+func (s *Server) effectNames() []string {
+	names := make([]string, len(s.effects))
+	for i, effect := range s.effects {
+		names[i] = effect.Name
+	}
+	return names
+}
+
+// paletteNames returns the names of all palettes.
+func (s *Server) paletteNames() []string {
+	palettes := s.palettes()
+	names := make([]string, len(palettes))
+	for i, palette := range palettes {
+		names[i] = palette.Name
+	}
+	return names
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if r.Method == http.MethodPost && (path == "/json" || path == "/json/state") {
+		var state State
+		if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": 9})
+			return
+		}
+		s.SetState(state)
+		if state.Verbose {
+			writeJSON(w, http.StatusOK, s.State())
+		} else {
+			writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+		}
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch path {
+	case "/json":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"state":    s.State(),
+			"info":     s.Info(),
+			"effects":  s.effectNames(),
+			"palettes": s.paletteNames(),
+		})
+	case "/json/state":
+		writeJSON(w, http.StatusOK, s.State())
+	case "/json/info":
+		writeJSON(w, http.StatusOK, s.Info())
+	case "/json/effects", "/json/eff":
+		writeJSON(w, http.StatusOK, s.effectNames())
+	case "/json/palettes", "/json/pal":
+		writeJSON(w, http.StatusOK, s.paletteNames())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeJSON writes v as the JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,137 @@
+package wled
+
+import (
+	"encoding/json"
+	"image/color"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aykevl/ledsgo"
+	"github.com/aykevl/ledsgo/internal/ledstest"
+)
+
+func TestServer(t *testing.T) {
+	var display ledstest.FrameRecorder
+	runner := ledsgo.NewRunner(&display, 10)
+	solid := func(params Params) ledsgo.Effect {
+		return ledsgo.EffectFunc(func(frame ledsgo.Strip, t time.Duration) {
+			frame.FillSolid(params.Colors[0])
+		})
+	}
+	server := NewServer(runner, []Effect{
+		{Name: "Solid", New: solid},
+		{Name: "Palette", New: func(params Params) ledsgo.Effect {
+			return ledsgo.EffectFunc(func(frame ledsgo.Strip, t time.Duration) {
+				frame.FillSolid(params.Palette.ColorAt(0))
+			})
+		}},
+	})
+
+	post := func(body string) string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("POST", "/json/state", strings.NewReader(body)))
+		if w.Code != 200 {
+			t.Fatalf("unexpected status %d for %s", w.Code, body)
+		}
+		return w.Body.String()
+	}
+
+	post(`{"bri":255,"seg":[{"col":[[1,2,3]]}]}`)
+	runner.Frame()
+	if display.Last()[0] != (color.RGBA{1, 2, 3, 0}) {
+		t.Errorf("unexpected color after setting the primary color: %v", display.Last()[0])
+	}
+
+	post(`{"seg":[{"fx":1,"pal":3}]}`)
+	runner.Frame()
+	if display.Last()[0] != ledsgo.OceanColors[0] {
+		t.Errorf("unexpected color after setting the effect and palette: %v", display.Last()[0])
+	}
+
+	post(`{"on":"t"}`)
+	if runner.On() {
+		t.Error("expected the runner to be switched off after a toggle")
+	}
+
+	var state State
+	if err := json.Unmarshal([]byte(post(`{"on":true,"bri":10,"v":true}`)), &state); err != nil {
+		t.Fatal(err)
+	}
+	if !state.On.On || *state.Brightness != 10 || *state.Segments[0].Effect != 1 || *state.Segments[0].Palette != 3 {
+		t.Errorf("unexpected state: %+v", state)
+	}
+
+	// A brightness of zero switches the light off, but keeps the brightness.
+	post(`{"bri":0}`)
+	if runner.On() || runner.Brightness() != 10 {
+		t.Errorf("bri 0: expected the light to be off at brightness 10, got %v and %d", runner.On(), runner.Brightness())
+	}
+	post(`{"on":true}`)
+	if !runner.On() || runner.Brightness() != 10 {
+		t.Errorf("on: expected the light to be on at brightness 10, got %v and %d", runner.On(), runner.Brightness())
+	}
+
+	// Color components outside of the range of a channel are clamped.
+	post(`{"bri":255,"seg":[{"fx":0,"col":[[300,-5,256]]}]}`)
+	runner.Frame()
+	if display.Last()[0] != (color.RGBA{255, 0, 255, 0}) {
+		t.Errorf("unexpected color after setting an out-of-range color: %v", display.Last()[0])
+	}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/json/effects", nil))
+	if body := strings.TrimSpace(w.Body.String()); body != `["Solid","Palette"]` {
+		t.Errorf("unexpected effect list: %s", body)
+	}
+}
+
+func TestServerCustomSliders(t *testing.T) {
+	runner := ledsgo.NewRunner(&ledstest.FrameRecorder{}, 8*8)
+	server := NewServer(runner, []Effect{
+		{Name: "Fire", New: func(params Params) ledsgo.Effect { return ledsgo.NewNoiseFire(8, 8) }},
+		{Name: "Solid", New: func(params Params) ledsgo.Effect {
+			return ledsgo.EffectFunc(func(frame ledsgo.Strip, t time.Duration) {})
+		}},
+	})
+
+	// The custom sliders change the intensity and the wind of the fire, while
+	// frames are being rendered.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			runner.Frame()
+		}
+		close(done)
+	}()
+	server.SetState(State{Segments: []Segment{{Custom1: ptr[uint8](64), Custom2: ptr[uint8](255)}}})
+	<-done
+	if value, _ := runner.Param("Intensity"); value != 64 {
+		t.Errorf("expected intensity 64, got %d", value)
+	}
+	if value, _ := runner.Param("Wind"); value != 127 {
+		t.Errorf("expected wind 127, got %d", value)
+	}
+	seg := server.State().Segments[0]
+	if seg.Custom1 == nil || *seg.Custom1 != 64 || seg.Custom2 == nil || *seg.Custom2 != 255 || seg.Custom3 != nil {
+		t.Errorf("unexpected custom sliders in the state: %v %v %v", seg.Custom1, seg.Custom2, seg.Custom3)
+	}
+
+	// The sliders are kept when the effect is restarted with a new speed, and
+	// reset when switching effects.
+	server.SetState(State{Segments: []Segment{{Speed: ptr[uint8](10)}}})
+	if value, _ := runner.Param("Intensity"); value != 64 {
+		t.Errorf("expected intensity 64 after changing the speed, got %d", value)
+	}
+	server.SetState(State{Segments: []Segment{{Effect: ptr(1)}}})
+	server.SetState(State{Segments: []Segment{{Effect: ptr(0)}}})
+	if value, _ := runner.Param("Intensity"); value != 128 {
+		t.Errorf("expected the default intensity after switching effects, got %d", value)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
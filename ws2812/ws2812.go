@@ -0,0 +1,100 @@
+// Package ws2812 encodes frames for WS2812 (and compatible) LEDs, so that they
+// can be sent using a SPI peripheral, usually with DMA. This way the whole
+// frame is encoded before sending, and the CPU is free during transmission.
+//
+// Every data bit for the LEDs is expanded into 3 or 4 SPI bits. With 3 bits, a
+// zero is sent as 100 and a one as 110, which needs a SPI clock of about 2.4MHz.
+// With 4 bits, a zero is sent as 1000 and a one as 1110, which needs a SPI clock
+// of about 3.2MHz. The 4-bit encoding is more tolerant of clock inaccuracies.
+package ws2812
+
+import (
+	"io"
+
+	"github.com/aykevl/ledsgo"
+)
+
+// Number of zero bytes needed after a frame to latch the data (at least 280µs
+// low), at the SPI clock rates mentioned in the package documentation.
+const (
+	ResetBytes3 = 90
+	ResetBytes4 = 120
+)
+
+// SPI patterns for every nibble with the 4-bit encoding.
+var nibbles4 = [16]uint16{
+	0x8888, 0x888e, 0x88e8, 0x88ee, 0x8e88, 0x8e8e, 0x8ee8, 0x8eee,
+	0xe888, 0xe88e, 0xe8e8, 0xe8ee, 0xee88, 0xee8e, 0xeee8, 0xeeee,
+}
+
+// AppendBytes appends the raw byte stream for the frame to buf and returns the
+// resulting slice. Most WS2812 LEDs use the GRB channel order.
+func AppendBytes(buf []byte, frame ledsgo.Strip, order ledsgo.ColorOrder) []byte {
+	return order.AppendStrip(buf, frame)
+}
+
+// AppendSPI3 appends the 3-bit SPI encoding of the raw data to buf and returns
+// the resulting slice. Every byte of data results in 3 bytes of output.
+func AppendSPI3(buf []byte, data []byte) []byte {
+	for _, b := range data {
+		var bits uint32
+		for i := 0; i < 8; i++ {
+			bits <<= 3
+			if b&0x80 != 0 {
+				bits |= 0b110
+			} else {
+				bits |= 0b100
+			}
+			b <<= 1
+		}
+		buf = append(buf, byte(bits>>16), byte(bits>>8), byte(bits))
+	}
+	return buf
+}
+
+// AppendSPI4 appends the 4-bit SPI encoding of the raw data to buf and returns
+// the resulting slice. Every byte of data results in 4 bytes of output.
+func AppendSPI4(buf []byte, data []byte) []byte {
+	for _, b := range data {
+		hi := nibbles4[b>>4]
+		lo := nibbles4[b&0x0f]
+		buf = append(buf, byte(hi>>8), byte(hi), byte(lo>>8), byte(lo))
+	}
+	return buf
+}
+
+// SPIDisplayer is a ledsgo.Displayer that sends encoded frames to a SPI bus (or
+// any other io.Writer), including the reset bytes at the end of the frame.
+type SPIDisplayer struct {
+	W     io.Writer
+	Order ledsgo.ColorOrder
+
+	// Bits is the number of SPI bits per data bit: 3 or 4.
+	Bits int
+
+	data []byte
+	buf  []byte
+}
+
+// NewSPIDisplayer returns a new SPIDisplayer for GRB LEDs using the given
+// encoding (3 or 4 bits).
+func NewSPIDisplayer(w io.Writer, bits int) *SPIDisplayer {
+	return &SPIDisplayer{W: w, Order: ledsgo.OrderGRB, Bits: bits}
+}
+
+// Display encodes and writes the frame.
+func (d *SPIDisplayer) Display(frame ledsgo.Strip) error {
+	d.data = AppendBytes(d.data[:0], frame, d.Order)
+	reset := ResetBytes4
+	if d.Bits == 3 {
+		d.buf = AppendSPI3(d.buf[:0], d.data)
+		reset = ResetBytes3
+	} else {
+		d.buf = AppendSPI4(d.buf[:0], d.data)
+	}
+	for i := 0; i < reset; i++ {
+		d.buf = append(d.buf, 0)
+	}
+	_, err := d.W.Write(d.buf)
+	return err
+}
@@ -0,0 +1,18 @@
+package ws2812
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSPI(t *testing.T) {
+	data := []byte{0xa5}
+	// 1 0 1 0 0 1 0 1 → 110 100 110 100 100 110 100 110
+	if buf := AppendSPI3(nil, data); !bytes.Equal(buf, []byte{0b11010011, 0b01001001, 0b10100110}) {
+		t.Errorf("unexpected 3-bit encoding: %08b", buf)
+	}
+	// 1010 0101 → 1110 1000 1110 1000 1000 1110 1000 1110
+	if buf := AppendSPI4(nil, data); !bytes.Equal(buf, []byte{0xe8, 0xe8, 0x8e, 0x8e}) {
+		t.Errorf("unexpected 4-bit encoding: %x", buf)
+	}
+}